@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"sync"
@@ -10,13 +11,22 @@ import (
 
 	"insider-message-system/internal/application/services"
 	"insider-message-system/internal/application/usecases"
+	"insider-message-system/internal/infrastructure/archive"
 	"insider-message-system/internal/infrastructure/database"
 	"insider-message-system/internal/infrastructure/database/repos"
+	"insider-message-system/internal/infrastructure/election"
+	"insider-message-system/internal/infrastructure/notifier"
+	"insider-message-system/internal/infrastructure/outbox"
+	"insider-message-system/internal/infrastructure/ratelimit"
 	"insider-message-system/internal/infrastructure/redis"
 	"insider-message-system/internal/infrastructure/webhook"
 	"insider-message-system/internal/interfaces/http"
 	"insider-message-system/internal/interfaces/http/handlers"
 	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/constants/enums/databasedrivers"
+	"insider-message-system/pkg/constants/enums/electionbackends"
+	"insider-message-system/pkg/constants/enums/notifiertypes"
+	"insider-message-system/pkg/constants/enums/ratelimitbackends"
 	"insider-message-system/pkg/logger"
 
 	_ "insider-message-system/docs"
@@ -45,14 +55,39 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	db, err := database.NewConnection(cfg.Database)
-	if err != nil {
-		logger.Fatal("Failed to connect to database", zap.Error(err))
-	}
-	defer db.Close()
+	var db *database.DB
+	var messageRepo repos.Message
+	if cfg.Database.Driver == databasedrivers.Memory {
+		logger.Warn("Using the in-memory message store, messages will be lost on restart")
+		messageRepo = repos.NewMemoryMessage()
+
+		if cfg.Outbox.Enabled {
+			logger.Warn("The outbox requires a database and is disabled with the memory driver")
+			cfg.Outbox.Enabled = false
+		}
+		if cfg.Scheduler.PersistSettings {
+			logger.Warn("Persisting scheduler settings requires a database and is disabled with the memory driver")
+			cfg.Scheduler.PersistSettings = false
+		}
+		if cfg.Scheduler.PersistState {
+			logger.Warn("Persisting the scheduler state requires a database and is disabled with the memory driver")
+			cfg.Scheduler.PersistState = false
+		}
+	} else {
+		db, err = database.NewConnection(cfg.Database)
+		if err != nil {
+			logger.Fatal("Failed to connect to database", zap.Error(err))
+		}
+		defer db.Close()
+
+		if err := db.Migrate(); err != nil {
+			logger.Fatal("Failed to run database migrations", zap.Error(err))
+		}
 
-	if err := db.Migrate(); err != nil {
-		logger.Fatal("Failed to run database migrations", zap.Error(err))
+		messageRepo = repos.NewMessage(db)
+		if cfg.Outbox.Enabled {
+			messageRepo = repos.NewMessageWithOutbox(db)
+		}
 	}
 
 	var cacheService redis.CacheService
@@ -65,25 +100,153 @@ func main() {
 		}
 	}
 
-	messageRepo := repos.NewMessage(db)
-	webhookService := webhook.NewClient(cfg.Webhook, cfg.CircuitBreaker)
-	messageService := services.NewMessage(messageRepo, webhookService, cacheService)
+	providers := make([]webhook.Provider, 0, len(cfg.WebhookProviders()))
+	for _, providerCfg := range cfg.WebhookProviders() {
+		var rateLimiter ratelimit.Limiter
+		if providerCfg.RateLimit.Enabled {
+			rateLimiter, err = newRateLimiter(cfg, *providerCfg.RateLimit)
+			if err != nil {
+				logger.Fatal("Failed to set up webhook rate limiting", zap.String("provider", providerCfg.Name), zap.Error(err))
+			}
+			if redisLimiter, ok := rateLimiter.(ratelimit.Redis); ok {
+				defer redisLimiter.Close()
+			}
+			logger.Info("Webhook rate limiting enabled",
+				zap.String("provider", providerCfg.Name),
+				zap.String("backend", providerCfg.RateLimit.Backend.String()),
+				zap.Float64("rate", providerCfg.RateLimit.Rate),
+				zap.Int("burst", providerCfg.RateLimit.Burst))
+		}
+
+		webhookCfg := cfg.Webhook
+		webhookCfg.URL, webhookCfg.AuthKey, webhookCfg.Timeout = providerCfg.URL, providerCfg.AuthKey, providerCfg.Timeout
+		webhookCfg.Mapping = providerCfg.Mapping
+		webhookCfg.Auth = providerCfg.Auth
+		webhookCfg.TLS, webhookCfg.ProxyURL = providerCfg.TLS, providerCfg.ProxyURL
+		client, err := webhook.NewClientWithRateLimiter(webhookCfg, *providerCfg.CircuitBreaker, rateLimiter)
+		if err != nil {
+			logger.Fatal("Failed to create webhook client", zap.String("provider", providerCfg.Name), zap.Error(err))
+		}
+		providers = append(providers, webhook.Provider{
+			Name:     providerCfg.Name,
+			Client:   client,
+			Priority: providerCfg.Priority,
+			Weight:   providerCfg.Weight,
+			Prefixes: providerCfg.Prefixes,
+		})
+	}
+
+	webhookService, err := webhook.NewRouter(providers, cfg.Webhook.Routing)
+	if err != nil {
+		logger.Fatal("Failed to set up webhook providers", zap.Error(err))
+	}
+	if len(cfg.Webhook.Providers) > 0 {
+		logger.Info("Webhook providers configured",
+			zap.Int("providers", len(providers)),
+			zap.String("routing", cfg.Webhook.Routing.String()))
+	}
+	var wakeupNotifier notifier.Notifier
+	if cfg.Scheduler.Wakeup.Enabled {
+		wakeupNotifier = newWakeupNotifier(cfg)
+		defer wakeupNotifier.Close()
+	}
+
+	messageService := services.NewMessageWithNotifier(messageRepo, webhookService, cacheService, wakeupNotifier, cfg.Webhook.Retry)
+	statsService := services.NewStats(messageRepo)
 	schedulerService := services.NewScheduler(cfg.Scheduler)
 
-	processor := services.NewMessageProcessor(messageService, cfg.Scheduler.BatchSize)
+	processor := services.NewMessageProcessorWithCircuitBreaker(messageService, cfg.Scheduler.Concurrency, cfg.Scheduler.ClaimLease, cfg.Scheduler.DrainTimeout, webhookService)
 	schedulerService.SetMessageProcessor(processor)
+	if wakeupNotifier != nil {
+		schedulerService.SetNotifier(wakeupNotifier)
+	}
+	if cfg.Scheduler.LeaderElection.Enabled {
+		// Campaign with the ID the scheduler reports, which defaults to <hostname>-<pid>.
+		lock, err := newLeaderLock(cfg, db, cfg.Scheduler.LeaderElection.Key)
+		if err != nil {
+			logger.Fatal("Failed to set up scheduler leader election", zap.Error(err))
+		}
+		defer lock.Close()
+		instanceID := schedulerService.Status().InstanceID
+		if cfg.Scheduler.LeaderElection.Backend == electionbackends.Postgres && len(instanceID) > election.MaxPostgresIDLength {
+			logger.Fatal("Failed to set up scheduler leader election", zap.String("instance_id", instanceID), zap.Error(election.ErrIDTooLong))
+		}
+		schedulerService.SetElector(election.New(lock, instanceID, cfg.Scheduler.LeaderElection.RenewInterval))
+		logger.Info("Scheduler leader election enabled",
+			zap.String("backend", cfg.Scheduler.LeaderElection.Backend.String()),
+			zap.String("instance_id", instanceID))
+	}
+	if cfg.Scheduler.AdaptiveBatch.Enabled {
+		controller, err := services.NewBatchController(cfg.Scheduler.AdaptiveBatch, cfg.Scheduler.BatchSize, messageRepo)
+		if err != nil {
+			logger.Fatal("Failed to set up adaptive batch sizing", zap.Error(err))
+		}
+		schedulerService.SetBatchController(controller)
+		logger.Info("Adaptive batch sizing enabled",
+			zap.Int("min_batch_size", cfg.Scheduler.AdaptiveBatch.MinBatchSize),
+			zap.Int("max_batch_size", cfg.Scheduler.AdaptiveBatch.MaxBatchSize),
+			zap.Duration("target_latency", cfg.Scheduler.AdaptiveBatch.TargetLatency))
+	}
+	if cfg.Scheduler.PersistSettings {
+		schedulerService.SetSettingsRepository(repos.NewSchedulerSettings(db))
+		if err := schedulerService.LoadSettings(ctx); err != nil {
+			logger.Error("Failed to load persisted scheduler settings", zap.Error(err))
+		}
+	}
+	if cfg.Scheduler.PersistState {
+		schedulerService.SetStateRepository(repos.NewSchedulerState(db))
+	}
+
+	jobRunner := services.NewJobRunner()
+	if cfg.Scheduler.LeaderElection.Enabled {
+		// Leader-only jobs campaign for a lock of their own, so they keep a leader
+		// while the scheduler is stopped.
+		jobsLock, err := newLeaderLock(cfg, db, cfg.Scheduler.LeaderElection.Key+":jobs")
+		if err != nil {
+			logger.Fatal("Failed to set up background job leader election", zap.Error(err))
+		}
+		defer jobsLock.Close()
+		jobRunner.SetElector(election.New(jobsLock, schedulerService.Status().InstanceID, cfg.Scheduler.LeaderElection.RenewInterval))
+	}
+	if cfg.Retention.Enabled {
+		retention := services.NewRetention(cfg.Retention, messageRepo, archive.NewFileArchiver(cfg.Retention.ArchiveDir))
+		// Replicas sweeping the same rows would archive them more than once.
+		registerJob(jobRunner, "retention", cfg.Retention.Interval, cfg.Jobs.Retention, true, func(ctx context.Context) error {
+			_, err := retention.RunOnce(ctx)
+			return err
+		})
+	}
+	if cfg.Outbox.Enabled {
+		sinks, err := outbox.NewSinks(cfg.Outbox.Sinks)
+		if err != nil {
+			logger.Fatal("Failed to configure outbox sinks", zap.Error(err))
+		}
+		outboxRelay := services.NewOutboxRelay(cfg.Outbox, repos.NewOutbox(db), sinks)
+		// Replicas relaying the same rows would deliver events twice and out of order.
+		registerJob(jobRunner, "outbox_relay", cfg.Outbox.PollInterval, cfg.Jobs.OutboxRelay, true, func(ctx context.Context) error {
+			_, err := outboxRelay.RunOnce(ctx)
+			return err
+		})
+	}
 
 	sendMessageUC := usecases.NewSendMessageUseCase(messageService)
 	getMessagesUC := usecases.NewGetMessagesUseCase(messageService)
 	controlSchedulerUC := usecases.NewControlSchedulerUseCase(schedulerService)
+	getStatsUC := usecases.NewGetStatsUseCase(statsService)
+	controlJobsUC := usecases.NewControlJobsUseCase(jobRunner)
 
 	messageHandler := handlers.NewMessageHandler(sendMessageUC, getMessagesUC)
 	schedulerHandler := handlers.NewSchedulerHandler(controlSchedulerUC)
+	statsHandler := handlers.NewStatsHandler(getStatsUC)
+	jobHandler := handlers.NewJobHandler(controlJobsUC)
 
 	routeConfig := http.RouteConfig{
 		MessageHandler:   messageHandler,
 		SchedulerHandler: schedulerHandler,
+		StatsHandler:     statsHandler,
+		JobHandler:       jobHandler,
 		WebhookClient:    webhookService,
+		Database:         db,
 		AuthKey:          cfg.Webhook.AuthKey,
 	}
 
@@ -100,7 +263,19 @@ func main() {
 		}
 	}()
 
-	if cfg.Scheduler.AutoStart {
+	if err := jobRunner.Start(ctx); err != nil {
+		logger.Error("Failed to start background jobs", zap.Error(err))
+	}
+
+	// With a persisted state, scheduler.auto_start only seeds the state on first
+	// start, and the reconciler starts or stops this instance to follow it.
+	var reconciler services.SchedulerReconciler
+	if cfg.Scheduler.PersistState {
+		reconciler = services.NewSchedulerReconciler(schedulerService, cfg.Scheduler.ReconcileInterval)
+		if err := reconciler.Start(ctx); err != nil {
+			logger.Error("Failed to start scheduler reconciler", zap.Error(err))
+		}
+	} else if cfg.Scheduler.AutoStart {
 		logger.Info("Auto-starting scheduler")
 		if err := schedulerService.Start(ctx); err != nil {
 			logger.Error("Failed to auto-start scheduler", zap.Error(err))
@@ -122,15 +297,30 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
 
-	if schedulerService.IsRunning() {
-		logger.Info("Stopping scheduler...")
-		if err := schedulerService.Stop(); err != nil {
-			logger.Error("Failed to stop scheduler", zap.Error(err))
+	// Shut down from the outside in: stop taking requests, so that no new messages
+	// or manual runs come in, then drain the scheduler while the database, cache
+	// and webhook client are still available. Those are closed by the deferred
+	// calls once main returns.
+	if err := server.Stop(shutdownCtx); err != nil {
+		logger.Error("Failed to stop server", zap.Error(err))
+	}
+
+	// Stop reconciling first, so that the scheduler is not restarted while it drains.
+	if reconciler != nil {
+		if err := reconciler.Stop(); err != nil {
+			logger.Error("Failed to stop scheduler reconciler", zap.Error(err))
 		}
 	}
 
-	if err := server.Stop(shutdownCtx); err != nil {
-		logger.Error("Failed to stop server", zap.Error(err))
+	// Stop also drains and waits for a manual run, so it is called even when the
+	// periodic scheduler is not running.
+	logger.Info("Stopping scheduler...")
+	if err := schedulerService.Stop(); err != nil {
+		logger.Error("Failed to stop scheduler", zap.Error(err))
+	}
+
+	if err := jobRunner.Stop(); err != nil {
+		logger.Error("Failed to stop background jobs", zap.Error(err))
 	}
 
 	cancel()
@@ -138,3 +328,90 @@ func main() {
 
 	logger.Info("Application shutdown complete")
 }
+
+// registerJob adds a periodic job with the run policy from its jobs.<name> settings.
+func registerJob(runner services.JobRunner, name string, interval time.Duration, cfg config.JobConfig, leaderOnly bool, run func(ctx context.Context) error) {
+	err := runner.Register(services.Job{
+		Name:        name,
+		Run:         run,
+		Interval:    interval,
+		Jitter:      cfg.Jitter,
+		Overlap:     cfg.Overlap,
+		Timeout:     cfg.Timeout,
+		MaxAttempts: cfg.MaxAttempts,
+		RetryDelay:  cfg.RetryDelay,
+		LeaderOnly:  leaderOnly,
+	})
+	if err != nil {
+		logger.Fatal("Failed to register background job", zap.String("job", name), zap.Error(err))
+	}
+}
+
+// newLeaderLock builds the lock replicas campaign for under key. Unlike the wake-up
+// notifier it has no fallback: without a shared lock every replica would lead.
+func newLeaderLock(cfg *config.Config, db *database.DB, key string) (election.Lock, error) {
+	le := cfg.Scheduler.LeaderElection
+	if le.RenewInterval <= 0 || (le.Backend == electionbackends.Redis && le.RenewInterval >= le.TTL) {
+		return nil, fmt.Errorf("scheduler.leader_election.renew_interval must be positive and shorter than ttl")
+	}
+
+	switch le.Backend {
+	case electionbackends.Redis:
+		return election.NewRedis(cfg.Redis, key, le.TTL)
+	case electionbackends.Postgres:
+		if db == nil || cfg.Database.Driver != databasedrivers.Postgres {
+			return nil, fmt.Errorf("the postgres leader election backend requires the postgres database driver")
+		}
+		sqlDB, err := db.DB.DB()
+		if err != nil {
+			return nil, err
+		}
+		return election.NewPostgres(sqlDB, key), nil
+	default:
+		return nil, fmt.Errorf("unknown leader election backend %q", le.Backend)
+	}
+}
+
+// newRateLimiter builds a provider's send limiter. A Redis limiter that cannot
+// connect at startup is replaced by a local one at the fallback rate, the same
+// rate it would use had Redis become unreachable later.
+func newRateLimiter(cfg *config.Config, rl config.RateLimitConfig) (ratelimit.Limiter, error) {
+	if rl.Rate <= 0 || rl.Burst < 1 || rl.FallbackRate < 0 {
+		return nil, fmt.Errorf("webhook.rate_limit.rate and burst must be positive and fallback_rate must not be negative")
+	}
+
+	fallbackRate := rl.FallbackRate
+	if fallbackRate == 0 {
+		fallbackRate = rl.Rate
+	}
+
+	switch rl.Backend {
+	case ratelimitbackends.Local:
+		return ratelimit.NewLocal(rl.Rate, rl.Burst, rl.MaxWait), nil
+	case ratelimitbackends.Redis:
+		limiter, err := ratelimit.NewRedis(cfg.Redis, rl.Key, rl.Rate, rl.Burst, fallbackRate, rl.MaxWait)
+		if err == nil {
+			return limiter, nil
+		}
+		logger.Warn("Failed to start Redis rate limiter, using local limiter",
+			zap.Float64("fallback_rate", fallbackRate),
+			zap.Error(err))
+		return ratelimit.NewLocal(fallbackRate, rl.Burst, rl.MaxWait), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend %q", rl.Backend)
+	}
+}
+
+// newWakeupNotifier builds the scheduler wake-up notifier, falling back to an
+// in-process notifier when Redis pub/sub is configured but unavailable.
+func newWakeupNotifier(cfg *config.Config) notifier.Notifier {
+	if cfg.Scheduler.Wakeup.Backend == notifiertypes.Redis {
+		redisNotifier, err := notifier.NewRedis(cfg.Redis, cfg.Scheduler.Wakeup.Channel)
+		if err == nil {
+			return redisNotifier
+		}
+		logger.Warn("Failed to start Redis wake-up notifier, using in-process notifier", zap.Error(err))
+	}
+
+	return notifier.NewLocal()
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"insider-message-system/internal/infrastructure/archive"
+	"insider-message-system/internal/infrastructure/database"
+	"insider-message-system/internal/infrastructure/database/repos"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/logger"
+	"os"
+
+	"go.uber.org/zap"
+)
+
+// restore loads archive files produced by the retention job back into the messages table.
+//
+// Usage:
+//
+//	go run ./cmd/restore ./archive/messages-sent-20240115T103000.000000000Z.ndjson.gz [more files...]
+//
+// Messages that already exist are skipped, so restoring the same file twice is safe.
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s <archive file>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		panic("Failed to load configuration: " + err.Error())
+	}
+
+	if err := logger.Init(cfg.Logger); err != nil {
+		panic("Failed to initialize logger: " + err.Error())
+	}
+	defer logger.Sync()
+
+	db, err := database.NewConnection(cfg.Database)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer db.Close()
+
+	if err := db.Migrate(); err != nil {
+		logger.Fatal("Failed to run database migrations", zap.Error(err))
+	}
+
+	restored, err := restoreFiles(context.Background(), repos.NewMessage(db), flag.Args())
+	if err != nil {
+		logger.Error("Restore failed", zap.Error(err), zap.Int64("restored", restored))
+		os.Exit(1)
+	}
+
+	logger.Info("Restore completed", zap.Int64("restored", restored))
+}
+
+func restoreFiles(ctx context.Context, messageRepo repos.Message, paths []string) (int64, error) {
+	var total int64
+
+	for _, path := range paths {
+		messages, err := archive.ReadFile(path)
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", path, err)
+		}
+
+		restored, err := messageRepo.Restore(ctx, messages)
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", path, err)
+		}
+		total += restored
+
+		logger.Info("Archive restored",
+			zap.String("path", path),
+			zap.Int("messages", len(messages)),
+			zap.Int64("restored", restored))
+	}
+
+	return total, nil
+}
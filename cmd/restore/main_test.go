@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/archive"
+	"insider-message-system/internal/infrastructure/database"
+	"insider-message-system/internal/infrastructure/database/repos"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRestoreFiles(t *testing.T) {
+	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&domain.Message{}))
+	repo := repos.NewMessage(&database.DB{DB: gdb})
+
+	messages := []*domain.Message{
+		{ID: uuid.New(), To: "+905551111111", Content: "one", Status: messagestatus.Sent, CreatedAt: time.Now()},
+		{ID: uuid.New(), To: "+905551111111", Content: "two", Status: messagestatus.Sent, CreatedAt: time.Now()},
+	}
+	path, err := archive.NewFileArchiver(t.TempDir()).Write(messagestatus.Sent, messages)
+	require.NoError(t, err)
+
+	restored, err := restoreFiles(context.Background(), repo, []string{path})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), restored)
+
+	restored, err = restoreFiles(context.Background(), repo, []string{path})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), restored)
+
+	_, err = restoreFiles(context.Background(), repo, []string{filepath.Join(t.TempDir(), "missing.ndjson.gz")})
+	assert.Error(t, err)
+}
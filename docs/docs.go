@@ -15,6 +15,158 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/v1/jobs": {
+            "get": {
+                "description": "Status, run policy and recent activity of every background job on this instance, ordered by name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "List background jobs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.JobListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jobs/{name}": {
+            "get": {
+                "description": "Status, run policy, next due run, last run and cumulative counters of a background job on this instance",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Get background job status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "retention",
+                        "description": "Job name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.JobStatusResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jobs/{name}/start": {
+            "post": {
+                "description": "Start running a background job periodically on this instance. The first run is due one interval, plus jitter, from now.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Start a background job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "retention",
+                        "description": "Job name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.JobStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jobs/{name}/stop": {
+            "post": {
+                "description": "Stop running a background job on this instance. A run in progress is cancelled and waited for before responding.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Stop a background job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "retention",
+                        "description": "Job name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.JobStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/messages": {
             "post": {
                 "description": "Add a new message to the queue for sending",
@@ -112,9 +264,133 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/scheduler/config": {
+            "get": {
+                "description": "Get the interval, batch size and retry settings the scheduler is currently using",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduler"
+                ],
+                "summary": "Get scheduler configuration",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.SchedulerConfigResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Change the interval, batch size or retry settings of the running scheduler. Omitted fields keep their current value. A new interval resets the ticker, and the other settings apply from the next cycle.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduler"
+                ],
+                "summary": "Update scheduler configuration",
+                "parameters": [
+                    {
+                        "description": "Settings to change",
+                        "name": "config",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/usecases.UpdateSchedulerConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.SchedulerConfigResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/scheduler/run": {
+            "post": {
+                "description": "Send pending messages right now, without starting the periodic scheduler. Synchronous runs return what was processed; async runs return 202 as soon as the cycle has started. Refused with 409 while another cycle is in progress.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduler"
+                ],
+                "summary": "Run one processing cycle",
+                "parameters": [
+                    {
+                        "description": "Batch size override and mode",
+                        "name": "run",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/usecases.RunSchedulerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.SchedulerRunResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.SchedulerRunResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/scheduler/start": {
             "post": {
-                "description": "Start automatic message sending process",
+                "description": "Start automatic message sending process. The new state is persisted and every instance follows it; changed_by defaults to the caller's IP address.",
                 "consumes": [
                     "application/json"
                 ],
@@ -125,6 +401,16 @@ const docTemplate = `{
                     "scheduler"
                 ],
                 "summary": "Start the message scheduler",
+                "parameters": [
+                    {
+                        "description": "Who changes the state and why",
+                        "name": "change",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/usecases.ChangeSchedulerStateRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -149,7 +435,7 @@ const docTemplate = `{
         },
         "/v1/scheduler/status": {
             "get": {
-                "description": "Get current status of the message scheduler",
+                "description": "Get current status of the message scheduler, its next scheduled tick, uptime, recent cycles (newest first) and cumulative counters",
                 "consumes": [
                     "application/json"
                 ],
@@ -164,7 +450,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/apidocs.SchedulerResponse"
+                            "$ref": "#/definitions/apidocs.SchedulerStatusResponse"
                         }
                     }
                 }
@@ -172,7 +458,7 @@ const docTemplate = `{
         },
         "/v1/scheduler/stop": {
             "post": {
-                "description": "Stop automatic message sending process",
+                "description": "Stop automatic message sending process. The new state is persisted and every instance follows it; changed_by defaults to the caller's IP address.",
                 "consumes": [
                     "application/json"
                 ],
@@ -183,6 +469,16 @@ const docTemplate = `{
                     "scheduler"
                 ],
                 "summary": "Stop the message scheduler",
+                "parameters": [
+                    {
+                        "description": "Who changes the state and why",
+                        "name": "change",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/usecases.ChangeSchedulerStateRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -204,40 +500,315 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "apidocs.ErrorData": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "string",
-                    "example": "VALIDATION_ERROR"
-                },
-                "details": {
-                    "type": "string",
-                    "example": "Invalid request parameters"
-                }
-            }
-        },
-        "apidocs.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "$ref": "#/definitions/apidocs.ErrorData"
-                },
-                "msg": {
-                    "type": "string",
-                    "example": "Validation failed"
-                },
-                "status": {
-                    "type": "boolean",
-                    "example": false
-                }
-            }
         },
-        "apidocs.MessageCreatedResponse": {
-            "type": "object",
+        "/v1/stats": {
+            "get": {
+                "description": "Message counts per status, a per-hour or per-day time series, and queue-to-send latency percentiles for a date range. Each message is counted when it entered its status: sent_at for sent, failed_at for failed and created_at for pending.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stats"
+                ],
+                "summary": "Get delivery statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Range start (RFC3339), defaults to one day or one week before 'to'",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Range end (RFC3339), defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "hour",
+                            "day"
+                        ],
+                        "type": "string",
+                        "default": "hour",
+                        "description": "Bucket size",
+                        "name": "interval",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.StatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/apidocs.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apidocs.BatchResultData": {
+            "type": "object",
+            "properties": {
+                "deferred": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "fetched": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "sent": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "skipped": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "unsent": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "apidocs.BatchSizingData": {
+            "type": "object",
+            "properties": {
+                "backlog": {
+                    "type": "integer",
+                    "example": 340
+                },
+                "batch_size": {
+                    "type": "integer",
+                    "example": 40
+                },
+                "decided_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:00:00Z"
+                },
+                "decision": {
+                    "type": "string",
+                    "example": "increase"
+                },
+                "error_rate": {
+                    "type": "number",
+                    "example": 0
+                },
+                "latency_ms": {
+                    "type": "integer",
+                    "example": 180
+                },
+                "max_batch_size": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "min_batch_size": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "backlog of 340 exceeds batch size 20"
+                }
+            }
+        },
+        "apidocs.ErrorData": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "VALIDATION_ERROR"
+                },
+                "details": {
+                    "type": "string",
+                    "example": "Invalid request parameters"
+                }
+            }
+        },
+        "apidocs.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/apidocs.ErrorData"
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "Validation failed"
+                },
+                "status": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "apidocs.JobListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apidocs.JobStatusData"
+                    }
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "Request processed successfully"
+                },
+                "status": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "apidocs.JobRunData": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 840
+                },
+                "error": {
+                    "type": "string",
+                    "example": "context deadline exceeded"
+                },
+                "finished_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:00:01Z"
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:00:00Z"
+                }
+            }
+        },
+        "apidocs.JobStatusData": {
+            "type": "object",
+            "properties": {
+                "failures": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "in_flight": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "interval": {
+                    "type": "string",
+                    "example": "1h0m0s"
+                },
+                "jitter": {
+                    "type": "string",
+                    "example": "5m0s"
+                },
+                "last_run": {
+                    "$ref": "#/definitions/apidocs.JobRunData"
+                },
+                "leader_only": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "max_attempts": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "name": {
+                    "type": "string",
+                    "example": "retention"
+                },
+                "next_run_at": {
+                    "type": "string",
+                    "example": "2024-01-15T11:00:00Z"
+                },
+                "overlap": {
+                    "type": "string",
+                    "example": "skip"
+                },
+                "retry_delay": {
+                    "type": "string",
+                    "example": "1m0s"
+                },
+                "runs": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "skipped": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "status": {
+                    "type": "string",
+                    "example": "running"
+                },
+                "timeout": {
+                    "type": "string",
+                    "example": "30m0s"
+                }
+            }
+        },
+        "apidocs.JobStatusResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/apidocs.JobStatusData"
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "Request processed successfully"
+                },
+                "status": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "apidocs.LatencyData": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "p50_ms": {
+                    "type": "number",
+                    "example": 61000
+                },
+                "p95_ms": {
+                    "type": "number",
+                    "example": 118500
+                },
+                "p99_ms": {
+                    "type": "number",
+                    "example": 120000
+                }
+            }
+        },
+        "apidocs.MessageCreatedResponse": {
+            "type": "object",
             "properties": {
                 "data": {
                     "$ref": "#/definitions/apidocs.MessageData"
@@ -263,6 +834,17 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2024-01-15T10:30:00Z"
                 },
+                "failed_at": {
+                    "type": "string"
+                },
+                "failed_attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "failure_code": {
+                    "type": "string",
+                    "example": "transient"
+                },
                 "failure_reason": {
                     "type": "string"
                 },
@@ -274,6 +856,10 @@ const docTemplate = `{
                     "type": "string",
                     "example": "msg_123456"
                 },
+                "provider": {
+                    "type": "string",
+                    "example": "primary"
+                },
                 "sent_at": {
                     "type": "string",
                     "example": "2024-01-15T10:35:00Z"
@@ -318,6 +904,102 @@ const docTemplate = `{
                 }
             }
         },
+        "apidocs.SchedulerConfigData": {
+            "type": "object",
+            "properties": {
+                "batch_size": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "interval": {
+                    "type": "string",
+                    "example": "2m0s"
+                },
+                "max_retries": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "retry_delay": {
+                    "type": "string",
+                    "example": "5s"
+                }
+            }
+        },
+        "apidocs.SchedulerConfigResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/apidocs.SchedulerConfigData"
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "Request processed successfully"
+                },
+                "status": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "apidocs.SchedulerCycleData": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "batch_size": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "deferred": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 840
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "fetched": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "finished_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:00:01Z"
+                },
+                "sent": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "skipped": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:00:00Z"
+                },
+                "trigger": {
+                    "type": "string",
+                    "example": "tick"
+                },
+                "unsent": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
         "apidocs.SchedulerData": {
             "type": "object",
             "properties": {
@@ -331,6 +1013,27 @@ const docTemplate = `{
                 }
             }
         },
+        "apidocs.SchedulerDesiredStateData": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string",
+                    "example": "2024-01-15T09:00:00Z"
+                },
+                "changed_by": {
+                    "type": "string",
+                    "example": "ops@example.com"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "maintenance over"
+                },
+                "running": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
         "apidocs.SchedulerResponse": {
             "type": "object",
             "properties": {
@@ -347,6 +1050,209 @@ const docTemplate = `{
                 }
             }
         },
+        "apidocs.SchedulerRunData": {
+            "type": "object",
+            "properties": {
+                "batch_size": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 840
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "sync"
+                },
+                "result": {
+                    "$ref": "#/definitions/apidocs.BatchResultData"
+                }
+            }
+        },
+        "apidocs.SchedulerRunResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/apidocs.SchedulerRunData"
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "Request processed successfully"
+                },
+                "status": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "apidocs.SchedulerStatusData": {
+            "type": "object",
+            "properties": {
+                "batch_sizing": {
+                    "$ref": "#/definitions/apidocs.BatchSizingData"
+                },
+                "cycles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apidocs.SchedulerCycleData"
+                    }
+                },
+                "desired_state": {
+                    "$ref": "#/definitions/apidocs.SchedulerDesiredStateData"
+                },
+                "instance_id": {
+                    "type": "string",
+                    "example": "api-7f9c-1"
+                },
+                "last_cycle": {
+                    "$ref": "#/definitions/apidocs.SchedulerCycleData"
+                },
+                "leader_id": {
+                    "type": "string",
+                    "example": "api-7f9c-1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Scheduler is currently running"
+                },
+                "next_run_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:02:00Z"
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "2024-01-15T09:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "running"
+                },
+                "totals": {
+                    "$ref": "#/definitions/apidocs.SchedulerTotalsData"
+                },
+                "uptime_seconds": {
+                    "type": "integer",
+                    "example": 3600
+                }
+            }
+        },
+        "apidocs.SchedulerStatusResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/apidocs.SchedulerStatusData"
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "Request processed successfully"
+                },
+                "status": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "apidocs.SchedulerTotalsData": {
+            "type": "object",
+            "properties": {
+                "cycles": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "deferred": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "failed_cycles": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "fetched": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "sent": {
+                    "type": "integer",
+                    "example": 77
+                },
+                "skipped": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "unsent": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "apidocs.StatsData": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "2024-01-14T00:00:00Z"
+                },
+                "interval": {
+                    "type": "string",
+                    "example": "hour"
+                },
+                "latency": {
+                    "$ref": "#/definitions/apidocs.LatencyData"
+                },
+                "time_series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/apidocs.TimeSeriesPoint"
+                    }
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2024-01-15T00:00:00Z"
+                },
+                "totals": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "apidocs.StatsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/apidocs.StatsData"
+                },
+                "msg": {
+                    "type": "string",
+                    "example": "Request processed successfully"
+                },
+                "status": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "apidocs.TimeSeriesPoint": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string",
+                    "example": "2024-01-14T10:00:00Z"
+                },
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
         "domain.MessageRequest": {
             "type": "object",
             "required": [
@@ -381,6 +1287,55 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "usecases.ChangeSchedulerStateRequest": {
+            "type": "object",
+            "properties": {
+                "changed_by": {
+                    "type": "string",
+                    "example": "ops@example.com"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "provider maintenance"
+                }
+            }
+        },
+        "usecases.RunSchedulerRequest": {
+            "type": "object",
+            "properties": {
+                "async": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "batch_size": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1,
+                    "example": 100
+                }
+            }
+        },
+        "usecases.UpdateSchedulerConfigRequest": {
+            "type": "object",
+            "properties": {
+                "batch_size": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "interval": {
+                    "type": "string",
+                    "example": "30s"
+                },
+                "max_retries": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "retry_delay": {
+                    "type": "string",
+                    "example": "5s"
+                }
+            }
         }
     }
 }`
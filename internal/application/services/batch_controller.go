@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/database/repos"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/constants/enums/batchdecisions"
+	"insider-message-system/pkg/errors"
+	"insider-message-system/pkg/logger"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// BatchController picks the batch size of each periodic cycle from the pending
+// backlog and the outcome of the previous cycles.
+type BatchController interface {
+	// Next decides the batch size for the next cycle from what was observed since
+	// the previous call.
+	Next(ctx context.Context) int
+	// Observe records the outcome of one processing attempt.
+	Observe(result *domain.BatchResult, err error)
+	// Status returns the latest decision.
+	Status() domain.BatchSizing
+}
+
+type batchController struct {
+	config      config.AdaptiveBatchConfig
+	messageRepo repos.Message
+	now         func() time.Time
+
+	// mu guards the batch size, the latest decision and the observations made
+	// since that decision.
+	mu          sync.Mutex
+	size        int
+	status      domain.BatchSizing
+	failed      bool
+	reached     int
+	deferred    int
+	rejected    int
+	sendLatency time.Duration
+}
+
+// NewBatchController creates a BatchController that starts at initialSize,
+// clamped to the configured bounds, and counts the backlog with messageRepo.
+// The size doubles while the backlog exceeds it and sends are healthy, and halves
+// when the mean send latency exceeds the target, the share of failed and deferred
+// sends exceeds the maximum error rate, or a processing attempt fails.
+func NewBatchController(cfg config.AdaptiveBatchConfig, initialSize int, messageRepo repos.Message) (BatchController, error) {
+	if err := validateAdaptiveBatchConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	size := min(max(initialSize, cfg.MinBatchSize), cfg.MaxBatchSize)
+
+	return &batchController{
+		config:      cfg,
+		messageRepo: messageRepo,
+		now:         time.Now,
+		size:        size,
+		status: domain.BatchSizing{
+			BatchSize:    size,
+			MinBatchSize: cfg.MinBatchSize,
+			MaxBatchSize: cfg.MaxBatchSize,
+			Decision:     batchdecisions.Hold,
+			Reason:       "initial batch size",
+			Backlog:      -1,
+		},
+	}, nil
+}
+
+func validateAdaptiveBatchConfig(cfg config.AdaptiveBatchConfig) error {
+	var details string
+	switch {
+	case cfg.MinBatchSize < domain.MinSchedulerBatchSize || cfg.MaxBatchSize > domain.MaxSchedulerBatchSize || cfg.MinBatchSize > cfg.MaxBatchSize:
+		details = fmt.Sprintf("scheduler.adaptive_batch.min_batch_size and max_batch_size must satisfy %d <= min <= max <= %d", domain.MinSchedulerBatchSize, domain.MaxSchedulerBatchSize)
+	case cfg.TargetLatency <= 0:
+		details = "scheduler.adaptive_batch.target_latency must be positive"
+	case cfg.MaxErrorRate < 0 || cfg.MaxErrorRate > 1:
+		details = "scheduler.adaptive_batch.max_error_rate must be between 0 and 1"
+	default:
+		return nil
+	}
+	return errors.NewErrorWithDetails("INVALID_SCHEDULER_CONFIG", "Invalid scheduler configuration", details, http.StatusInternalServerError)
+}
+
+func (c *batchController) Observe(result *domain.BatchResult, err error) {
+	// A cycle cut short by a stop says nothing about the provider.
+	if err == context.Canceled {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		c.failed = true
+	}
+	if result != nil {
+		reached := result.Sent + result.Failed
+		c.sendLatency += result.SendLatency * time.Duration(reached)
+		c.reached += reached
+		c.deferred += result.Deferred
+		c.rejected += result.Failed + result.Deferred
+	}
+}
+
+func (c *batchController) Next(ctx context.Context) int {
+	backlog, err := c.messageRepo.CountPending(ctx)
+	if err != nil {
+		logger.Warn("Failed to count pending messages for adaptive batch sizing", zap.Error(err))
+		backlog = -1
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var latency time.Duration
+	if c.reached > 0 {
+		latency = c.sendLatency / time.Duration(c.reached)
+	}
+	var errorRate float64
+	if total := c.reached + c.deferred; total > 0 {
+		errorRate = float64(c.rejected) / float64(total)
+	}
+
+	size, decision, reason := c.size, batchdecisions.Hold, "backlog fits the batch size"
+	switch {
+	case c.failed:
+		size, decision, reason = c.decrease(), batchdecisions.Decrease, "processing attempt failed"
+	case errorRate > c.config.MaxErrorRate:
+		size, decision = c.decrease(), batchdecisions.Decrease
+		reason = fmt.Sprintf("error rate %.2f above %.2f", errorRate, c.config.MaxErrorRate)
+	case latency > c.config.TargetLatency:
+		size, decision = c.decrease(), batchdecisions.Decrease
+		reason = fmt.Sprintf("send latency %s above target %s", latency.Round(time.Millisecond), c.config.TargetLatency)
+	case backlog < 0:
+		reason = "backlog unknown"
+	case backlog > int64(c.size) && c.size < c.config.MaxBatchSize:
+		size, decision = c.increase(backlog), batchdecisions.Increase
+		reason = fmt.Sprintf("backlog of %d exceeds batch size %d", backlog, c.size)
+	case backlog > int64(c.size):
+		reason = "batch size at maximum"
+	}
+
+	if size == c.size && decision == batchdecisions.Decrease {
+		decision, reason = batchdecisions.Hold, reason+", batch size at minimum"
+	}
+
+	if decision != batchdecisions.Hold {
+		logger.Info("Adaptive batch size changed",
+			zap.Int("from", c.size),
+			zap.Int("to", size),
+			zap.String("reason", reason))
+	}
+
+	c.size = size
+	c.status = domain.BatchSizing{
+		BatchSize:    size,
+		MinBatchSize: c.config.MinBatchSize,
+		MaxBatchSize: c.config.MaxBatchSize,
+		Decision:     decision,
+		Reason:       reason,
+		Backlog:      backlog,
+		LatencyMs:    latency.Milliseconds(),
+		ErrorRate:    errorRate,
+		DecidedAt:    c.now(),
+	}
+	c.failed, c.reached, c.deferred, c.rejected, c.sendLatency = false, 0, 0, 0, 0
+
+	return size
+}
+
+// increase must be called with mu held. It never grows the batch past the backlog.
+func (c *batchController) increase(backlog int64) int {
+	size := min(c.size*2, c.config.MaxBatchSize)
+	if backlog < int64(size) {
+		size = int(backlog)
+	}
+	return size
+}
+
+// decrease must be called with mu held.
+func (c *batchController) decrease() int {
+	return max(c.size/2, c.config.MinBatchSize)
+}
+
+func (c *batchController) Status() domain.BatchSizing {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
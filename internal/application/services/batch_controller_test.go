@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/constants/enums/batchdecisions"
+	customerrors "insider-message-system/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBatchController(t *testing.T, initialSize int, backlog int64) BatchController {
+	repo := new(mockMessageRepository)
+	repo.On("CountPending", mock.Anything).Return(backlog, nil)
+
+	controller, err := NewBatchController(config.AdaptiveBatchConfig{
+		MinBatchSize:  2,
+		MaxBatchSize:  50,
+		TargetLatency: 500 * time.Millisecond,
+		MaxErrorRate:  0.2,
+	}, initialSize, repo)
+	require.NoError(t, err)
+	return controller
+}
+
+func TestBatchController_GrowsWithBacklogUpToMax(t *testing.T) {
+	controller := newTestBatchController(t, 10, 1000)
+	ctx := context.Background()
+
+	healthy := &domain.BatchResult{Fetched: 10, Sent: 10, SendLatency: 100 * time.Millisecond}
+
+	controller.Observe(healthy, nil)
+	assert.Equal(t, 20, controller.Next(ctx))
+
+	status := controller.Status()
+	assert.Equal(t, batchdecisions.Increase, status.Decision)
+	assert.Equal(t, int64(1000), status.Backlog)
+	assert.Equal(t, int64(100), status.LatencyMs)
+	assert.Equal(t, "backlog of 1000 exceeds batch size 10", status.Reason)
+
+	controller.Observe(healthy, nil)
+	assert.Equal(t, 40, controller.Next(ctx))
+	controller.Observe(healthy, nil)
+	assert.Equal(t, 50, controller.Next(ctx))
+	controller.Observe(healthy, nil)
+	assert.Equal(t, 50, controller.Next(ctx))
+	assert.Equal(t, batchdecisions.Hold, controller.Status().Decision)
+}
+
+func TestBatchController_DoesNotGrowPastBacklog(t *testing.T) {
+	controller := newTestBatchController(t, 10, 15)
+
+	assert.Equal(t, 15, controller.Next(context.Background()))
+	assert.Equal(t, 15, controller.Next(context.Background()))
+	assert.Equal(t, batchdecisions.Hold, controller.Status().Decision)
+}
+
+func TestBatchController_ShrinksOnLatencyAndErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *domain.BatchResult
+		err    error
+		reason string
+	}{
+		{
+			name:   "latency above target",
+			result: &domain.BatchResult{Fetched: 40, Sent: 40, SendLatency: 800 * time.Millisecond},
+			reason: "send latency 800ms above target 500ms",
+		},
+		{
+			name:   "failed and deferred sends",
+			result: &domain.BatchResult{Fetched: 40, Sent: 30, Failed: 5, Deferred: 5, SendLatency: 100 * time.Millisecond},
+			reason: "error rate 0.25 above 0.20",
+		},
+		{
+			name:   "circuit breaker open",
+			result: &domain.BatchResult{},
+			err:    customerrors.ErrWebhookCircuitOpen,
+			reason: "processing attempt failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := newTestBatchController(t, 40, 1000)
+
+			controller.Observe(tt.result, tt.err)
+			assert.Equal(t, 20, controller.Next(context.Background()))
+
+			status := controller.Status()
+			assert.Equal(t, batchdecisions.Decrease, status.Decision)
+			assert.Equal(t, tt.reason, status.Reason)
+		})
+	}
+}
+
+func TestBatchController_StaysWithinMin(t *testing.T) {
+	controller := newTestBatchController(t, 3, 1000)
+	ctx := context.Background()
+
+	controller.Observe(nil, errors.New("database unavailable"))
+	assert.Equal(t, 2, controller.Next(ctx))
+
+	controller.Observe(nil, errors.New("database unavailable"))
+	assert.Equal(t, 2, controller.Next(ctx))
+	assert.Equal(t, batchdecisions.Hold, controller.Status().Decision)
+	assert.Equal(t, "processing attempt failed, batch size at minimum", controller.Status().Reason)
+}
+
+func TestBatchController_IgnoresCancelledCycles(t *testing.T) {
+	controller := newTestBatchController(t, 10, 1000)
+
+	controller.Observe(&domain.BatchResult{Fetched: 10, Sent: 4, Unsent: 6, SendLatency: 100 * time.Millisecond}, context.Canceled)
+	assert.Equal(t, 20, controller.Next(context.Background()))
+}
+
+func TestBatchController_HoldsWhenBacklogUnknown(t *testing.T) {
+	repo := new(mockMessageRepository)
+	repo.On("CountPending", mock.Anything).Return(int64(0), errors.New("count error"))
+
+	controller, err := NewBatchController(config.AdaptiveBatchConfig{MinBatchSize: 1, MaxBatchSize: 100, TargetLatency: time.Second, MaxErrorRate: 0.1}, 10, repo)
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, controller.Next(context.Background()))
+	status := controller.Status()
+	assert.Equal(t, batchdecisions.Hold, status.Decision)
+	assert.Equal(t, int64(-1), status.Backlog)
+}
+
+func TestNewBatchController_ClampsAndValidates(t *testing.T) {
+	valid := config.AdaptiveBatchConfig{MinBatchSize: 5, MaxBatchSize: 20, TargetLatency: time.Second, MaxErrorRate: 0.1}
+
+	controller, err := NewBatchController(valid, 2, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 5, controller.Status().BatchSize)
+
+	controller, err = NewBatchController(valid, 500, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 20, controller.Status().BatchSize)
+
+	invalid := []config.AdaptiveBatchConfig{
+		{MinBatchSize: 0, MaxBatchSize: 20, TargetLatency: time.Second},
+		{MinBatchSize: 30, MaxBatchSize: 20, TargetLatency: time.Second},
+		{MinBatchSize: 1, MaxBatchSize: 5000, TargetLatency: time.Second},
+		{MinBatchSize: 1, MaxBatchSize: 20},
+		{MinBatchSize: 1, MaxBatchSize: 20, TargetLatency: time.Second, MaxErrorRate: 1.5},
+	}
+	for _, cfg := range invalid {
+		_, err := NewBatchController(cfg, 10, nil)
+		assert.Error(t, err)
+	}
+}
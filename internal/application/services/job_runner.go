@@ -0,0 +1,505 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/election"
+	"insider-message-system/pkg/constants/enums/joboverlaps"
+	"insider-message-system/pkg/errors"
+	"insider-message-system/pkg/logger"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Job is a named task that a JobRunner runs periodically.
+type Job struct {
+	Name string
+	Run  func(ctx context.Context) error
+	// Interval is the time between due runs. Runs happen in the background, so a
+	// slow run does not push back the next one.
+	Interval time.Duration
+	// Jitter adds a random delay of up to Jitter to every interval, so that
+	// instances started together do not run the job in lockstep.
+	Jitter time.Duration
+	// Overlap decides what happens when a run is due while the previous one is
+	// still in progress.
+	Overlap joboverlaps.JobOverlap
+	// Timeout bounds each attempt. Zero means no timeout.
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts per run, including the first one.
+	MaxAttempts int
+	// RetryDelay is the wait between failed attempts.
+	RetryDelay time.Duration
+	// LeaderOnly runs the job only on the instance that holds the runner's
+	// leadership, for jobs that must not run on every replica at once. Without an
+	// elector the job runs on every instance.
+	LeaderOnly bool
+}
+
+// JobRunner defines the interface for the service that runs registered jobs in
+// the background, each on its own schedule. The send cycle is not a job: the
+// Scheduler keeps its own loop for its cron schedules, wake-ups and manual runs.
+type JobRunner interface {
+	Register(job Job) error
+	// SetElector makes the runner campaign for leadership from Start until Stop.
+	// It must be called before Start.
+	SetElector(elector election.Elector)
+	Start(ctx context.Context) error
+	Stop() error
+	StartJob(ctx context.Context, name string) error
+	StopJob(name string) error
+	Status(name string) (*domain.JobStatus, error)
+	Statuses() []domain.JobStatus
+}
+
+type jobEntry struct {
+	job Job
+
+	// control serializes starting and stopping the job, so that a start cannot
+	// race a stop that is still waiting for the job's runs.
+	control sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+	runs    sync.WaitGroup
+
+	// The fields below are guarded by the runner's mu.
+	running   bool
+	inFlight  int
+	queued    bool
+	nextRunAt *time.Time
+	lastRun   *domain.JobRun
+	runCount  int64
+	failures  int64
+	skipped   int64
+}
+
+type jobRunner struct {
+	now    func() time.Time
+	jitter func(limit time.Duration) time.Duration
+
+	elector         election.Elector
+	stopCampaign    context.CancelFunc
+	campaignStopped chan struct{}
+
+	mu   sync.Mutex
+	jobs map[string]*jobEntry
+	// leaderCtx is set while this instance leads, and is cancelled as soon as it
+	// loses leadership.
+	leaderCtx context.Context
+}
+
+// NewJobRunner creates a new JobRunner with no jobs registered.
+func NewJobRunner() JobRunner {
+	return &jobRunner{
+		now: time.Now,
+		jitter: func(limit time.Duration) time.Duration {
+			if limit <= 0 {
+				return 0
+			}
+			return rand.N(limit + 1)
+		},
+		jobs: make(map[string]*jobEntry),
+	}
+}
+
+// Register adds a job to the runner. The job stays stopped until Start or
+// StartJob is called.
+func (r *jobRunner) Register(job Job) error {
+	if err := validateJob(job); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.jobs[job.Name]; exists {
+		return errors.NewErrorWithDetails("INVALID_JOB_CONFIG", "Invalid job configuration", fmt.Sprintf("job %q is already registered", job.Name), http.StatusInternalServerError)
+	}
+
+	r.jobs[job.Name] = &jobEntry{job: job}
+	return nil
+}
+
+func validateJob(job Job) error {
+	var details string
+	switch {
+	case job.Name == "":
+		details = "job name must not be empty"
+	case job.Run == nil:
+		details = fmt.Sprintf("job %q has no run function", job.Name)
+	case job.Interval <= 0:
+		details = fmt.Sprintf("job %q interval must be positive", job.Name)
+	case job.Jitter < 0:
+		details = fmt.Sprintf("job %q jitter must not be negative", job.Name)
+	case !job.Overlap.IsValid():
+		details = fmt.Sprintf("job %q overlap must be one of skip, queue or allow", job.Name)
+	case job.Timeout < 0:
+		details = fmt.Sprintf("job %q timeout must not be negative", job.Name)
+	case job.MaxAttempts < 1:
+		details = fmt.Sprintf("job %q max_attempts must be at least 1", job.Name)
+	case job.RetryDelay < 0:
+		details = fmt.Sprintf("job %q retry_delay must not be negative", job.Name)
+	default:
+		return nil
+	}
+	return errors.NewErrorWithDetails("INVALID_JOB_CONFIG", "Invalid job configuration", details, http.StatusInternalServerError)
+}
+
+func (r *jobRunner) SetElector(elector election.Elector) {
+	r.elector = elector
+}
+
+// Start starts every registered job that is not already running, and campaigns
+// for leadership when the runner has an elector.
+func (r *jobRunner) Start(ctx context.Context) error {
+	if r.elector != nil && r.stopCampaign == nil {
+		var campaignCtx context.Context
+		campaignCtx, r.stopCampaign = context.WithCancel(context.WithoutCancel(ctx))
+		r.campaignStopped = make(chan struct{})
+		go func() {
+			defer close(r.campaignStopped)
+			r.elector.Run(campaignCtx, r.lead)
+		}()
+	}
+
+	for _, entry := range r.entries() {
+		if err := r.startEntry(ctx, entry); err != nil && err != errors.ErrJobAlreadyRunning {
+			return err
+		}
+	}
+	return nil
+}
+
+// lead makes leader-only jobs run until leaderCtx is cancelled.
+func (r *jobRunner) lead(leaderCtx context.Context) {
+	r.mu.Lock()
+	r.leaderCtx = leaderCtx
+	r.mu.Unlock()
+
+	<-leaderCtx.Done()
+
+	r.mu.Lock()
+	r.leaderCtx = nil
+	r.mu.Unlock()
+}
+
+// Stop stops every running job, waits for their runs in progress and hands
+// leadership over.
+func (r *jobRunner) Stop() error {
+	entries := r.entries()
+
+	var wg sync.WaitGroup
+	for _, entry := range entries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.stopEntry(entry); err != nil && err != errors.ErrJobNotRunning {
+				logger.Error("Failed to stop job", zap.String("job", entry.job.Name), zap.Error(err))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if r.stopCampaign != nil {
+		r.stopCampaign()
+		<-r.campaignStopped
+		r.stopCampaign = nil
+	}
+
+	return nil
+}
+
+// StartJob starts a single job. The job outlives ctx, so that a job started from
+// an HTTP request keeps running after the request completes.
+func (r *jobRunner) StartJob(ctx context.Context, name string) error {
+	entry, err := r.entry(name)
+	if err != nil {
+		return err
+	}
+	return r.startEntry(context.WithoutCancel(ctx), entry)
+}
+
+// StopJob stops a single job and waits for its runs in progress, which see their
+// context cancelled.
+func (r *jobRunner) StopJob(name string) error {
+	entry, err := r.entry(name)
+	if err != nil {
+		return err
+	}
+	return r.stopEntry(entry)
+}
+
+func (r *jobRunner) Status(name string) (*domain.JobStatus, error) {
+	entry, err := r.entry(name)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := r.statusLocked(entry)
+	return &status, nil
+}
+
+// Statuses returns the status of every registered job, ordered by name.
+func (r *jobRunner) Statuses() []domain.JobStatus {
+	entries := r.entries()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := make([]domain.JobStatus, len(entries))
+	for i, entry := range entries {
+		statuses[i] = r.statusLocked(entry)
+	}
+	return statuses
+}
+
+// statusLocked must be called with mu held.
+func (r *jobRunner) statusLocked(entry *jobEntry) domain.JobStatus {
+	status := domain.JobStatus{
+		Name:        entry.job.Name,
+		Running:     entry.running,
+		Interval:    entry.job.Interval,
+		Jitter:      entry.job.Jitter,
+		Overlap:     entry.job.Overlap,
+		Timeout:     entry.job.Timeout,
+		MaxAttempts: entry.job.MaxAttempts,
+		RetryDelay:  entry.job.RetryDelay,
+		LeaderOnly:  entry.job.LeaderOnly,
+		Standby:     entry.job.LeaderOnly && r.elector != nil && r.leaderCtx == nil,
+		InFlight:    entry.inFlight,
+		Runs:        entry.runCount,
+		Failures:    entry.failures,
+		Skipped:     entry.skipped,
+	}
+	if entry.running && entry.nextRunAt != nil {
+		nextRunAt := *entry.nextRunAt
+		status.NextRunAt = &nextRunAt
+	}
+	if entry.lastRun != nil {
+		lastRun := *entry.lastRun
+		status.LastRun = &lastRun
+	}
+	return status
+}
+
+func (r *jobRunner) entry(name string) (*jobEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, exists := r.jobs[name]
+	if !exists {
+		return nil, errors.ErrJobNotFound
+	}
+	return entry, nil
+}
+
+func (r *jobRunner) entries() []*jobEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]*jobEntry, 0, len(r.jobs))
+	for _, entry := range r.jobs {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].job.Name < entries[j].job.Name })
+	return entries
+}
+
+func (r *jobRunner) startEntry(ctx context.Context, entry *jobEntry) error {
+	entry.control.Lock()
+	defer entry.control.Unlock()
+
+	r.mu.Lock()
+	if entry.running {
+		r.mu.Unlock()
+		return errors.ErrJobAlreadyRunning
+	}
+	entry.running = true
+	delay := r.scheduleLocked(entry)
+	r.mu.Unlock()
+
+	ctx, entry.cancel = context.WithCancel(ctx)
+	entry.done = make(chan struct{})
+	go r.loop(ctx, entry, delay)
+
+	logger.Info("Job started",
+		zap.String("job", entry.job.Name),
+		zap.Duration("interval", entry.job.Interval),
+		zap.Duration("jitter", entry.job.Jitter),
+		zap.String("overlap", entry.job.Overlap.String()))
+	return nil
+}
+
+func (r *jobRunner) stopEntry(entry *jobEntry) error {
+	entry.control.Lock()
+	defer entry.control.Unlock()
+
+	r.mu.Lock()
+	if !entry.running {
+		r.mu.Unlock()
+		return errors.ErrJobNotRunning
+	}
+	entry.running = false
+	entry.nextRunAt = nil
+	r.mu.Unlock()
+
+	// Runs are only triggered from the loop, so once it has exited no new run can
+	// be added while waiting for those in progress.
+	entry.cancel()
+	<-entry.done
+	entry.runs.Wait()
+
+	logger.Info("Job stopped", zap.String("job", entry.job.Name))
+	return nil
+}
+
+func (r *jobRunner) loop(ctx context.Context, entry *jobEntry, delay time.Duration) {
+	defer close(entry.done)
+
+	for {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		r.mu.Lock()
+		r.triggerLocked(ctx, entry)
+		delay = r.scheduleLocked(entry)
+		r.mu.Unlock()
+	}
+}
+
+// scheduleLocked must be called with mu held. It picks the delay until the next
+// due run.
+func (r *jobRunner) scheduleLocked(entry *jobEntry) time.Duration {
+	delay := entry.job.Interval + r.jitter(entry.job.Jitter)
+	nextRunAt := r.now().Add(delay)
+	entry.nextRunAt = &nextRunAt
+	return delay
+}
+
+// triggerLocked must be called with mu held. It applies the job's overlap policy
+// to a due run. A leader-only run is dropped on an instance that does not lead,
+// and is cancelled once this instance stops leading.
+func (r *jobRunner) triggerLocked(ctx context.Context, entry *jobEntry) {
+	var leaderCtx context.Context
+	if entry.job.LeaderOnly && r.elector != nil {
+		if r.leaderCtx == nil {
+			return
+		}
+		leaderCtx = r.leaderCtx
+	}
+
+	if entry.inFlight > 0 {
+		switch entry.job.Overlap {
+		case joboverlaps.Skip:
+			entry.skipped++
+			logger.Warn("Skipping job run, previous run still in progress", zap.String("job", entry.job.Name))
+			return
+		case joboverlaps.Queue:
+			entry.queued = true
+			return
+		}
+	}
+
+	entry.inFlight++
+	entry.runs.Add(1)
+	go r.execute(ctx, leaderCtx, entry)
+}
+
+// execute runs the job, and runs it again while a queued run is waiting. A
+// non-nil leaderCtx cancels the runs when it is cancelled.
+func (r *jobRunner) execute(ctx, leaderCtx context.Context, entry *jobEntry) {
+	defer entry.runs.Done()
+
+	if leaderCtx != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		defer context.AfterFunc(leaderCtx, cancel)()
+	}
+
+	for {
+		run := r.run(ctx, entry.job)
+
+		r.mu.Lock()
+		entry.lastRun = &run
+		entry.runCount++
+		if run.Error != "" && ctx.Err() == nil {
+			entry.failures++
+		}
+		if entry.queued && ctx.Err() == nil {
+			entry.queued = false
+			r.mu.Unlock()
+			continue
+		}
+		entry.queued = false
+		entry.inFlight--
+		r.mu.Unlock()
+		return
+	}
+}
+
+// run makes up to MaxAttempts attempts, each bounded by the job's timeout.
+func (r *jobRunner) run(ctx context.Context, job Job) domain.JobRun {
+	run := domain.JobRun{StartedAt: r.now()}
+
+	var err error
+	for run.Attempts < job.MaxAttempts {
+		run.Attempts++
+		if err = r.attempt(ctx, job); err == nil || ctx.Err() != nil || run.Attempts == job.MaxAttempts {
+			break
+		}
+
+		logger.Warn("Job attempt failed, retrying",
+			zap.String("job", job.Name),
+			zap.Int("attempt", run.Attempts),
+			zap.Duration("retry_delay", job.RetryDelay),
+			zap.Error(err))
+
+		timer := time.NewTimer(job.RetryDelay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			err = ctx.Err()
+			break
+		}
+	}
+
+	run.FinishedAt = r.now()
+	run.DurationMs = run.FinishedAt.Sub(run.StartedAt).Milliseconds()
+	if err != nil {
+		run.Error = err.Error()
+		if ctx.Err() == nil {
+			logger.Error("Job run failed",
+				zap.String("job", job.Name),
+				zap.Int("attempts", run.Attempts),
+				zap.Error(err))
+		}
+	}
+	return run
+}
+
+func (r *jobRunner) attempt(ctx context.Context, job Job) error {
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+	return job.Run(ctx)
+}
@@ -0,0 +1,364 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"insider-message-system/internal/infrastructure/election"
+	"insider-message-system/pkg/constants/enums/joboverlaps"
+	customerrors "insider-message-system/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testJob(name string, run func(ctx context.Context) error) Job {
+	return Job{
+		Name:        name,
+		Run:         run,
+		Interval:    5 * time.Millisecond,
+		Overlap:     joboverlaps.Skip,
+		MaxAttempts: 1,
+	}
+}
+
+// blockingJob returns a run function that blocks until release is closed or its
+// context is cancelled, counting the runs that have started.
+func blockingJob(started *atomic.Int32, release <-chan struct{}) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		started.Add(1)
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func TestJobRunner_Register_RejectsInvalidJob(t *testing.T) {
+	noop := func(ctx context.Context) error { return nil }
+
+	tests := []struct {
+		name   string
+		modify func(job *Job)
+	}{
+		{name: "empty name", modify: func(job *Job) { job.Name = "" }},
+		{name: "no run function", modify: func(job *Job) { job.Run = nil }},
+		{name: "zero interval", modify: func(job *Job) { job.Interval = 0 }},
+		{name: "negative jitter", modify: func(job *Job) { job.Jitter = -time.Second }},
+		{name: "unknown overlap", modify: func(job *Job) { job.Overlap = "replace" }},
+		{name: "negative timeout", modify: func(job *Job) { job.Timeout = -time.Second }},
+		{name: "zero attempts", modify: func(job *Job) { job.MaxAttempts = 0 }},
+		{name: "negative retry delay", modify: func(job *Job) { job.RetryDelay = -time.Second }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := testJob("sweep", noop)
+			tt.modify(&job)
+
+			err := NewJobRunner().Register(job)
+			var customErr *customerrors.Error
+			require.ErrorAs(t, err, &customErr)
+			assert.Equal(t, "INVALID_JOB_CONFIG", customErr.Code)
+		})
+	}
+}
+
+func TestJobRunner_Register_RejectsDuplicateName(t *testing.T) {
+	runner := NewJobRunner()
+	noop := func(ctx context.Context) error { return nil }
+
+	assert.NoError(t, runner.Register(testJob("sweep", noop)))
+	assert.Error(t, runner.Register(testJob("sweep", noop)))
+}
+
+func TestJobRunner_StartStopJob(t *testing.T) {
+	runner := NewJobRunner()
+	var runs atomic.Int32
+	require.NoError(t, runner.Register(testJob("sweep", func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})))
+
+	status, err := runner.Status("sweep")
+	require.NoError(t, err)
+	assert.False(t, status.Running)
+	assert.Nil(t, status.NextRunAt)
+
+	assert.NoError(t, runner.StartJob(context.Background(), "sweep"))
+	assert.Equal(t, customerrors.ErrJobAlreadyRunning, runner.StartJob(context.Background(), "sweep"))
+	assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, time.Millisecond)
+
+	status, err = runner.Status("sweep")
+	require.NoError(t, err)
+	assert.True(t, status.Running)
+	assert.NotNil(t, status.NextRunAt)
+	require.NotNil(t, status.LastRun)
+	assert.Equal(t, 1, status.LastRun.Attempts)
+	assert.Empty(t, status.LastRun.Error)
+
+	assert.NoError(t, runner.StopJob("sweep"))
+	assert.Equal(t, customerrors.ErrJobNotRunning, runner.StopJob("sweep"))
+
+	stopped := runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, stopped, runs.Load())
+
+	status, err = runner.Status("sweep")
+	require.NoError(t, err)
+	assert.False(t, status.Running)
+	assert.Equal(t, int64(stopped), status.Runs)
+}
+
+func TestJobRunner_UnknownJob(t *testing.T) {
+	runner := NewJobRunner()
+
+	assert.Equal(t, customerrors.ErrJobNotFound, runner.StartJob(context.Background(), "missing"))
+	assert.Equal(t, customerrors.ErrJobNotFound, runner.StopJob("missing"))
+	_, err := runner.Status("missing")
+	assert.Equal(t, customerrors.ErrJobNotFound, err)
+}
+
+func TestJobRunner_StartStop_AllJobs(t *testing.T) {
+	runner := NewJobRunner()
+	noop := func(ctx context.Context) error { return nil }
+	require.NoError(t, runner.Register(testJob("sweep", noop)))
+	require.NoError(t, runner.Register(testJob("archive", noop)))
+
+	assert.NoError(t, runner.Start(context.Background()))
+	assert.NoError(t, runner.Start(context.Background()))
+
+	statuses := runner.Statuses()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "archive", statuses[0].Name)
+	assert.Equal(t, "sweep", statuses[1].Name)
+	assert.True(t, statuses[0].Running)
+	assert.True(t, statuses[1].Running)
+
+	assert.NoError(t, runner.Stop())
+	assert.NoError(t, runner.Stop())
+	for _, status := range runner.Statuses() {
+		assert.False(t, status.Running)
+	}
+}
+
+func TestJobRunner_RetriesFailedAttempts(t *testing.T) {
+	runner := NewJobRunner()
+	var attempts atomic.Int32
+	job := testJob("sweep", func(ctx context.Context) error {
+		if attempts.Add(1)%3 != 0 {
+			return fmt.Errorf("attempt failed")
+		}
+		return nil
+	})
+	job.MaxAttempts = 3
+	job.Interval = time.Hour
+	require.NoError(t, runner.Register(job))
+
+	r := runner.(*jobRunner)
+	entry, err := r.entry("sweep")
+	require.NoError(t, err)
+
+	run := r.run(context.Background(), entry.job)
+	assert.Equal(t, 3, run.Attempts)
+	assert.Empty(t, run.Error)
+}
+
+func TestJobRunner_TimeoutFailsAttempt(t *testing.T) {
+	runner := NewJobRunner()
+	job := testJob("sweep", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	job.Timeout = 5 * time.Millisecond
+	job.MaxAttempts = 2
+	require.NoError(t, runner.Register(job))
+
+	r := runner.(*jobRunner)
+	entry, err := r.entry("sweep")
+	require.NoError(t, err)
+
+	run := r.run(context.Background(), entry.job)
+	assert.Equal(t, 2, run.Attempts)
+	assert.Equal(t, context.DeadlineExceeded.Error(), run.Error)
+}
+
+func TestJobRunner_Overlap(t *testing.T) {
+	t.Run("skip drops due runs", func(t *testing.T) {
+		runner := NewJobRunner()
+		var started atomic.Int32
+		release := make(chan struct{})
+		require.NoError(t, runner.Register(testJob("sweep", blockingJob(&started, release))))
+
+		require.NoError(t, runner.StartJob(context.Background(), "sweep"))
+		assert.Eventually(t, func() bool {
+			status, _ := runner.Status("sweep")
+			return status.Skipped >= 2
+		}, time.Second, time.Millisecond)
+
+		status, err := runner.Status("sweep")
+		require.NoError(t, err)
+		assert.Equal(t, 1, status.InFlight)
+		assert.Equal(t, int32(1), started.Load())
+
+		close(release)
+		assert.NoError(t, runner.StopJob("sweep"))
+	})
+
+	t.Run("queue coalesces due runs", func(t *testing.T) {
+		runner := NewJobRunner()
+		var started atomic.Int32
+		release := make(chan struct{}, 1)
+		job := testJob("sweep", blockingJob(&started, release))
+		job.Overlap = joboverlaps.Queue
+		require.NoError(t, runner.Register(job))
+
+		require.NoError(t, runner.StartJob(context.Background(), "sweep"))
+		assert.Eventually(t, func() bool { return started.Load() == 1 }, time.Second, time.Millisecond)
+
+		// Several runs fall due while the first one blocks, but only one follows it.
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, int32(1), started.Load())
+		release <- struct{}{}
+		assert.Eventually(t, func() bool { return started.Load() == 2 }, time.Second, time.Millisecond)
+
+		status, err := runner.Status("sweep")
+		require.NoError(t, err)
+		assert.Equal(t, 1, status.InFlight)
+		assert.Zero(t, status.Skipped)
+
+		assert.NoError(t, runner.StopJob("sweep"))
+	})
+
+	t.Run("allow runs concurrently", func(t *testing.T) {
+		runner := NewJobRunner()
+		var started atomic.Int32
+		release := make(chan struct{})
+		job := testJob("sweep", blockingJob(&started, release))
+		job.Overlap = joboverlaps.Allow
+		require.NoError(t, runner.Register(job))
+
+		require.NoError(t, runner.StartJob(context.Background(), "sweep"))
+		assert.Eventually(t, func() bool {
+			status, _ := runner.Status("sweep")
+			return status.InFlight >= 2
+		}, time.Second, time.Millisecond)
+
+		close(release)
+		assert.NoError(t, runner.StopJob("sweep"))
+	})
+}
+
+func TestJobRunner_StopCancelsRunInProgress(t *testing.T) {
+	runner := NewJobRunner()
+	var started atomic.Int32
+	require.NoError(t, runner.Register(testJob("sweep", blockingJob(&started, make(chan struct{})))))
+
+	require.NoError(t, runner.StartJob(context.Background(), "sweep"))
+	assert.Eventually(t, func() bool { return started.Load() == 1 }, time.Second, time.Millisecond)
+
+	assert.NoError(t, runner.StopJob("sweep"))
+
+	status, err := runner.Status("sweep")
+	require.NoError(t, err)
+	assert.Zero(t, status.InFlight)
+	assert.Equal(t, int64(1), status.Runs)
+	assert.Zero(t, status.Failures)
+	require.NotNil(t, status.LastRun)
+	assert.Equal(t, context.Canceled.Error(), status.LastRun.Error)
+}
+
+func TestJobRunner_LeaderOnly_RunsOnLeader(t *testing.T) {
+	lock := &sharedLock{}
+
+	newReplica := func(id string) (JobRunner, *atomic.Int32) {
+		var runs atomic.Int32
+		runner := NewJobRunner()
+		job := testJob("relay", func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		})
+		job.LeaderOnly = true
+		require.NoError(t, runner.Register(job))
+		runner.SetElector(election.New(lock, id, time.Millisecond))
+		return runner, &runs
+	}
+
+	first, firstRuns := newReplica("first")
+	second, secondRuns := newReplica("second")
+
+	require.NoError(t, first.Start(context.Background()))
+	assert.Eventually(t, func() bool { return firstRuns.Load() > 0 }, time.Second, time.Millisecond)
+
+	require.NoError(t, second.Start(context.Background()))
+	defer second.Stop()
+
+	// The follower keeps the job scheduled but drops its due runs.
+	time.Sleep(20 * time.Millisecond)
+	assert.Zero(t, secondRuns.Load())
+	status, err := second.Status("relay")
+	require.NoError(t, err)
+	assert.True(t, status.Running)
+	assert.True(t, status.LeaderOnly)
+	assert.True(t, status.Standby)
+
+	// Stopping the leader hands the job over.
+	require.NoError(t, first.Stop())
+	assert.Eventually(t, func() bool { return secondRuns.Load() > 0 }, time.Second, time.Millisecond)
+	status, err = second.Status("relay")
+	require.NoError(t, err)
+	assert.False(t, status.Standby)
+}
+
+func TestJobRunner_LeaderOnly_LostLeadershipCancelsRun(t *testing.T) {
+	lock := &sharedLock{}
+	runner := NewJobRunner()
+	var started atomic.Int32
+	job := testJob("relay", blockingJob(&started, make(chan struct{})))
+	job.LeaderOnly = true
+	require.NoError(t, runner.Register(job))
+	runner.SetElector(election.New(lock, "first", time.Millisecond))
+
+	require.NoError(t, runner.Start(context.Background()))
+	defer runner.Stop()
+	assert.Eventually(t, func() bool { return started.Load() == 1 }, time.Second, time.Millisecond)
+
+	lock.mu.Lock()
+	lock.holder = "second"
+	lock.mu.Unlock()
+
+	assert.Eventually(t, func() bool {
+		status, _ := runner.Status("relay")
+		return status.Standby && status.InFlight == 0
+	}, time.Second, time.Millisecond)
+	status, err := runner.Status("relay")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), status.Runs)
+	assert.Zero(t, status.Failures)
+	require.NotNil(t, status.LastRun)
+	assert.Equal(t, context.Canceled.Error(), status.LastRun.Error)
+}
+
+func TestJobRunner_LeaderOnly_WithoutElectorRunsEverywhere(t *testing.T) {
+	runner := NewJobRunner()
+	var runs atomic.Int32
+	job := testJob("relay", func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	job.LeaderOnly = true
+	require.NoError(t, runner.Register(job))
+
+	require.NoError(t, runner.Start(context.Background()))
+	defer runner.Stop()
+	assert.Eventually(t, func() bool { return runs.Load() > 0 }, time.Second, time.Millisecond)
+
+	status, err := runner.Status("relay")
+	require.NoError(t, err)
+	assert.False(t, status.Standby)
+}
@@ -2,14 +2,20 @@ package services
 
 import (
 	"context"
+	stderrors "errors"
 	"insider-message-system/internal/domain"
 	"insider-message-system/internal/infrastructure/database/repos"
+	"insider-message-system/internal/infrastructure/notifier"
 	"insider-message-system/internal/infrastructure/redis"
 	"insider-message-system/internal/infrastructure/webhook"
+	"insider-message-system/pkg/config"
 	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
 	"insider-message-system/pkg/errors"
 	"insider-message-system/pkg/logger"
+	"time"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
@@ -17,25 +23,46 @@ import (
 type Message interface {
 	CreateMessage(ctx context.Context, request domain.MessageRequest) (*domain.Message, error)
 	GetSentMessages(ctx context.Context, page, pageSize int) ([]*domain.Message, int64, error)
-	SendPendingMessages(ctx context.Context, limit int) error
+	ClaimPendingMessages(ctx context.Context, limit int, lease time.Duration) ([]*domain.Message, error)
+	SendMessage(ctx context.Context, message *domain.Message) error
+	ReleaseClaim(ctx context.Context, id uuid.UUID) error
 }
 
 type message struct {
 	messageRepo    repos.Message
 	webhookService webhook.Client
 	cacheService   redis.CacheService
+	notifier       notifier.Notifier
+	retry          config.RetryConfig
 }
 
-// NewMessage creates a new Message service with the given dependencies.
+// NewMessage creates a new Message service with the given dependencies. Failed
+// sends are not retried, except for throttled ones.
 func NewMessage(
 	messageRepo repos.Message,
 	webhookService webhook.Client,
 	cacheService redis.CacheService,
 ) Message {
+	return NewMessageWithNotifier(messageRepo, webhookService, cacheService, nil, config.RetryConfig{MaxAttempts: 1})
+}
+
+// NewMessageWithNotifier creates a Message service that signals notifier after
+// every created message, so the scheduler can send it without waiting for the next tick,
+// and retries failed sends according to retry. A MaxAttempts below one is taken as one.
+func NewMessageWithNotifier(
+	messageRepo repos.Message,
+	webhookService webhook.Client,
+	cacheService redis.CacheService,
+	notifier notifier.Notifier,
+	retry config.RetryConfig,
+) Message {
+	retry.MaxAttempts = max(retry.MaxAttempts, 1)
 	return &message{
 		messageRepo:    messageRepo,
 		webhookService: webhookService,
 		cacheService:   cacheService,
+		notifier:       notifier,
+		retry:          retry,
 	}
 }
 
@@ -52,6 +79,13 @@ func (s *message) CreateMessage(ctx context.Context, request domain.MessageReque
 	}
 
 	logger.Info("Message created successfully", zap.String("message_id", message.ID.String()))
+
+	if s.notifier != nil {
+		if err := s.notifier.Notify(ctx); err != nil {
+			logger.Warn("Failed to signal scheduler wake-up", zap.Error(err), zap.String("message_id", message.ID.String()))
+		}
+	}
+
 	return message, nil
 }
 
@@ -73,38 +107,36 @@ func (s *message) GetSentMessages(ctx context.Context, page, pageSize int) ([]*d
 	return messages, total, nil
 }
 
-func (s *message) SendPendingMessages(ctx context.Context, limit int) error {
-	messages, err := s.messageRepo.GetPendingMessages(ctx, limit)
+// ClaimPendingMessages claims up to limit pending messages for lease so that no
+// other cycle or instance sends them at the same time.
+func (s *message) ClaimPendingMessages(ctx context.Context, limit int, lease time.Duration) ([]*domain.Message, error) {
+	messages, err := s.messageRepo.ClaimPendingMessages(ctx, limit, lease)
 	if err != nil {
-		logger.Error("Failed to get pending messages", zap.Error(err))
-		return err
-	}
-
-	if len(messages) == 0 {
-		logger.Debug("No pending messages to send")
-		return nil
+		logger.Error("Failed to claim pending messages", zap.Error(err))
+		return nil, err
 	}
 
-	logger.Info("Processing pending messages", zap.Int("count", len(messages)))
-
-	for _, message := range messages {
-		if err := s.sendSingleMessage(ctx, message); err != nil {
-			logger.Error("Failed to send message",
-				zap.Error(err),
-				zap.String("message_id", message.ID.String()))
-			continue
-		}
-	}
+	return messages, nil
+}
 
-	return nil
+// ReleaseClaim makes a claimed message that was not sent available to the next
+// claim right away.
+func (s *message) ReleaseClaim(ctx context.Context, id uuid.UUID) error {
+	return s.messageRepo.ReleaseClaim(ctx, id)
 }
 
-func (s *message) sendSingleMessage(ctx context.Context, message *domain.Message) error {
+// SendMessage delivers a single pending message through the webhook and records
+// the outcome. It returns errors.ErrMessageNotSendable, without calling the
+// webhook, for messages that are not pending or whose content is too long.
+// Messages held back by the send rate limit or rejected by the circuit breaker
+// stay pending: their claim is released and the rejection is returned. Failed
+// sends are handled by failure category; see recordFailure.
+func (s *message) SendMessage(ctx context.Context, message *domain.Message) error {
 	if !message.IsValidForSending() {
 		logger.Warn("Message is not valid for sending",
 			zap.String("message_id", message.ID.String()),
 			zap.String("status", string(message.Status)))
-		return nil
+		return errors.ErrMessageNotSendable
 	}
 
 	webhookRequest := domain.WebhookRequest{
@@ -113,17 +145,21 @@ func (s *message) sendSingleMessage(ctx context.Context, message *domain.Message
 	}
 
 	response, err := s.webhookService.SendMessage(ctx, webhookRequest)
-	if err != nil {
-		failureReason := err.Error()
-		if err := s.messageRepo.UpdateStatus(ctx, message.ID, messagestatus.Failed, nil, &failureReason); err != nil {
-			logger.Error("Failed to update message status to failed",
+	if isDeferral(err) {
+		// The release must outlive a cancelled send, or the message waits out its lease.
+		if err := s.messageRepo.ReleaseClaim(context.WithoutCancel(ctx), message.ID); err != nil {
+			logger.Warn("Failed to release claim on deferred message",
 				zap.Error(err),
 				zap.String("message_id", message.ID.String()))
 		}
 		return err
 	}
+	if err != nil {
+		s.recordFailure(ctx, message, err)
+		return err
+	}
 
-	if err := s.messageRepo.UpdateStatus(ctx, message.ID, messagestatus.Sent, &response.MessageID, nil); err != nil {
+	if err := s.messageRepo.UpdateStatus(ctx, message.ID, messagestatus.Sent, &response.MessageID, providerName(response.Provider), nil); err != nil {
 		logger.Error("Failed to update message status to sent",
 			zap.Error(err),
 			zap.String("message_id", message.ID.String()))
@@ -145,7 +181,83 @@ func (s *message) sendSingleMessage(ctx context.Context, message *domain.Message
 
 	logger.Info("Message sent successfully",
 		zap.String("message_id", message.ID.String()),
-		zap.String("webhook_message_id", response.MessageID))
+		zap.String("webhook_message_id", response.MessageID),
+		zap.String("provider", response.Provider))
 
 	return nil
 }
+
+// recordFailure stores the outcome of a failed send. A throttled message stays
+// pending until the provider's Retry-After has passed, without using up an
+// attempt. A transient failure keeps the message pending for another attempt
+// after the retry backoff, until MaxAttempts is reached. Any other failure, and
+// the last transient one, marks the message failed. The category is stored as
+// the message's failure code either way, and a failed message records the
+// provider that was tried last.
+func (s *message) recordFailure(ctx context.Context, message *domain.Message, sendErr error) {
+	failure := domain.MessageFailure{Code: webhook.Category(sendErr), Reason: sendErr.Error()}
+
+	var webhookErr *webhook.Error
+	if !stderrors.As(sendErr, &webhookErr) {
+		webhookErr = &webhook.Error{}
+	}
+
+	var (
+		delay          time.Duration
+		failedAttempts = message.FailedAttempts
+	)
+	switch {
+	case failure.Code == webhookfailures.Throttled:
+		delay = s.retry.Backoff
+		if webhookErr.RetryAfter > 0 {
+			delay = webhookErr.RetryAfter
+		}
+		if s.retry.MaxRetryAfter > 0 {
+			delay = min(delay, s.retry.MaxRetryAfter)
+		}
+	case failure.Code == webhookfailures.Transient && failedAttempts+1 < s.retry.MaxAttempts:
+		failedAttempts++
+		delay = s.retry.Backoff
+	default:
+		if err := s.messageRepo.UpdateStatus(ctx, message.ID, messagestatus.Failed, nil, providerName(webhookErr.Provider), &failure); err != nil {
+			logger.Error("Failed to update message status to failed",
+				zap.Error(err),
+				zap.String("message_id", message.ID.String()))
+		}
+		return
+	}
+
+	if err := s.messageRepo.RetryLater(ctx, message.ID, failedAttempts, time.Now().Add(delay), failure); err != nil {
+		logger.Error("Failed to schedule message retry",
+			zap.Error(err),
+			zap.String("message_id", message.ID.String()))
+		return
+	}
+	logger.Info("Message send will be retried",
+		zap.String("message_id", message.ID.String()),
+		zap.String("failure_code", failure.Code.String()),
+		zap.Int("failed_attempts", failedAttempts),
+		zap.Duration("retry_in", delay))
+}
+
+// providerName returns a pointer to name, or nil when no provider is known.
+func providerName(name string) *string {
+	if name == "" {
+		return nil
+	}
+	return &name
+}
+
+// isDeferral reports whether err rejected a send before it reached the webhook, so
+// the message can be retried later without counting as failed.
+func isDeferral(err error) bool {
+	return err == errors.ErrWebhookThrottled ||
+		err == errors.ErrWebhookCircuitOpen ||
+		err == errors.ErrWebhookCircuitHalfOpen
+}
+
+// isThrottled reports whether the provider asked for err's send to be retried
+// later, which the processor counts as deferred rather than failed.
+func isThrottled(err error) bool {
+	return err != nil && webhook.Category(err) == webhookfailures.Throttled
+}
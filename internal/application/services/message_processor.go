@@ -2,29 +2,235 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/webhook"
+	"insider-message-system/pkg/circuitbreaker"
+	"insider-message-system/pkg/errors"
 	"insider-message-system/pkg/logger"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+const (
+	DefaultProcessorConcurrency = 4
+	DefaultClaimLease           = 5 * time.Minute
+	DefaultDrainTimeout         = 30 * time.Second
+)
+
 type MessageProcessor interface {
-	ProcessMessages(ctx context.Context) error
+	ProcessMessages(ctx context.Context, batchSize int) (*domain.BatchResult, error)
 }
 
 type messageProcessor struct {
 	messageService Message
-	batchSize      int
+	webhookClient  webhook.Client
+	concurrency    int
+	claimLease     time.Duration
+	drainTimeout   time.Duration
 }
 
-func NewMessageProcessor(messageService Message, batchSize int) MessageProcessor {
+// NewMessageProcessor creates a processor that sends pending messages using at most
+// concurrency workers. Each batch is claimed for claimLease before it is sent, so
+// the lease must comfortably exceed the time it takes to send a batch. Sends in
+// flight when a cycle is cancelled get drainTimeout to finish. Non-positive values
+// fall back to DefaultProcessorConcurrency, DefaultClaimLease and
+// DefaultDrainTimeout.
+func NewMessageProcessor(messageService Message, concurrency int, claimLease, drainTimeout time.Duration) MessageProcessor {
+	return NewMessageProcessorWithCircuitBreaker(messageService, concurrency, claimLease, drainTimeout, nil)
+}
+
+// NewMessageProcessorWithCircuitBreaker creates a processor that checks the
+// circuit breaker of webhookClient before claiming work. While the breaker is open
+// nothing is claimed, and while it is half-open a single message is claimed to
+// serve as the trial call.
+func NewMessageProcessorWithCircuitBreaker(messageService Message, concurrency int, claimLease, drainTimeout time.Duration, webhookClient webhook.Client) MessageProcessor {
+	if concurrency <= 0 {
+		concurrency = DefaultProcessorConcurrency
+	}
+	if claimLease <= 0 {
+		claimLease = DefaultClaimLease
+	}
+	if drainTimeout <= 0 {
+		drainTimeout = DefaultDrainTimeout
+	}
+
 	return &messageProcessor{
 		messageService: messageService,
-		batchSize:      batchSize,
+		webhookClient:  webhookClient,
+		concurrency:    concurrency,
+		claimLease:     claimLease,
+		drainTimeout:   drainTimeout,
+	}
+}
+
+// ProcessMessages claims up to batchSize pending messages and sends them through a
+// bounded worker pool. Claimed messages are invisible to other cycles and instances
+// until their status changes or the claim lease expires. A failure to send one message never affects the others;
+// the returned error is reserved for failing to fetch the batch or for the
+// context being cancelled before every message was attempted.
+//
+// Cancelling ctx drains the batch: no further messages are dispatched and their
+// claims are released, while sends already in flight get the drain timeout to
+// finish, so a message the provider accepted is still marked sent. Sends cut off
+// by the deadline keep their claim until the lease expires, since the provider
+// may have accepted them. Both are counted as unsent. When the webhook
+// circuit breaker is open, nothing is claimed and errors.ErrWebhookCircuitOpen is
+// returned.
+func (p *messageProcessor) ProcessMessages(ctx context.Context, batchSize int) (*domain.BatchResult, error) {
+	logger.Debug("Processing messages",
+		zap.Int("batch_size", batchSize),
+		zap.Int("concurrency", p.concurrency))
+
+	if p.webhookClient != nil {
+		switch p.webhookClient.GetCircuitBreakerState() {
+		case circuitbreaker.StateOpen:
+			logger.Warn("Webhook circuit breaker is open, not claiming messages")
+			return &domain.BatchResult{}, errors.ErrWebhookCircuitOpen
+		case circuitbreaker.StateHalfOpen:
+			// The breaker lets one trial call through; the rest of a batch would
+			// only be rejected.
+			logger.Info("Webhook circuit breaker is half-open, claiming a single trial message")
+			batchSize = min(batchSize, 1)
+		}
+	}
+
+	messages, err := p.messageService.ClaimPendingMessages(ctx, batchSize, p.claimLease)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.BatchResult{Fetched: len(messages)}
+	if len(messages) == 0 {
+		logger.Debug("No pending messages to send")
+		return result, nil
+	}
+
+	logger.Info("Processing pending messages", zap.Int("count", len(messages)))
+
+	// Sends outlive ctx by up to the drain timeout.
+	sendCtx, cancelSends := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelSends()
+	stopDrain := context.AfterFunc(ctx, func() {
+		time.AfterFunc(p.drainTimeout, cancelSends)
+	})
+	defer stopDrain()
+
+	workers := min(p.concurrency, len(messages))
+	jobs := make(chan *domain.Message)
+
+	var (
+		mu          sync.Mutex
+		wg          sync.WaitGroup
+		unsent      []uuid.UUID
+		sendLatency time.Duration
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for message := range jobs {
+				start := time.Now()
+				err := p.send(sendCtx, message)
+				elapsed := time.Since(start)
+
+				mu.Lock()
+				switch {
+				case err == nil:
+					result.Sent++
+					sendLatency += elapsed
+				case sendCtx.Err() != nil:
+					unsent = append(unsent, message.ID)
+				case err == errors.ErrMessageNotSendable:
+					result.Skipped++
+				case isDeferral(err) || isThrottled(err):
+					result.Deferred++
+				default:
+					result.Failed++
+					sendLatency += elapsed
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	dispatched := 0
+dispatch:
+	for _, message := range messages {
+		if ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case jobs <- message:
+			dispatched++
+		}
+	}
+	close(jobs)
+
+	for _, message := range messages[dispatched:] {
+		p.releaseClaim(ctx, message)
+		unsent = append(unsent, message.ID)
+	}
+	wg.Wait()
+
+	result.Unsent = len(unsent)
+	if reached := result.Sent + result.Failed; reached > 0 {
+		result.SendLatency = sendLatency / time.Duration(reached)
+	}
+
+	logger.Info("Finished processing pending messages",
+		zap.Int("fetched", result.Fetched),
+		zap.Int("sent", result.Sent),
+		zap.Int("failed", result.Failed),
+		zap.Int("skipped", result.Skipped),
+		zap.Int("deferred", result.Deferred),
+		zap.Int("unsent", result.Unsent))
+
+	if len(unsent) > 0 {
+		ids := make([]string, len(unsent))
+		for i, id := range unsent {
+			ids[i] = id.String()
+		}
+		logger.Warn("Processing cycle was cancelled, messages left unsent", zap.Strings("message_ids", ids))
+		return result, ctx.Err()
+	}
+
+	return result, nil
+}
+
+// releaseClaim makes a message that was never dispatched available to the next
+// cycle right away instead of after the claim lease.
+func (p *messageProcessor) releaseClaim(ctx context.Context, message *domain.Message) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := p.messageService.ReleaseClaim(ctx, message.ID); err != nil {
+		logger.Warn("Failed to release claim on unsent message",
+			zap.Error(err),
+			zap.String("message_id", message.ID.String()))
 	}
 }
 
-func (p *messageProcessor) ProcessMessages(ctx context.Context) error {
-	logger.Debug("Processing messages", zap.Int("batch_size", p.batchSize))
+// send delivers a single message, converting a panic into an error so that one
+// misbehaving message cannot take down the worker or the rest of the batch.
+func (p *messageProcessor) send(ctx context.Context, message *domain.Message) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic while sending message: %v", r)
+		}
+		if err != nil && err != errors.ErrMessageNotSendable && !isDeferral(err) && !isThrottled(err) {
+			logger.Error("Failed to send message",
+				zap.Error(err),
+				zap.String("message_id", message.ID.String()))
+		}
+	}()
 
-	return p.messageService.SendPendingMessages(ctx, p.batchSize)
+	return p.messageService.SendMessage(ctx, message)
 }
@@ -0,0 +1,432 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/circuitbreaker"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
+	customerrors "insider-message-system/pkg/errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubMessageService lets the worker pool tests control how long each send takes
+// and what it returns without going through the webhook and repository mocks.
+type stubMessageService struct {
+	Message
+	pending []*domain.Message
+	send    func(ctx context.Context, message *domain.Message) error
+
+	mu       sync.Mutex
+	released []uuid.UUID
+}
+
+func (s *stubMessageService) ClaimPendingMessages(ctx context.Context, limit int, lease time.Duration) ([]*domain.Message, error) {
+	return s.pending, nil
+}
+
+func (s *stubMessageService) SendMessage(ctx context.Context, message *domain.Message) error {
+	return s.send(ctx, message)
+}
+
+func (s *stubMessageService) ReleaseClaim(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.released = append(s.released, id)
+	return nil
+}
+
+func pendingMessages(n int) []*domain.Message {
+	messages := make([]*domain.Message, n)
+	for i := range messages {
+		messages[i] = &domain.Message{
+			ID:        uuid.New(),
+			To:        "+905551111111",
+			Content:   "Test message",
+			Status:    messagestatus.Pending,
+			CreatedAt: time.Now(),
+		}
+	}
+	return messages
+}
+
+// withoutLatency returns result with SendLatency cleared, so that counts can be
+// compared exactly.
+func withoutLatency(result *domain.BatchResult) *domain.BatchResult {
+	if result == nil {
+		return nil
+	}
+	counts := *result
+	counts.SendLatency = 0
+	return &counts
+}
+
+func messageIDs(messages []*domain.Message) []uuid.UUID {
+	ids := make([]uuid.UUID, len(messages))
+	for i, message := range messages {
+		ids[i] = message.ID
+	}
+	return ids
+}
+
+func TestMessageProcessor_ProcessMessages(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int
+		setupMocks  func(*mockMessageRepository, *mockWebhookService, *mockCacheService)
+		expected    *domain.BatchResult
+		expectError bool
+	}{
+		{
+			name:  "no pending messages",
+			limit: 2,
+			setupMocks: func(repo *mockMessageRepository, webhook *mockWebhookService, cache *mockCacheService) {
+				repo.On("ClaimPendingMessages", mock.Anything, 2, DefaultClaimLease).Return([]*domain.Message{}, nil)
+			},
+			expected: &domain.BatchResult{},
+		},
+		{
+			name:  "successful message sending",
+			limit: 1,
+			setupMocks: func(repo *mockMessageRepository, webhook *mockWebhookService, cache *mockCacheService) {
+				message := pendingMessages(1)[0]
+
+				repo.On("ClaimPendingMessages", mock.Anything, 1, DefaultClaimLease).Return([]*domain.Message{message}, nil)
+
+				webhookResponse := &domain.MessageResponse{
+					Message:   "Accepted",
+					MessageID: "test-message-id",
+				}
+				webhook.On("SendMessage", mock.Anything, mock.AnythingOfType("domain.WebhookRequest")).Return(webhookResponse, nil)
+
+				repo.On("UpdateStatus", mock.Anything, message.ID, messagestatus.Sent, &webhookResponse.MessageID, (*string)(nil), (*domain.MessageFailure)(nil)).Return(nil)
+
+				cache.On("SetMessageCache", mock.Anything, message.ID, mock.AnythingOfType("domain.CacheEntry")).Return(nil)
+			},
+			expected: &domain.BatchResult{Fetched: 1, Sent: 1},
+		},
+		{
+			name:  "webhook error",
+			limit: 1,
+			setupMocks: func(repo *mockMessageRepository, webhook *mockWebhookService, cache *mockCacheService) {
+				message := pendingMessages(1)[0]
+
+				repo.On("ClaimPendingMessages", mock.Anything, 1, DefaultClaimLease).Return([]*domain.Message{message}, nil)
+
+				webhook.On("SendMessage", mock.Anything, mock.AnythingOfType("domain.WebhookRequest")).Return((*domain.MessageResponse)(nil), errors.New("webhook error"))
+
+				failure := &domain.MessageFailure{Code: webhookfailures.Transient, Reason: "webhook error"}
+				repo.On("UpdateStatus", mock.Anything, message.ID, messagestatus.Failed, (*string)(nil), (*string)(nil), failure).Return(nil)
+			},
+			expected: &domain.BatchResult{Fetched: 1, Failed: 1},
+		},
+		{
+			name:  "provider throttling is deferred",
+			limit: 1,
+			setupMocks: func(repo *mockMessageRepository, webhook *mockWebhookService, cache *mockCacheService) {
+				message := pendingMessages(1)[0]
+
+				repo.On("ClaimPendingMessages", mock.Anything, 1, DefaultClaimLease).Return([]*domain.Message{message}, nil)
+
+				sendErr := webhookError(webhookfailures.Throttled, 429, time.Minute)
+				webhook.On("SendMessage", mock.Anything, mock.AnythingOfType("domain.WebhookRequest")).Return((*domain.MessageResponse)(nil), sendErr)
+
+				failure := domain.MessageFailure{Code: webhookfailures.Throttled, Reason: sendErr.Error()}
+				repo.On("RetryLater", mock.Anything, message.ID, 0, mock.AnythingOfType("time.Time"), failure).Return(nil)
+			},
+			expected: &domain.BatchResult{Fetched: 1, Deferred: 1},
+		},
+		{
+			name:  "invalid message is skipped",
+			limit: 1,
+			setupMocks: func(repo *mockMessageRepository, webhook *mockWebhookService, cache *mockCacheService) {
+				message := pendingMessages(1)[0]
+				message.Status = messagestatus.Sent
+
+				repo.On("ClaimPendingMessages", mock.Anything, 1, DefaultClaimLease).Return([]*domain.Message{message}, nil)
+			},
+			expected: &domain.BatchResult{Fetched: 1, Skipped: 1},
+		},
+		{
+			name:  "repository error",
+			limit: 1,
+			setupMocks: func(repo *mockMessageRepository, webhook *mockWebhookService, cache *mockCacheService) {
+				repo.On("ClaimPendingMessages", mock.Anything, 1, DefaultClaimLease).Return([]*domain.Message(nil), errors.New("db error"))
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockMessageRepository{}
+			mockWebhook := &mockWebhookService{}
+			mockCache := &mockCacheService{}
+
+			tt.setupMocks(mockRepo, mockWebhook, mockCache)
+
+			processor := NewMessageProcessor(NewMessage(mockRepo, mockWebhook, mockCache), 2, 0, 0)
+
+			result, err := processor.ProcessMessages(context.Background(), tt.limit)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, withoutLatency(result))
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockWebhook.AssertExpectations(t)
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMessageProcessor_UpdateStatusFails(t *testing.T) {
+	mockRepo := &mockMessageRepository{}
+	mockWebhook := &mockWebhookService{}
+	mockCache := &mockCacheService{}
+
+	message := pendingMessages(1)[0]
+	mockRepo.On("ClaimPendingMessages", mock.Anything, 1, DefaultClaimLease).Return([]*domain.Message{message}, nil)
+	webhookResponse := &domain.MessageResponse{Message: "Accepted", MessageID: "test-message-id"}
+	mockWebhook.On("SendMessage", mock.Anything, mock.AnythingOfType("domain.WebhookRequest")).Return(webhookResponse, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, message.ID, messagestatus.Sent, &webhookResponse.MessageID, (*string)(nil), (*domain.MessageFailure)(nil)).Return(errors.New("update error"))
+	mockCache.On("SetMessageCache", mock.Anything, message.ID, mock.AnythingOfType("domain.CacheEntry")).Return(nil).Maybe()
+
+	processor := NewMessageProcessor(NewMessage(mockRepo, mockWebhook, mockCache), 1, 0, 0)
+	result, err := processor.ProcessMessages(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.BatchResult{Fetched: 1, Failed: 1}, withoutLatency(result))
+	mockRepo.AssertExpectations(t)
+	mockWebhook.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestMessageProcessor_CacheServiceNil(t *testing.T) {
+	mockRepo := &mockMessageRepository{}
+	mockWebhook := &mockWebhookService{}
+
+	message := pendingMessages(1)[0]
+	mockRepo.On("ClaimPendingMessages", mock.Anything, 1, DefaultClaimLease).Return([]*domain.Message{message}, nil)
+	webhookResponse := &domain.MessageResponse{Message: "Accepted", MessageID: "test-message-id"}
+	mockWebhook.On("SendMessage", mock.Anything, mock.AnythingOfType("domain.WebhookRequest")).Return(webhookResponse, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, message.ID, messagestatus.Sent, &webhookResponse.MessageID, (*string)(nil), (*domain.MessageFailure)(nil)).Return(nil)
+
+	processor := NewMessageProcessor(NewMessage(mockRepo, mockWebhook, nil), 1, 0, 0)
+	result, err := processor.ProcessMessages(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.BatchResult{Fetched: 1, Sent: 1}, withoutLatency(result))
+	mockRepo.AssertExpectations(t)
+	mockWebhook.AssertExpectations(t)
+}
+
+func TestMessageProcessor_CircuitBreakerOpen(t *testing.T) {
+	mockRepo := &mockMessageRepository{}
+	mockWebhook := &mockWebhookService{}
+	mockWebhook.On("GetCircuitBreakerState").Return(circuitbreaker.StateOpen)
+
+	processor := NewMessageProcessorWithCircuitBreaker(NewMessage(mockRepo, mockWebhook, nil), 2, 0, 0, mockWebhook)
+	result, err := processor.ProcessMessages(context.Background(), 10)
+
+	assert.Equal(t, customerrors.ErrWebhookCircuitOpen, err)
+	assert.Equal(t, &domain.BatchResult{}, withoutLatency(result))
+	mockRepo.AssertNotCalled(t, "ClaimPendingMessages", mock.Anything, mock.Anything, mock.Anything)
+	mockWebhook.AssertExpectations(t)
+}
+
+func TestMessageProcessor_CircuitBreakerHalfOpenClaimsOneMessage(t *testing.T) {
+	mockRepo := &mockMessageRepository{}
+	mockWebhook := &mockWebhookService{}
+
+	message := pendingMessages(1)[0]
+	mockWebhook.On("GetCircuitBreakerState").Return(circuitbreaker.StateHalfOpen)
+	mockRepo.On("ClaimPendingMessages", mock.Anything, 1, DefaultClaimLease).Return([]*domain.Message{message}, nil)
+	webhookResponse := &domain.MessageResponse{Message: "Accepted", MessageID: "test-message-id"}
+	mockWebhook.On("SendMessage", mock.Anything, mock.AnythingOfType("domain.WebhookRequest")).Return(webhookResponse, nil)
+	mockRepo.On("UpdateStatus", mock.Anything, message.ID, messagestatus.Sent, &webhookResponse.MessageID, (*string)(nil), (*domain.MessageFailure)(nil)).Return(nil)
+
+	processor := NewMessageProcessorWithCircuitBreaker(NewMessage(mockRepo, mockWebhook, nil), 2, 0, 0, mockWebhook)
+	result, err := processor.ProcessMessages(context.Background(), 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.BatchResult{Fetched: 1, Sent: 1}, withoutLatency(result))
+	mockRepo.AssertExpectations(t)
+	mockWebhook.AssertExpectations(t)
+}
+
+func TestMessageProcessor_CircuitBreakerRejectionIsDeferred(t *testing.T) {
+	mockRepo := &mockMessageRepository{}
+	mockWebhook := &mockWebhookService{}
+
+	messages := pendingMessages(2)
+	mockWebhook.On("GetCircuitBreakerState").Return(circuitbreaker.StateClosed)
+	mockRepo.On("ClaimPendingMessages", mock.Anything, 2, DefaultClaimLease).Return(messages, nil)
+	// The breaker opens while the batch is being sent.
+	mockWebhook.On("SendMessage", mock.Anything, mock.AnythingOfType("domain.WebhookRequest")).Return((*domain.MessageResponse)(nil), customerrors.ErrWebhookCircuitOpen)
+	mockRepo.On("ReleaseClaim", mock.Anything, messages[0].ID).Return(nil)
+	mockRepo.On("ReleaseClaim", mock.Anything, messages[1].ID).Return(nil)
+
+	processor := NewMessageProcessorWithCircuitBreaker(NewMessage(mockRepo, mockWebhook, nil), 2, 0, 0, mockWebhook)
+	result, err := processor.ProcessMessages(context.Background(), 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.BatchResult{Fetched: 2, Deferred: 2}, withoutLatency(result))
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMessageProcessor_ConcurrencyIsBounded(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	svc := &stubMessageService{
+		pending: pendingMessages(12),
+		send: func(ctx context.Context, message *domain.Message) error {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		},
+	}
+
+	result, err := NewMessageProcessor(svc, 3, 0, 0).ProcessMessages(context.Background(), 12)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.BatchResult{Fetched: 12, Sent: 12}, withoutLatency(result))
+	assert.Equal(t, int32(3), peak.Load())
+	assert.GreaterOrEqual(t, result.SendLatency, 20*time.Millisecond)
+}
+
+func TestMessageProcessor_SlowMessageDoesNotBlockOthers(t *testing.T) {
+	messages := pendingMessages(5)
+	slow := messages[0].ID
+	release := make(chan struct{})
+
+	var sent atomic.Int32
+	svc := &stubMessageService{
+		pending: messages,
+		send: func(ctx context.Context, message *domain.Message) error {
+			if message.ID == slow {
+				<-release
+			}
+			sent.Add(1)
+			return nil
+		},
+	}
+
+	done := make(chan *domain.BatchResult)
+	go func() {
+		result, _ := NewMessageProcessor(svc, 2, 0, 0).ProcessMessages(context.Background(), 5)
+		done <- result
+	}()
+
+	assert.Eventually(t, func() bool { return sent.Load() == 4 }, time.Second, 5*time.Millisecond)
+	close(release)
+
+	result := <-done
+	assert.Equal(t, &domain.BatchResult{Fetched: 5, Sent: 5}, withoutLatency(result))
+}
+
+func TestMessageProcessor_ErrorsAreIsolated(t *testing.T) {
+	messages := pendingMessages(5)
+	svc := &stubMessageService{
+		pending: messages,
+		send: func(ctx context.Context, message *domain.Message) error {
+			switch message.ID {
+			case messages[0].ID:
+				return errors.New("webhook error")
+			case messages[1].ID:
+				panic("boom")
+			case messages[2].ID:
+				return customerrors.ErrMessageNotSendable
+			case messages[3].ID:
+				return customerrors.ErrWebhookThrottled
+			}
+			return nil
+		},
+	}
+
+	result, err := NewMessageProcessor(svc, 2, 0, 0).ProcessMessages(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.BatchResult{Fetched: 5, Sent: 1, Failed: 2, Skipped: 1, Deferred: 1}, withoutLatency(result))
+}
+
+func TestMessageProcessor_CancellationDrainsInFlightSends(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	messages := pendingMessages(10)
+	svc := &stubMessageService{
+		pending: messages,
+		send: func(sendCtx context.Context, message *domain.Message) error {
+			cancel()
+			// The send outlives the cancelled cycle.
+			select {
+			case <-sendCtx.Done():
+				return sendCtx.Err()
+			case <-time.After(20 * time.Millisecond):
+				return nil
+			}
+		},
+	}
+
+	result, err := NewMessageProcessor(svc, 1, 0, time.Second).ProcessMessages(ctx, 10)
+	assert.ErrorIs(t, err, context.Canceled)
+	require.NotNil(t, result)
+	assert.Equal(t, 10, result.Fetched)
+	assert.Equal(t, 1, result.Sent)
+	assert.Zero(t, result.Failed)
+	assert.Equal(t, 9, result.Unsent)
+
+	// Messages that were never dispatched are released for the next cycle.
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	assert.ElementsMatch(t, messageIDs(messages[1:]), svc.released)
+}
+
+func TestMessageProcessor_DrainDeadlineCutsOffSends(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	svc := &stubMessageService{
+		pending: pendingMessages(1),
+		send: func(sendCtx context.Context, message *domain.Message) error {
+			cancel()
+			<-sendCtx.Done()
+			return sendCtx.Err()
+		},
+	}
+
+	result, err := NewMessageProcessor(svc, 1, 0, 10*time.Millisecond).ProcessMessages(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+	require.NotNil(t, result)
+	assert.Zero(t, result.Failed)
+	assert.Equal(t, 1, result.Unsent)
+
+	// The provider may have accepted the message, so it keeps its claim.
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	assert.Empty(t, svc.released)
+}
+
+func TestNewMessageProcessor_Defaults(t *testing.T) {
+	processor := NewMessageProcessor(&stubMessageService{}, 0, 0, 0)
+	assert.Equal(t, DefaultProcessorConcurrency, processor.(*messageProcessor).concurrency)
+	assert.Equal(t, DefaultClaimLease, processor.(*messageProcessor).claimLease)
+	assert.Equal(t, DefaultDrainTimeout, processor.(*messageProcessor).drainTimeout)
+}
@@ -4,8 +4,14 @@ import (
 	"context"
 	"errors"
 	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/notifier"
+	"insider-message-system/internal/infrastructure/webhook"
 	"insider-message-system/pkg/circuitbreaker"
+	"insider-message-system/pkg/config"
 	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/statsintervals"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
+	customerrors "insider-message-system/pkg/errors"
 	"testing"
 	"time"
 
@@ -28,6 +34,16 @@ func (m *mockMessageRepository) GetPendingMessages(ctx context.Context, limit in
 	return args.Get(0).([]*domain.Message), args.Error(1)
 }
 
+func (m *mockMessageRepository) ReleaseClaim(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *mockMessageRepository) ClaimPendingMessages(ctx context.Context, limit int, lease time.Duration) ([]*domain.Message, error) {
+	args := m.Called(ctx, limit, lease)
+	return args.Get(0).([]*domain.Message), args.Error(1)
+}
+
 func (m *mockMessageRepository) GetSentMessages(ctx context.Context, offset, limit int) ([]*domain.Message, error) {
 	args := m.Called(ctx, offset, limit)
 	return args.Get(0).([]*domain.Message), args.Error(1)
@@ -38,9 +54,21 @@ func (m *mockMessageRepository) UpdateStatus(
 	id uuid.UUID,
 	status messagestatus.MessageStatus,
 	messageID *string,
-	failureReason *string,
+	provider *string,
+	failure *domain.MessageFailure,
 ) error {
-	args := m.Called(ctx, id, status, messageID, failureReason)
+	args := m.Called(ctx, id, status, messageID, provider, failure)
+	return args.Error(0)
+}
+
+func (m *mockMessageRepository) RetryLater(
+	ctx context.Context,
+	id uuid.UUID,
+	failedAttempts int,
+	until time.Time,
+	failure domain.MessageFailure,
+) error {
+	args := m.Called(ctx, id, failedAttempts, until, failure)
 	return args.Error(0)
 }
 
@@ -54,6 +82,50 @@ func (m *mockMessageRepository) GetTotalSentCount(ctx context.Context) (int64, e
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *mockMessageRepository) CountPending(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockMessageRepository) GetExpiredMessages(
+	ctx context.Context,
+	status messagestatus.MessageStatus,
+	before time.Time,
+	limit int,
+) ([]*domain.Message, error) {
+	args := m.Called(ctx, status, before, limit)
+	return args.Get(0).([]*domain.Message), args.Error(1)
+}
+
+func (m *mockMessageRepository) DeleteByIDs(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockMessageRepository) Restore(ctx context.Context, messages []*domain.Message) (int64, error) {
+	args := m.Called(ctx, messages)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockMessageRepository) CountByStatus(ctx context.Context, from, to time.Time) (domain.StatusCounts, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).(domain.StatusCounts), args.Error(1)
+}
+
+func (m *mockMessageRepository) GetStatusTimeSeries(
+	ctx context.Context,
+	from, to time.Time,
+	interval statsintervals.StatsInterval,
+) ([]domain.TimeSeriesPoint, error) {
+	args := m.Called(ctx, from, to, interval)
+	return args.Get(0).([]domain.TimeSeriesPoint), args.Error(1)
+}
+
+func (m *mockMessageRepository) GetSendLatency(ctx context.Context, from, to time.Time) (*domain.LatencyStats, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).(*domain.LatencyStats), args.Error(1)
+}
+
 type mockWebhookService struct {
 	mock.Mock
 }
@@ -161,96 +233,6 @@ func TestMessageService_CreateMessage(t *testing.T) {
 	}
 }
 
-func TestMessageService_SendPendingMessages(t *testing.T) {
-	tests := []struct {
-		name        string
-		limit       int
-		setupMocks  func(*mockMessageRepository, *mockWebhookService, *mockCacheService)
-		expectError bool
-	}{
-		{
-			name:  "no pending messages",
-			limit: 2,
-			setupMocks: func(repo *mockMessageRepository, webhook *mockWebhookService, cache *mockCacheService) {
-				repo.On("GetPendingMessages", mock.Anything, 2).Return([]*domain.Message{}, nil)
-			},
-			expectError: false,
-		},
-		{
-			name:  "successful message sending",
-			limit: 1,
-			setupMocks: func(repo *mockMessageRepository, webhook *mockWebhookService, cache *mockCacheService) {
-				message := &domain.Message{
-					ID:        uuid.New(),
-					To:        "+905551111111",
-					Content:   "Test message",
-					Status:    messagestatus.Pending,
-					CreatedAt: time.Now(),
-				}
-
-				repo.On("GetPendingMessages", mock.Anything, 1).Return([]*domain.Message{message}, nil)
-
-				webhookResponse := &domain.MessageResponse{
-					Message:   "Accepted",
-					MessageID: "test-message-id",
-				}
-				webhook.On("SendMessage", mock.Anything, mock.AnythingOfType("domain.WebhookRequest")).Return(webhookResponse, nil)
-
-				repo.On("UpdateStatus", mock.Anything, message.ID, messagestatus.Sent, &webhookResponse.MessageID, (*string)(nil)).Return(nil)
-
-				cache.On("SetMessageCache", mock.Anything, message.ID, mock.AnythingOfType("domain.CacheEntry")).Return(nil)
-			},
-			expectError: false,
-		},
-		{
-			name:  "webhook error",
-			limit: 1,
-			setupMocks: func(repo *mockMessageRepository, webhook *mockWebhookService, cache *mockCacheService) {
-				message := &domain.Message{
-					ID:        uuid.New(),
-					To:        "+905551111111",
-					Content:   "Test message",
-					Status:    messagestatus.Pending,
-					CreatedAt: time.Now(),
-				}
-
-				repo.On("GetPendingMessages", mock.Anything, 1).Return([]*domain.Message{message}, nil)
-
-				webhook.On("SendMessage", mock.Anything, mock.AnythingOfType("domain.WebhookRequest")).Return((*domain.MessageResponse)(nil), errors.New("webhook error"))
-
-				failureReason := "webhook error"
-				repo.On("UpdateStatus", mock.Anything, message.ID, messagestatus.Failed, (*string)(nil), &failureReason).Return(nil)
-			},
-			expectError: false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mockRepo := &mockMessageRepository{}
-			mockWebhook := &mockWebhookService{}
-			mockCache := &mockCacheService{}
-
-			tt.setupMocks(mockRepo, mockWebhook, mockCache)
-
-			service := NewMessage(mockRepo, mockWebhook, mockCache)
-			ctx := context.Background()
-
-			err := service.SendPendingMessages(ctx, tt.limit)
-
-			if tt.expectError {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-			}
-
-			mockRepo.AssertExpectations(t)
-			mockWebhook.AssertExpectations(t)
-			mockCache.AssertExpectations(t)
-		})
-	}
-}
-
 func TestMessageService_GetSentMessages(t *testing.T) {
 	type args struct {
 		page     int
@@ -319,70 +301,185 @@ func TestMessageService_GetSentMessages(t *testing.T) {
 	}
 }
 
-func TestMessageService_SendPendingMessages_UpdateStatusFails(t *testing.T) {
+func TestMessageService_SendMessage_InvalidMessage(t *testing.T) {
 	mockRepo := &mockMessageRepository{}
 	mockWebhook := &mockWebhookService{}
 	mockCache := &mockCacheService{}
 
-	message := &domain.Message{
+	invalidMsg := &domain.Message{
 		ID:        uuid.New(),
 		To:        "+905551111111",
-		Content:   "Test message",
+		Content:   "This message is too long to be valid for sending because it exceeds the 160 character limit. Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua.",
 		Status:    messagestatus.Pending,
 		CreatedAt: time.Now(),
 	}
-	mockRepo.On("GetPendingMessages", mock.Anything, 1).Return([]*domain.Message{message}, nil)
-	webhookResponse := &domain.MessageResponse{Message: "Accepted", MessageID: "test-message-id"}
-	mockWebhook.On("SendMessage", mock.Anything, mock.AnythingOfType("domain.WebhookRequest")).Return(webhookResponse, nil)
-	mockRepo.On("UpdateStatus", mock.Anything, message.ID, messagestatus.Sent, &webhookResponse.MessageID, (*string)(nil)).Return(errors.New("update error"))
-	mockCache.On("SetMessageCache", mock.Anything, message.ID, mock.AnythingOfType("domain.CacheEntry")).Return(nil).Maybe()
 
 	service := NewMessage(mockRepo, mockWebhook, mockCache)
-	err := service.SendPendingMessages(context.Background(), 1)
-	assert.NoError(t, err)
+	// Should not panic or call webhook/repo
+	err := service.SendMessage(context.Background(), invalidMsg)
+	assert.Equal(t, customerrors.ErrMessageNotSendable, err)
 	mockRepo.AssertExpectations(t)
 	mockWebhook.AssertExpectations(t)
-	mockCache.AssertExpectations(t)
 }
 
-func TestMessageService_SendPendingMessages_CacheServiceNil(t *testing.T) {
+func TestMessageService_SendMessage_ThrottledStaysPending(t *testing.T) {
 	mockRepo := &mockMessageRepository{}
 	mockWebhook := &mockWebhookService{}
 
-	message := &domain.Message{
+	msg := &domain.Message{
 		ID:        uuid.New(),
 		To:        "+905551111111",
 		Content:   "Test message",
 		Status:    messagestatus.Pending,
 		CreatedAt: time.Now(),
 	}
-	mockRepo.On("GetPendingMessages", mock.Anything, 1).Return([]*domain.Message{message}, nil)
-	webhookResponse := &domain.MessageResponse{Message: "Accepted", MessageID: "test-message-id"}
-	mockWebhook.On("SendMessage", mock.Anything, mock.AnythingOfType("domain.WebhookRequest")).Return(webhookResponse, nil)
-	mockRepo.On("UpdateStatus", mock.Anything, message.ID, messagestatus.Sent, &webhookResponse.MessageID, (*string)(nil)).Return(nil)
+
+	mockWebhook.On("SendMessage", mock.Anything, mock.Anything).Return((*domain.MessageResponse)(nil), customerrors.ErrWebhookThrottled)
+	mockRepo.On("ReleaseClaim", mock.Anything, msg.ID).Return(nil)
 
 	service := NewMessage(mockRepo, mockWebhook, nil)
-	err := service.SendPendingMessages(context.Background(), 1)
-	assert.NoError(t, err)
+	err := service.SendMessage(context.Background(), msg)
+
+	assert.Equal(t, customerrors.ErrWebhookThrottled, err)
 	mockRepo.AssertExpectations(t)
-	mockWebhook.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
-func TestMessageService_sendSingleMessage_InvalidMessage(t *testing.T) {
-	mockRepo := &mockMessageRepository{}
-	mockWebhook := &mockWebhookService{}
-	mockCache := &mockCacheService{}
+func webhookError(category webhookfailures.WebhookFailure, statusCode int, retryAfter time.Duration) error {
+	return &webhook.Error{
+		Category:   category,
+		StatusCode: statusCode,
+		RetryAfter: retryAfter,
+		Err:        customerrors.NewError("WEBHOOK_ERROR", "Webhook request failed", 500),
+	}
+}
 
-	invalidMsg := &domain.Message{
-		ID:        uuid.New(),
-		To:        "+905551111111",
-		Content:   "This message is too long to be valid for sending because it exceeds the 160 character limit. Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua.",
-		Status:    messagestatus.Pending,
-		CreatedAt: time.Now(),
+func TestMessageService_SendMessage_FailureCategories(t *testing.T) {
+	retry := config.RetryConfig{MaxAttempts: 3, Backoff: time.Minute, MaxRetryAfter: time.Hour}
+
+	tests := []struct {
+		name           string
+		sendErr        error
+		failedAttempts int
+		wantStatus     bool
+		wantAttempts   int
+		wantDelay      time.Duration
+		wantCode       webhookfailures.WebhookFailure
+	}{
+		{name: "permanent fails at once", sendErr: webhookError(webhookfailures.Permanent, 400, 0), wantStatus: true, wantCode: webhookfailures.Permanent},
+		{name: "transient is retried", sendErr: webhookError(webhookfailures.Transient, 503, 0), failedAttempts: 1, wantAttempts: 2, wantDelay: time.Minute, wantCode: webhookfailures.Transient},
+		{name: "network error is retried", sendErr: errors.New("connection refused"), wantAttempts: 1, wantDelay: time.Minute, wantCode: webhookfailures.Transient},
+		{name: "last transient attempt fails", sendErr: webhookError(webhookfailures.Transient, 500, 0), failedAttempts: 2, wantStatus: true, wantCode: webhookfailures.Transient},
+		{name: "throttled waits for retry-after", sendErr: webhookError(webhookfailures.Throttled, 429, 30*time.Second), failedAttempts: 2, wantAttempts: 2, wantDelay: 30 * time.Second, wantCode: webhookfailures.Throttled},
+		{name: "throttled without retry-after backs off", sendErr: webhookError(webhookfailures.Throttled, 429, 0), wantDelay: time.Minute, wantCode: webhookfailures.Throttled},
+		{name: "retry-after is capped", sendErr: webhookError(webhookfailures.Throttled, 429, 24*time.Hour), wantDelay: time.Hour, wantCode: webhookfailures.Throttled},
 	}
 
-	service := NewMessage(mockRepo, mockWebhook, mockCache)
-	// Should not panic or call webhook/repo
-	err := service.(*message).sendSingleMessage(context.Background(), invalidMsg)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockMessageRepository{}
+			mockWebhook := &mockWebhookService{}
+
+			msg := &domain.Message{
+				ID:             uuid.New(),
+				To:             "+905551111111",
+				Content:        "Test message",
+				Status:         messagestatus.Pending,
+				CreatedAt:      time.Now(),
+				FailedAttempts: tt.failedAttempts,
+			}
+			failure := domain.MessageFailure{Code: tt.wantCode, Reason: tt.sendErr.Error()}
+
+			mockWebhook.On("SendMessage", mock.Anything, mock.Anything).Return((*domain.MessageResponse)(nil), tt.sendErr)
+			if tt.wantStatus {
+				mockRepo.On("UpdateStatus", mock.Anything, msg.ID, messagestatus.Failed, (*string)(nil), (*string)(nil), &failure).Return(nil)
+			} else {
+				mockRepo.On("RetryLater", mock.Anything, msg.ID, tt.wantAttempts, mock.AnythingOfType("time.Time"), failure).Return(nil)
+			}
+
+			service := NewMessageWithNotifier(mockRepo, mockWebhook, nil, nil, retry)
+			start := time.Now()
+			err := service.SendMessage(context.Background(), msg)
+
+			assert.Equal(t, tt.sendErr, err)
+			mockRepo.AssertExpectations(t)
+			if !tt.wantStatus {
+				mockRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				until := mockRepo.Calls[0].Arguments.Get(3).(time.Time)
+				assert.WithinDuration(t, start.Add(tt.wantDelay), until, time.Second)
+			}
+		})
+	}
+}
+
+func TestMessageService_SendMessage_RecordsProvider(t *testing.T) {
+	newMsg := func() *domain.Message {
+		return &domain.Message{ID: uuid.New(), To: "+905551111111", Content: "Test message", Status: messagestatus.Pending, CreatedAt: time.Now()}
+	}
+	provider := "backup"
+
+	t.Run("delivering provider", func(t *testing.T) {
+		mockRepo := &mockMessageRepository{}
+		mockWebhook := &mockWebhookService{}
+		msg := newMsg()
+
+		response := &domain.MessageResponse{Message: "Accepted", MessageID: "provider-id", Provider: provider}
+		mockWebhook.On("SendMessage", mock.Anything, mock.Anything).Return(response, nil)
+		mockRepo.On("UpdateStatus", mock.Anything, msg.ID, messagestatus.Sent, &response.MessageID, &provider, (*domain.MessageFailure)(nil)).Return(nil)
+
+		assert.NoError(t, NewMessage(mockRepo, mockWebhook, nil).SendMessage(context.Background(), msg))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("failing provider", func(t *testing.T) {
+		mockRepo := &mockMessageRepository{}
+		mockWebhook := &mockWebhookService{}
+		msg := newMsg()
+
+		sendErr := &webhook.Error{
+			Provider: provider,
+			Category: webhookfailures.Permanent,
+			Err:      customerrors.NewError("WEBHOOK_ERROR", "Webhook request failed", 500),
+		}
+		failure := &domain.MessageFailure{Code: webhookfailures.Permanent, Reason: sendErr.Error()}
+		mockWebhook.On("SendMessage", mock.Anything, mock.Anything).Return((*domain.MessageResponse)(nil), sendErr)
+		mockRepo.On("UpdateStatus", mock.Anything, msg.ID, messagestatus.Failed, (*string)(nil), &provider, failure).Return(nil)
+
+		assert.Equal(t, sendErr, NewMessage(mockRepo, mockWebhook, nil).SendMessage(context.Background(), msg))
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestMessageService_CreateMessage_NotifiesScheduler(t *testing.T) {
+	repo := &mockMessageRepository{}
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Message")).Return(nil)
+
+	wakeups := notifier.NewLocal()
+	service := NewMessageWithNotifier(repo, nil, nil, wakeups, config.RetryConfig{})
+
+	_, err := service.CreateMessage(context.Background(), domain.MessageRequest{To: "+905551111111", Content: "Test message"})
 	assert.NoError(t, err)
+
+	select {
+	case <-wakeups.Subscribe():
+	default:
+		t.Fatal("expected a wake-up signal after creating a message")
+	}
+}
+
+func TestMessageService_CreateMessage_FailureDoesNotNotify(t *testing.T) {
+	repo := &mockMessageRepository{}
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Message")).Return(errors.New("db down"))
+
+	wakeups := notifier.NewLocal()
+	service := NewMessageWithNotifier(repo, nil, nil, wakeups, config.RetryConfig{})
+
+	_, err := service.CreateMessage(context.Background(), domain.MessageRequest{To: "+905551111111", Content: "Test message"})
+	assert.Error(t, err)
+
+	select {
+	case <-wakeups.Subscribe():
+		t.Fatal("no wake-up expected when the message was not stored")
+	default:
+	}
 }
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"insider-message-system/internal/infrastructure/database/repos"
+	"insider-message-system/internal/infrastructure/outbox"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/errors"
+	"insider-message-system/pkg/logger"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// OutboxRelay defines the interface for the service that relays outbox events to
+// sinks. It is run periodically as the "outbox_relay" job of the JobRunner.
+type OutboxRelay interface {
+	RunOnce(ctx context.Context) (*OutboxRelayResult, error)
+}
+
+// OutboxRelayResult summarizes a single relay pass.
+type OutboxRelayResult struct {
+	Published map[string]int `json:"published"`
+	Failed    []string       `json:"failed"`
+	Deleted   int64          `json:"deleted"`
+}
+
+type outboxRelay struct {
+	config     config.OutboxConfig
+	outboxRepo repos.Outbox
+	sinks      []outbox.Sink
+}
+
+// NewOutboxRelay creates a new OutboxRelay that delivers events to the given sinks.
+func NewOutboxRelay(cfg config.OutboxConfig, outboxRepo repos.Outbox, sinks []outbox.Sink) OutboxRelay {
+	return &outboxRelay{
+		config:     cfg,
+		outboxRepo: outboxRepo,
+		sinks:      sinks,
+	}
+}
+
+// validate rejects settings that would make a pass spin.
+func (r *outboxRelay) validate() error {
+	if r.config.BatchSize <= 0 {
+		return errors.NewErrorWithDetails("INVALID_OUTBOX_CONFIG", "Invalid outbox configuration", "outbox.batch_size must be positive", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// RunOnce delivers undelivered events to every sink in ID order. Each delivery is
+// recorded per sink and event, so a failing sink stops at the failed event and
+// retries it on the next pass without holding back the others, and an event whose
+// transaction committed after higher IDs were relayed is still delivered. Delivery
+// is therefore at-least-once and ordered per message.
+func (r *outboxRelay) RunOnce(ctx context.Context) (*OutboxRelayResult, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+
+	result := &OutboxRelayResult{Published: make(map[string]int, len(r.sinks))}
+
+	for _, sink := range r.sinks {
+		published, err := r.relay(ctx, sink)
+		result.Published[sink.Name()] = published
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			logger.Warn("Outbox sink delivery failed",
+				zap.String("sink", sink.Name()),
+				zap.Error(err))
+			result.Failed = append(result.Failed, sink.Name())
+		}
+	}
+
+	if r.config.Cleanup && len(r.sinks) > 0 {
+		names := make([]string, len(r.sinks))
+		for i, sink := range r.sinks {
+			names[i] = sink.Name()
+		}
+
+		deleted, err := r.outboxRepo.DeleteDeliveredEvents(ctx, names)
+		if err != nil {
+			return result, err
+		}
+		result.Deleted = deleted
+	}
+
+	return result, nil
+}
+
+func (r *outboxRelay) relay(ctx context.Context, sink outbox.Sink) (int, error) {
+	published := 0
+	for {
+		events, err := r.outboxRepo.GetUndeliveredEvents(ctx, sink.Name(), r.config.BatchSize)
+		if err != nil {
+			return published, err
+		}
+
+		for _, event := range events {
+			if err := ctx.Err(); err != nil {
+				return published, err
+			}
+
+			if err := sink.Publish(ctx, event); err != nil {
+				return published, err
+			}
+
+			if err := r.outboxRepo.MarkDelivered(ctx, sink.Name(), event.ID); err != nil {
+				return published, err
+			}
+
+			published++
+		}
+
+		if len(events) < r.config.BatchSize {
+			return published, nil
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/outbox"
+	"insider-message-system/pkg/config"
+	customerrors "insider-message-system/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *mockOutboxRepository) GetUndeliveredEvents(ctx context.Context, sink string, limit int) ([]*domain.OutboxEvent, error) {
+	args := m.Called(ctx, sink, limit)
+	return args.Get(0).([]*domain.OutboxEvent), args.Error(1)
+}
+
+func (m *mockOutboxRepository) MarkDelivered(ctx context.Context, sink string, eventID int64) error {
+	args := m.Called(ctx, sink, eventID)
+	return args.Error(0)
+}
+
+func (m *mockOutboxRepository) DeleteDeliveredEvents(ctx context.Context, sinks []string) (int64, error) {
+	args := m.Called(ctx, sinks)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type mockSink struct {
+	mock.Mock
+	name string
+}
+
+func (m *mockSink) Name() string {
+	return m.name
+}
+
+func (m *mockSink) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	args := m.Called(ctx, event.ID)
+	return args.Error(0)
+}
+
+func newTestOutboxRelay(repo *mockOutboxRepository, cleanup bool, sinks ...outbox.Sink) OutboxRelay {
+	return NewOutboxRelay(config.OutboxConfig{
+		PollInterval: time.Second,
+		BatchSize:    2,
+		Cleanup:      cleanup,
+	}, repo, sinks)
+}
+
+func TestOutboxRelay_RunOnce_PublishesInOrderAndRecordsDeliveries(t *testing.T) {
+	repo := &mockOutboxRepository{}
+	sink := &mockSink{name: "audit"}
+
+	repo.On("GetUndeliveredEvents", mock.Anything, "audit", 2).Return([]*domain.OutboxEvent{{ID: 1}, {ID: 2}}, nil).Once()
+	repo.On("GetUndeliveredEvents", mock.Anything, "audit", 2).Return([]*domain.OutboxEvent{{ID: 3}}, nil).Once()
+
+	for _, id := range []int64{1, 2, 3} {
+		sink.On("Publish", mock.Anything, id).Return(nil).Once()
+		repo.On("MarkDelivered", mock.Anything, "audit", id).Return(nil).Once()
+	}
+
+	result, err := newTestOutboxRelay(repo, false, sink).RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Published["audit"])
+	assert.Empty(t, result.Failed)
+
+	repo.AssertExpectations(t)
+	sink.AssertExpectations(t)
+	repo.AssertNotCalled(t, "DeleteDeliveredEvents", mock.Anything, mock.Anything)
+}
+
+func TestOutboxRelay_RunOnce_DeliversEventCommittedOutOfOrder(t *testing.T) {
+	repo := &mockOutboxRepository{}
+	sink := &mockSink{name: "audit"}
+	relay := newTestOutboxRelay(repo, true, sink)
+
+	// Event 2 commits first. Event 1 is still in an open transaction and invisible.
+	repo.On("GetUndeliveredEvents", mock.Anything, "audit", 2).Return([]*domain.OutboxEvent{{ID: 2}}, nil).Once()
+	sink.On("Publish", mock.Anything, int64(2)).Return(nil).Once()
+	repo.On("MarkDelivered", mock.Anything, "audit", int64(2)).Return(nil).Once()
+	repo.On("DeleteDeliveredEvents", mock.Anything, []string{"audit"}).Return(int64(1), nil).Once()
+
+	result, err := relay.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Published["audit"])
+
+	// Event 1 becomes visible afterwards and is still delivered.
+	repo.On("GetUndeliveredEvents", mock.Anything, "audit", 2).Return([]*domain.OutboxEvent{{ID: 1}}, nil).Once()
+	sink.On("Publish", mock.Anything, int64(1)).Return(nil).Once()
+	repo.On("MarkDelivered", mock.Anything, "audit", int64(1)).Return(nil).Once()
+	repo.On("DeleteDeliveredEvents", mock.Anything, []string{"audit"}).Return(int64(1), nil).Once()
+
+	result, err = relay.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Published["audit"])
+
+	repo.AssertExpectations(t)
+	sink.AssertExpectations(t)
+}
+
+func TestOutboxRelay_RunOnce_FailingSinkStopsWithoutBlockingOthers(t *testing.T) {
+	repo := &mockOutboxRepository{}
+	healthy := &mockSink{name: "healthy"}
+	failing := &mockSink{name: "failing"}
+	events := []*domain.OutboxEvent{{ID: 1}}
+
+	repo.On("GetUndeliveredEvents", mock.Anything, "healthy", 2).Return(events, nil)
+	repo.On("GetUndeliveredEvents", mock.Anything, "failing", 2).Return(events, nil)
+
+	healthy.On("Publish", mock.Anything, int64(1)).Return(nil)
+	repo.On("MarkDelivered", mock.Anything, "healthy", int64(1)).Return(nil)
+	failing.On("Publish", mock.Anything, int64(1)).Return(errors.New("connection refused"))
+
+	repo.On("DeleteDeliveredEvents", mock.Anything, []string{"healthy", "failing"}).Return(int64(0), nil)
+
+	result, err := newTestOutboxRelay(repo, true, healthy, failing).RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Published["healthy"])
+	assert.Equal(t, 0, result.Published["failing"])
+	assert.Equal(t, []string{"failing"}, result.Failed)
+	assert.Equal(t, int64(0), result.Deleted)
+
+	repo.AssertNotCalled(t, "MarkDelivered", mock.Anything, "failing", mock.Anything)
+}
+
+func TestOutboxRelay_RunOnce_CleanupDeletesEventsDeliveredToEverySink(t *testing.T) {
+	repo := &mockOutboxRepository{}
+	first := &mockSink{name: "first"}
+	second := &mockSink{name: "second"}
+
+	repo.On("GetUndeliveredEvents", mock.Anything, mock.Anything, 2).Return([]*domain.OutboxEvent{}, nil)
+	repo.On("DeleteDeliveredEvents", mock.Anything, []string{"first", "second"}).Return(int64(3), nil)
+
+	result, err := newTestOutboxRelay(repo, true, first, second).RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), result.Deleted)
+
+	repo.AssertExpectations(t)
+}
+
+func TestOutboxRelay_RunOnce_RejectsInvalidConfig(t *testing.T) {
+	relay := NewOutboxRelay(config.OutboxConfig{PollInterval: time.Second}, &mockOutboxRepository{}, nil)
+
+	_, err := relay.RunOnce(context.Background())
+	var appErr *customerrors.Error
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "INVALID_OUTBOX_CONFIG", appErr.Code)
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"insider-message-system/internal/infrastructure/archive"
+	"insider-message-system/internal/infrastructure/database/repos"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/errors"
+	"insider-message-system/pkg/logger"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Retention defines the interface for the message retention service. It is run
+// periodically as the "retention" job of the JobRunner.
+type Retention interface {
+	RunOnce(ctx context.Context) (*RetentionResult, error)
+}
+
+// RetentionResult summarizes a single retention pass.
+type RetentionResult struct {
+	Archived map[messagestatus.MessageStatus]int64 `json:"archived"`
+	Files    []string                              `json:"files"`
+}
+
+type retention struct {
+	config      config.RetentionConfig
+	messageRepo repos.Message
+	archiver    archive.Archiver
+	now         func() time.Time
+}
+
+// NewRetention creates a new Retention service with the given dependencies.
+func NewRetention(cfg config.RetentionConfig, messageRepo repos.Message, archiver archive.Archiver) Retention {
+	return &retention{
+		config:      cfg,
+		messageRepo: messageRepo,
+		archiver:    archiver,
+		now:         time.Now,
+	}
+}
+
+// validate rejects settings that would make a pass spin.
+func (r *retention) validate() error {
+	if r.config.BatchSize <= 0 {
+		return errors.NewErrorWithDetails("INVALID_RETENTION_CONFIG", "Invalid retention configuration", "retention.batch_size must be positive", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// RunOnce archives and deletes every message that has outlived its status policy.
+// Sent messages are aged from when they were sent and the others from when they
+// were created.
+// Messages are processed in batches of config.BatchSize and each batch is only
+// deleted after its archive file has been written successfully.
+func (r *retention) RunOnce(ctx context.Context) (*RetentionResult, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+
+	result := &RetentionResult{Archived: make(map[messagestatus.MessageStatus]int64)}
+
+	for _, status := range r.policyStatuses() {
+		cutoff := r.now().Add(-r.config.Policies[status])
+
+		for {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+
+			messages, err := r.messageRepo.GetExpiredMessages(ctx, status, cutoff, r.config.BatchSize)
+			if err != nil {
+				return result, err
+			}
+
+			if len(messages) == 0 {
+				break
+			}
+
+			path, err := r.archiver.Write(status, messages)
+			if err != nil {
+				logger.Error("Failed to archive expired messages",
+					zap.Error(err),
+					zap.String("status", string(status)))
+				return result, err
+			}
+			result.Files = append(result.Files, path)
+
+			ids := make([]uuid.UUID, len(messages))
+			for i, message := range messages {
+				ids[i] = message.ID
+			}
+
+			deleted, err := r.messageRepo.DeleteByIDs(ctx, ids)
+			if err != nil {
+				return result, err
+			}
+			result.Archived[status] += deleted
+
+			if len(messages) < r.config.BatchSize {
+				break
+			}
+		}
+	}
+
+	logger.Info("Retention pass completed",
+		zap.Any("archived", result.Archived),
+		zap.Int("files", len(result.Files)))
+
+	return result, nil
+}
+
+func (r *retention) policyStatuses() []messagestatus.MessageStatus {
+	statuses := make([]messagestatus.MessageStatus, 0, len(r.config.Policies))
+	for status, ttl := range r.config.Policies {
+		if ttl == 0 {
+			// A zero TTL switches off a policy, including one set by default.
+			logger.Debug("Retention policy disabled", zap.String("status", string(status)))
+			continue
+		}
+		if !status.IsValid() || ttl < 0 {
+			logger.Warn("Ignoring invalid retention policy",
+				zap.String("status", string(status)),
+				zap.Duration("ttl", ttl))
+			continue
+		}
+		if status == messagestatus.Pending {
+			logger.Warn("Ignoring retention policy for pending messages")
+			continue
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i] < statuses[j] })
+	return statuses
+}
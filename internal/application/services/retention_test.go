@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	customerrors "insider-message-system/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockArchiver struct {
+	mock.Mock
+}
+
+func (m *mockArchiver) Write(status messagestatus.MessageStatus, messages []*domain.Message) (string, error) {
+	args := m.Called(status, messages)
+	return args.String(0), args.Error(1)
+}
+
+func newTestRetention(repo *mockMessageRepository, archiver *mockArchiver, now time.Time) *retention {
+	r := NewRetention(config.RetentionConfig{
+		Interval:  time.Hour,
+		BatchSize: 2,
+		Policies: map[messagestatus.MessageStatus]time.Duration{
+			messagestatus.Sent:    24 * time.Hour,
+			messagestatus.Failed:  48 * time.Hour,
+			messagestatus.Pending: time.Hour,
+		},
+	}, repo, archiver).(*retention)
+	r.now = func() time.Time { return now }
+	return r
+}
+
+func TestRetention_RunOnce(t *testing.T) {
+	now := time.Now()
+	repo := &mockMessageRepository{}
+	archiver := &mockArchiver{}
+
+	firstBatch := []*domain.Message{{ID: uuid.New()}, {ID: uuid.New()}}
+	secondBatch := []*domain.Message{{ID: uuid.New()}}
+	failedBatch := []*domain.Message{{ID: uuid.New()}}
+
+	repo.On("GetExpiredMessages", mock.Anything, messagestatus.Failed, now.Add(-48*time.Hour), 2).Return(failedBatch, nil).Once()
+	repo.On("GetExpiredMessages", mock.Anything, messagestatus.Sent, now.Add(-24*time.Hour), 2).Return(firstBatch, nil).Once()
+	repo.On("GetExpiredMessages", mock.Anything, messagestatus.Sent, now.Add(-24*time.Hour), 2).Return(secondBatch, nil).Once()
+
+	archiver.On("Write", messagestatus.Failed, failedBatch).Return("failed-1", nil)
+	archiver.On("Write", messagestatus.Sent, firstBatch).Return("sent-1", nil)
+	archiver.On("Write", messagestatus.Sent, secondBatch).Return("sent-2", nil)
+
+	repo.On("DeleteByIDs", mock.Anything, []uuid.UUID{failedBatch[0].ID}).Return(int64(1), nil)
+	repo.On("DeleteByIDs", mock.Anything, []uuid.UUID{firstBatch[0].ID, firstBatch[1].ID}).Return(int64(2), nil)
+	repo.On("DeleteByIDs", mock.Anything, []uuid.UUID{secondBatch[0].ID}).Return(int64(1), nil)
+
+	result, err := newTestRetention(repo, archiver, now).RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), result.Archived[messagestatus.Sent])
+	assert.Equal(t, int64(1), result.Archived[messagestatus.Failed])
+	assert.Equal(t, []string{"failed-1", "sent-1", "sent-2"}, result.Files)
+
+	repo.AssertExpectations(t)
+	archiver.AssertExpectations(t)
+	repo.AssertNotCalled(t, "GetExpiredMessages", mock.Anything, messagestatus.Pending, mock.Anything, mock.Anything)
+}
+
+func TestRetention_RunOnce_ArchiveErrorKeepsRows(t *testing.T) {
+	now := time.Now()
+	repo := &mockMessageRepository{}
+	archiver := &mockArchiver{}
+
+	batch := []*domain.Message{{ID: uuid.New()}}
+	repo.On("GetExpiredMessages", mock.Anything, messagestatus.Failed, mock.Anything, 2).Return(batch, nil)
+	archiver.On("Write", messagestatus.Failed, batch).Return("", errors.New("disk full"))
+
+	_, err := newTestRetention(repo, archiver, now).RunOnce(context.Background())
+	assert.Error(t, err)
+	repo.AssertNotCalled(t, "DeleteByIDs", mock.Anything, mock.Anything)
+}
+
+func TestRetention_RunOnce_RejectsInvalidConfig(t *testing.T) {
+	r := NewRetention(config.RetentionConfig{Interval: time.Hour}, &mockMessageRepository{}, &mockArchiver{})
+
+	_, err := r.RunOnce(context.Background())
+	var appErr *customerrors.Error
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "INVALID_RETENTION_CONFIG", appErr.Code)
+}
+
+func TestRetention_RunOnce_ZeroTTLDisablesPolicy(t *testing.T) {
+	now := time.Now()
+	repo := &mockMessageRepository{}
+	archiver := &mockArchiver{}
+
+	r := newTestRetention(repo, archiver, now)
+	r.config.Policies[messagestatus.Failed] = 0
+
+	repo.On("GetExpiredMessages", mock.Anything, messagestatus.Sent, now.Add(-24*time.Hour), 2).Return([]*domain.Message{}, nil).Once()
+
+	result, err := r.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, result.Archived)
+
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "GetExpiredMessages", mock.Anything, messagestatus.Failed, mock.Anything, mock.Anything)
+}
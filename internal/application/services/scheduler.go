@@ -2,9 +2,18 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/database/repos"
+	"insider-message-system/internal/infrastructure/election"
+	"insider-message-system/internal/infrastructure/notifier"
 	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/constants/enums/cycletriggers"
+	"insider-message-system/pkg/cron"
 	"insider-message-system/pkg/errors"
 	"insider-message-system/pkg/logger"
+	"net/http"
+	"os"
 	"sync"
 	"time"
 
@@ -17,32 +26,367 @@ type Scheduler interface {
 	Stop() error
 	IsRunning() bool
 	SetMessageProcessor(processor MessageProcessor)
+	SetNotifier(notifier notifier.Notifier)
+	SetElector(elector election.Elector)
+	SetBatchController(controller BatchController)
+	SetSettingsRepository(repo repos.SchedulerSettings)
+	SetStateRepository(repo repos.SchedulerState)
+	SetDesiredState(ctx context.Context, state domain.SchedulerDesiredState) error
+	Reconcile(ctx context.Context) error
+	LoadSettings(ctx context.Context) error
+	Settings() domain.SchedulerSettings
+	UpdateSettings(ctx context.Context, settings domain.SchedulerSettings) error
+	RunOnce(ctx context.Context, batchSize int) (*domain.BatchResult, error)
+	RunOnceAsync(ctx context.Context, batchSize int) error
+	Status() domain.SchedulerStatus
 }
 
+// DefaultSchedulerHistorySize is the number of recent cycles kept when
+// config.HistorySize is not set.
+const DefaultSchedulerHistorySize = 20
+
 type scheduler struct {
 	config    config.SchedulerConfig
-	ticker    *time.Ticker
+	schedule  *cron.Schedule
 	ctx       context.Context
 	cancel    context.CancelFunc
 	wg        sync.WaitGroup
 	running   bool
 	mu        sync.RWMutex
 	processor MessageProcessor
+	notifier  notifier.Notifier
+	elector   election.Elector
+	batches   BatchController
+
+	// clockMu guards ticker and timer, which only exist while this instance runs
+	// the periodic loop. Exactly one of them is set then: ticker fires every
+	// interval, timer is re-armed for the next fire time of the cron schedule.
+	clockMu sync.Mutex
+	ticker  *time.Ticker
+	timer   *time.Timer
+
+	// settingsMu guards the runtime-adjustable fields of config. It is separate
+	// from mu because Stop holds mu while waiting for an in-flight cycle to finish.
+	settingsMu   sync.RWMutex
+	settingsRepo repos.SchedulerSettings
+
+	// stateMu guards stateRepo and is held while the desired state is changed or
+	// reconciled, so that this instance converges to one state at a time.
+	stateMu   sync.Mutex
+	stateRepo repos.SchedulerState
+
+	// cycleMu is held for the duration of every processing cycle, periodic or
+	// manual, so that two cycles never work on the same pending messages.
+	cycleMu sync.Mutex
+
+	// asyncWg tracks the background manual run started by RunOnceAsync, and
+	// asyncCancel cancels it. Both are only touched with mu held, so Stop can
+	// cancel and wait for the run before the process releases its resources.
+	asyncWg     sync.WaitGroup
+	asyncCancel context.CancelFunc
+
+	// statusMu guards the bookkeeping reported by Status.
+	statusMu     sync.Mutex
+	startedAt    time.Time
+	nextRunAt    time.Time
+	history      []domain.SchedulerCycle
+	totals       domain.SchedulerTotals
+	desiredState *domain.SchedulerDesiredState
 }
 
 // NewScheduler creates a new Scheduler with the given configuration.
 func NewScheduler(config config.SchedulerConfig) Scheduler {
+	if config.HistorySize <= 0 {
+		config.HistorySize = DefaultSchedulerHistorySize
+	}
+	if config.InstanceID == "" {
+		config.InstanceID = defaultInstanceID()
+	}
+
 	return &scheduler{
 		config: config,
 	}
 }
 
+// defaultInstanceID identifies this process as <hostname>-<pid>, which is unique
+// across replicas as well as across processes sharing a host.
+func defaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "scheduler"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
 func (s *scheduler) SetMessageProcessor(processor MessageProcessor) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.processor = processor
 }
 
+// SetNotifier lets the scheduler run early when a wake-up signal arrives.
+// Signals received within config.Wakeup.Debounce of each other trigger a single
+// run, and the periodic ticker keeps running as a safety net.
+func (s *scheduler) SetNotifier(notifier notifier.Notifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifier = notifier
+}
+
+// SetElector makes the scheduler campaign for leadership while it is running and
+// run periodic cycles only while this instance is the leader, so that a single
+// replica runs them at a time.
+func (s *scheduler) SetElector(elector election.Elector) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.elector = elector
+}
+
+// SetBatchController makes periodic cycles use the batch size chosen by
+// controller instead of the configured one. Manual runs are not affected.
+func (s *scheduler) SetBatchController(controller BatchController) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = controller
+}
+
+// SetSettingsRepository makes UpdateSettings persist every change, and LoadSettings
+// restore the last saved settings, so that runtime changes survive restarts.
+func (s *scheduler) SetSettingsRepository(repo repos.SchedulerSettings) {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	s.settingsRepo = repo
+}
+
+// LoadSettings applies the persisted settings, if any, on top of the configured ones.
+// Stored settings that are out of bounds are ignored.
+func (s *scheduler) LoadSettings(ctx context.Context) error {
+	s.settingsMu.RLock()
+	repo := s.settingsRepo
+	s.settingsMu.RUnlock()
+
+	if repo == nil {
+		return nil
+	}
+
+	stored, err := repo.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		return nil
+	}
+
+	if err := stored.Validate(); err != nil {
+		logger.Warn("Ignoring invalid persisted scheduler settings", zap.Error(err))
+		return nil
+	}
+
+	s.apply(*stored)
+	logger.Info("Restored persisted scheduler settings",
+		zap.Duration("interval", stored.Interval),
+		zap.Int("batch_size", stored.BatchSize),
+		zap.Int("max_retries", stored.MaxRetries),
+		zap.Duration("retry_delay", stored.RetryDelay))
+	return nil
+}
+
+// Settings returns the settings the scheduler is currently using.
+func (s *scheduler) Settings() domain.SchedulerSettings {
+	s.settingsMu.RLock()
+	defer s.settingsMu.RUnlock()
+
+	return domain.SchedulerSettings{
+		Interval:   s.config.Interval,
+		BatchSize:  s.config.BatchSize,
+		MaxRetries: s.config.MaxRetries,
+		RetryDelay: s.config.RetryDelay,
+	}
+}
+
+// UpdateSettings validates and applies new settings. Batch size and retry settings
+// take effect from the next cycle; a changed interval resets the ticker, so the
+// next tick happens one new interval from now. When a settings repository is set,
+// the settings are persisted before they are applied.
+func (s *scheduler) UpdateSettings(ctx context.Context, settings domain.SchedulerSettings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	s.settingsMu.RLock()
+	repo := s.settingsRepo
+	s.settingsMu.RUnlock()
+
+	if repo != nil {
+		if err := repo.Save(ctx, settings); err != nil {
+			return err
+		}
+	}
+
+	s.apply(settings)
+	logger.Info("Scheduler settings updated",
+		zap.Duration("interval", settings.Interval),
+		zap.Int("batch_size", settings.BatchSize),
+		zap.Int("max_retries", settings.MaxRetries),
+		zap.Duration("retry_delay", settings.RetryDelay))
+	return nil
+}
+
+func (s *scheduler) apply(settings domain.SchedulerSettings) {
+	s.clockMu.Lock()
+	defer s.clockMu.Unlock()
+
+	s.settingsMu.Lock()
+	intervalChanged := s.config.Interval != settings.Interval
+	s.config.Interval = settings.Interval
+	s.config.BatchSize = settings.BatchSize
+	s.config.MaxRetries = settings.MaxRetries
+	s.config.RetryDelay = settings.RetryDelay
+	s.settingsMu.Unlock()
+
+	// Ticker.Reset is safe while the loop is receiving from the ticker channel,
+	// and no stale tick from the old interval is delivered afterwards. A cron
+	// schedule does not use the interval.
+	if intervalChanged && s.ticker != nil {
+		s.ticker.Reset(settings.Interval)
+		s.setNextRun(time.Now().Add(settings.Interval))
+	}
+}
+
+// SetStateRepository makes the desired running state shared: SetDesiredState
+// persists every change, and Reconcile starts or stops this instance to match the
+// stored state, so that every instance follows the last change made on any of them.
+func (s *scheduler) SetStateRepository(repo repos.SchedulerState) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	s.stateRepo = repo
+}
+
+// SetDesiredState records whether the scheduler should be running, persisting it
+// when a state repository is set, and starts or stops this instance to match.
+// When the desired state already is state.Running, nothing is recorded and it
+// returns ErrSchedulerAlreadyRunning or ErrSchedulerNotRunning, after bringing an
+// instance that has not caught up with the stored state in line with it.
+func (s *scheduler) SetDesiredState(ctx context.Context, state domain.SchedulerDesiredState) error {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	current, err := s.desiredStateLocked(ctx)
+	if err != nil {
+		return err
+	}
+	running := s.IsRunning()
+	if current != nil {
+		running = current.Running
+	}
+	if running == state.Running {
+		if current != nil {
+			s.setDesiredState(*current)
+			if err := s.converge(ctx, *current); err != nil {
+				return err
+			}
+		}
+		if state.Running {
+			return errors.ErrSchedulerAlreadyRunning
+		}
+		return errors.ErrSchedulerNotRunning
+	}
+
+	if state.ChangedAt.IsZero() {
+		state.ChangedAt = time.Now()
+	}
+
+	if s.stateRepo != nil {
+		if err := s.stateRepo.Save(ctx, state); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("Scheduler desired state changed",
+		zap.Bool("running", state.Running),
+		zap.String("changed_by", state.ChangedBy),
+		zap.String("reason", state.Reason))
+
+	s.setDesiredState(state)
+	return s.converge(ctx, state)
+}
+
+// Reconcile starts or stops this instance to match the persisted desired state.
+// When no state has been saved yet, scheduler.auto_start becomes the initial
+// state. It does nothing without a state repository.
+func (s *scheduler) Reconcile(ctx context.Context) error {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	if s.stateRepo == nil {
+		return nil
+	}
+
+	state, err := s.stateRepo.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		state, err = s.stateRepo.Initialize(ctx, domain.SchedulerDesiredState{
+			Running:   s.config.AutoStart,
+			ChangedBy: "config",
+			Reason:    "scheduler.auto_start",
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	s.setDesiredState(*state)
+	return s.converge(ctx, *state)
+}
+
+// converge must be called with stateMu held. The scheduler outlives the request
+// or reconcile pass that starts it, so it is started with a context that is not
+// cancelled along with ctx.
+func (s *scheduler) converge(ctx context.Context, state domain.SchedulerDesiredState) error {
+	running := s.IsRunning()
+
+	switch {
+	case state.Running && !running:
+		logger.Info("Starting scheduler to match desired state",
+			zap.String("changed_by", state.ChangedBy),
+			zap.String("reason", state.Reason))
+		return s.Start(context.WithoutCancel(ctx))
+
+	case !state.Running && running:
+		logger.Info("Stopping scheduler to match desired state",
+			zap.String("changed_by", state.ChangedBy),
+			zap.String("reason", state.Reason))
+		return s.Stop()
+	}
+
+	return nil
+}
+
+// desiredStateLocked must be called with stateMu held. It returns the stored
+// desired state, or without a state repository the last one set on this instance,
+// and nil when none has been recorded yet.
+func (s *scheduler) desiredStateLocked(ctx context.Context) (*domain.SchedulerDesiredState, error) {
+	if s.stateRepo != nil {
+		return s.stateRepo.Get(ctx)
+	}
+
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	if s.desiredState == nil {
+		return nil, nil
+	}
+	state := *s.desiredState
+	return &state, nil
+}
+
+func (s *scheduler) setDesiredState(state domain.SchedulerDesiredState) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.desiredState = &state
+}
+
 func (s *scheduler) Start(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -54,34 +398,114 @@ func (s *scheduler) Start(ctx context.Context) error {
 
 	if s.processor == nil {
 		logger.Error("Message processor not set")
-		return errors.NewError("PROCESSOR_NOT_SET", "Message processor not set", 500)
+		return errors.ErrProcessorNotSet
+	}
+
+	schedule, err := s.loadSchedule()
+	if err != nil {
+		logger.Error("Invalid scheduler cron configuration", zap.Error(err))
+		return err
+	}
+
+	now := time.Now()
+	if schedule != nil && schedule.Next(now).IsZero() {
+		return errors.NewErrorWithDetails("INVALID_SCHEDULER_CONFIG", "Invalid scheduler configuration", "scheduler.cron.expression never fires", http.StatusInternalServerError)
 	}
 
+	// schedule is only read by the loop goroutines, which Stop waits for before
+	// the scheduler can be started again.
+	s.schedule = schedule
 	s.ctx, s.cancel = context.WithCancel(ctx)
-	s.ticker = time.NewTicker(s.config.Interval)
 	s.running = true
 
+	s.statusMu.Lock()
+	s.startedAt = now
+	s.statusMu.Unlock()
+
+	var wakeups <-chan struct{}
+	if s.notifier != nil {
+		wakeups = s.notifier.Subscribe()
+	}
+
+	runCtx, elector := s.ctx, s.elector
+	lead := func(ctx context.Context) { s.lead(ctx, wakeups) }
+
 	s.wg.Add(1)
-	go s.run()
+	go func() {
+		defer s.wg.Done()
+		if elector != nil {
+			elector.Run(runCtx, lead)
+			return
+		}
+		lead(runCtx)
+	}()
 
-	logger.Info("Scheduler started successfully", zap.Duration("interval", s.config.Interval))
+	fields := []zap.Field{zap.Bool("leader_election", elector != nil)}
+	if schedule != nil {
+		fields = append(fields,
+			zap.String("cron", schedule.String()),
+			zap.String("timezone", schedule.Location().String()))
+	} else {
+		fields = append(fields, zap.Duration("interval", s.Settings().Interval))
+	}
+	logger.Info("Scheduler started successfully", fields...)
 	return nil
 }
 
+// loadSchedule parses the configured cron schedule and its holiday calendar. It
+// returns nil when no cron expression is set and the fixed interval applies.
+func (s *scheduler) loadSchedule() (*cron.Schedule, error) {
+	cfg := s.config.Cron
+	if cfg.Expression == "" {
+		return nil, nil
+	}
+
+	location := time.UTC
+	if cfg.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, errors.NewErrorWithDetails("INVALID_SCHEDULER_CONFIG", "Invalid scheduler configuration", fmt.Sprintf("scheduler.cron.timezone: %v", err), http.StatusInternalServerError)
+		}
+	}
+
+	schedule, err := cron.Parse(cfg.Expression, location)
+	if err != nil {
+		return nil, errors.NewErrorWithDetails("INVALID_SCHEDULER_CONFIG", "Invalid scheduler configuration", fmt.Sprintf("scheduler.cron.expression: %v", err), http.StatusInternalServerError)
+	}
+
+	if cfg.HolidaysFile != "" {
+		calendar, err := cron.LoadCalendar(cfg.HolidaysFile)
+		if err != nil {
+			return nil, errors.NewErrorWithDetails("INVALID_SCHEDULER_CONFIG", "Invalid scheduler configuration", fmt.Sprintf("scheduler.cron.holidays_file: %v", err), http.StatusInternalServerError)
+		}
+		schedule = schedule.WithCalendar(calendar)
+		logger.Info("Loaded scheduler holiday calendar",
+			zap.String("path", cfg.HolidaysFile),
+			zap.Int("holidays", calendar.Len()))
+	}
+
+	return schedule, nil
+}
+
+// Stop stops the periodic scheduler and cancels a background manual run, waiting
+// for both to finish. A cycle in progress is drained: it claims and dispatches no
+// further messages, and sends already in flight get the processor's drain timeout
+// to finish. It is safe to call when the scheduler is not running.
 func (s *scheduler) Stop() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.stopAsyncRun()
+
 	if !s.running {
 		logger.Warn("Scheduler is not running")
 		return nil
 	}
 
 	s.cancel()
-	s.ticker.Stop()
 	s.running = false
 
-	logger.Info("Stopping scheduler...")
+	logger.Info("Stopping scheduler, draining in-flight sends...")
 	s.wg.Wait()
 	logger.Info("Scheduler stopped successfully")
 
@@ -94,45 +518,339 @@ func (s *scheduler) IsRunning() bool {
 	return s.running
 }
 
-func (s *scheduler) run() {
-	defer s.wg.Done()
+// Status returns the scheduler's state together with its most recent cycles,
+// newest first, and counters accumulated since the process started.
+func (s *scheduler) Status() domain.SchedulerStatus {
+	s.mu.RLock()
+	running, elector, batches := s.running, s.elector, s.batches
+	s.mu.RUnlock()
+
+	var leaderID string
+	if elector != nil && running {
+		leaderID = elector.Leader()
+	}
+
+	var batchSizing *domain.BatchSizing
+	if batches != nil {
+		sizing := batches.Status()
+		batchSizing = &sizing
+	}
+
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	status := domain.SchedulerStatus{
+		Running:     running,
+		InstanceID:  s.config.InstanceID,
+		LeaderID:    leaderID,
+		Cycles:      make([]domain.SchedulerCycle, 0, len(s.history)),
+		Totals:      s.totals,
+		BatchSizing: batchSizing,
+	}
+
+	if s.desiredState != nil {
+		desiredState := *s.desiredState
+		status.DesiredState = &desiredState
+	}
+
+	for i := len(s.history) - 1; i >= 0; i-- {
+		status.Cycles = append(status.Cycles, s.history[i])
+	}
+
+	if running {
+		startedAt := s.startedAt
+		status.StartedAt = &startedAt
+		status.Uptime = time.Since(startedAt)
+		if !s.nextRunAt.IsZero() {
+			nextRunAt := s.nextRunAt
+			status.NextRunAt = &nextRunAt
+		}
+	}
+
+	return status
+}
+
+func (s *scheduler) setNextRun(at time.Time) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.nextRunAt = at
+}
+
+func (s *scheduler) recordCycle(cycle domain.SchedulerCycle) {
+	cycle.FinishedAt = time.Now()
+	cycle.DurationMs = cycle.FinishedAt.Sub(cycle.StartedAt).Milliseconds()
+
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	s.history = append(s.history, cycle)
+	if overflow := len(s.history) - s.config.HistorySize; overflow > 0 {
+		s.history = append(s.history[:0], s.history[overflow:]...)
+	}
+	s.totals.Add(cycle)
+}
+
+// RunOnce runs a single processing cycle right away, whether or not the periodic
+// scheduler is running. A non-positive batchSize uses the current batch size. It
+// returns errors.ErrSchedulerCycleRunning instead of waiting when another cycle is
+// in progress.
+func (s *scheduler) RunOnce(ctx context.Context, batchSize int) (*domain.BatchResult, error) {
+	s.mu.RLock()
+	processor := s.processor
+	s.mu.RUnlock()
+
+	if err := s.beginManualCycle(processor); err != nil {
+		return nil, err
+	}
+	defer s.cycleMu.Unlock()
+
+	return s.runManualCycle(ctx, processor, batchSize)
+}
+
+// RunOnceAsync starts a single processing cycle in the background and returns as
+// soon as it has been started. The cycle is not cancelled when ctx is, so it can
+// outlive the request that triggered it, but it is owned by the scheduler and is
+// cancelled and waited for by Stop.
+func (s *scheduler) RunOnceAsync(ctx context.Context, batchSize int) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	processor := s.processor
+	if err := s.beginManualCycle(processor); err != nil {
+		return err
+	}
+
+	// Holding cycleMu makes this the only caller that can reach this point, so
+	// asyncCancel is not written concurrently; Stop reads it under the write lock.
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	s.asyncCancel = cancel
+	s.asyncWg.Add(1)
+
+	go func() {
+		defer s.asyncWg.Done()
+		defer cancel()
+		defer s.cycleMu.Unlock()
+		s.runManualCycle(runCtx, processor, batchSize)
+	}()
+
+	return nil
+}
+
+// stopAsyncRun cancels the background manual run, if any, and waits for it to
+// finish. The caller must hold mu for writing.
+func (s *scheduler) stopAsyncRun() {
+	if s.asyncCancel != nil {
+		s.asyncCancel()
+		s.asyncCancel = nil
+	}
+	s.asyncWg.Wait()
+}
+
+func (s *scheduler) beginManualCycle(processor MessageProcessor) error {
+	if processor == nil {
+		return errors.ErrProcessorNotSet
+	}
+
+	if !s.cycleMu.TryLock() {
+		logger.Warn("Manual run refused, a processing cycle is already in progress")
+		return errors.ErrSchedulerCycleRunning
+	}
+
+	return nil
+}
+
+func (s *scheduler) runManualCycle(ctx context.Context, processor MessageProcessor, batchSize int) (*domain.BatchResult, error) {
+	if batchSize <= 0 {
+		batchSize = s.Settings().BatchSize
+	}
+
+	logger.Info("Running manual processing cycle", zap.Int("batch_size", batchSize))
+
+	cycle := domain.SchedulerCycle{Trigger: cycletriggers.Manual, StartedAt: time.Now(), BatchSize: batchSize}
+	result, err := processor.ProcessMessages(ctx, batchSize)
+	cycle.AddAttempt(result, err)
+	s.recordCycle(cycle)
+
+	if err != nil {
+		logger.Error("Manual processing cycle failed", zap.Error(err))
+		return result, err
+	}
+
+	logger.Info("Manual processing cycle finished",
+		zap.Int("fetched", result.Fetched),
+		zap.Int("sent", result.Sent),
+		zap.Int("failed", result.Failed),
+		zap.Int("skipped", result.Skipped))
+	return result, nil
+}
+
+// lead runs periodic cycles until ctx is cancelled: for as long as the scheduler
+// runs or, with leader election, for as long as this instance is the leader.
+func (s *scheduler) lead(ctx context.Context, wakeups <-chan struct{}) {
+	ticks := s.startClock()
+	defer s.stopClock()
+
+	s.run(ctx, ticks, wakeups)
+}
+
+// startClock starts the ticker, or with a cron schedule the timer, that drives
+// the periodic loop.
+func (s *scheduler) startClock() <-chan time.Time {
+	s.clockMu.Lock()
+	defer s.clockMu.Unlock()
+
+	now := time.Now()
+
+	if s.schedule != nil {
+		next := s.schedule.Next(now)
+		if next.IsZero() {
+			logger.Warn("Cron schedule has no upcoming fire time, periodic runs stop",
+				zap.String("cron", s.schedule.String()))
+			return nil
+		}
+		s.timer = time.NewTimer(time.Until(next))
+		s.setNextRun(next)
+		return s.timer.C
+	}
+
+	interval := s.Settings().Interval
+	s.ticker = time.NewTicker(interval)
+	s.setNextRun(now.Add(interval))
+	return s.ticker.C
+}
+
+func (s *scheduler) stopClock() {
+	s.clockMu.Lock()
+	defer s.clockMu.Unlock()
 
+	if s.ticker != nil {
+		s.ticker.Stop()
+		s.ticker = nil
+	}
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	s.setNextRun(time.Time{})
+}
+
+// run drives periodic cycles from ticks. With a cron schedule, ticks come from a
+// timer that is re-armed for the schedule's next fire time on every tick.
+func (s *scheduler) run(ctx context.Context, ticks <-chan time.Time, wakeups <-chan struct{}) {
 	logger.Info("Scheduler loop started")
 
+	// debounce is only non-nil while a wake-up run is pending.
+	var debounce <-chan time.Time
+
 	for {
 		select {
-		case <-s.ctx.Done():
+		case <-ctx.Done():
 			logger.Info("Scheduler context cancelled, stopping...")
 			return
 
-		case <-s.ticker.C:
-			s.processWithRetry()
+		case tick := <-ticks:
+			s.scheduleNextRun(tick)
+			s.processWithRetry(ctx, cycletriggers.Tick)
+
+		case <-wakeups:
+			if debounce == nil {
+				debounce = time.After(s.config.Wakeup.Debounce)
+			}
+
+		case <-debounce:
+			debounce = nil
+			logger.Debug("Scheduler woken up early")
+			s.processWithRetry(ctx, cycletriggers.Wakeup)
 		}
 	}
 }
 
-func (s *scheduler) processWithRetry() {
-	for attempt := 1; attempt <= s.config.MaxRetries; attempt++ {
-		err := s.processor.ProcessMessages(s.ctx)
+func (s *scheduler) scheduleNextRun(tick time.Time) {
+	s.clockMu.Lock()
+	defer s.clockMu.Unlock()
+
+	schedule := s.schedule
+	if schedule == nil {
+		s.setNextRun(tick.Add(s.Settings().Interval))
+		return
+	}
+
+	// Compute from the fire time the timer was armed for, so a timer that fires a
+	// little early by the wall clock cannot schedule the same slot twice.
+	s.statusMu.Lock()
+	from := s.nextRunAt
+	s.statusMu.Unlock()
+	if tick.After(from) {
+		from = tick
+	}
+
+	next := schedule.Next(from)
+	if next.IsZero() {
+		logger.Warn("Cron schedule has no upcoming fire time, periodic runs stop",
+			zap.String("cron", schedule.String()))
+		s.setNextRun(time.Time{})
+		return
+	}
+
+	s.timer.Reset(time.Until(next))
+	s.setNextRun(next)
+}
+
+func (s *scheduler) processWithRetry(ctx context.Context, trigger cycletriggers.CycleTrigger) {
+	if !s.cycleMu.TryLock() {
+		logger.Info("Skipping scheduled cycle, a manual run is in progress")
+		return
+	}
+	defer s.cycleMu.Unlock()
+
+	settings := s.Settings()
+
+	// The controller is only set up before the scheduler starts, so it can be
+	// read without holding mu, which Stop holds while waiting for this cycle.
+	batchSize := settings.BatchSize
+	if s.batches != nil {
+		batchSize = s.batches.Next(ctx)
+	}
+
+	cycle := domain.SchedulerCycle{Trigger: trigger, StartedAt: time.Now(), BatchSize: batchSize}
+	defer func() { s.recordCycle(cycle) }()
+
+	for attempt := 1; attempt <= settings.MaxRetries; attempt++ {
+		result, err := s.processor.ProcessMessages(ctx, batchSize)
+		cycle.AddAttempt(result, err)
+		if s.batches != nil {
+			s.batches.Observe(result, err)
+		}
+		if err == errors.ErrWebhookCircuitOpen {
+			// Retrying before the breaker lets a trial call through is pointless;
+			// back off until the next cycle.
+			logger.Warn("Skipping cycle, webhook circuit breaker is open")
+			return
+		}
 		if err == nil {
+			if result != nil && result.Failed > 0 {
+				logger.Warn("Some messages failed to send",
+					zap.Int("sent", result.Sent),
+					zap.Int("failed", result.Failed))
+			}
 			return
 		}
 
 		logger.Error("Message processing failed",
 			zap.Error(err),
 			zap.Int("attempt", attempt),
-			zap.Int("max_retries", s.config.MaxRetries))
+			zap.Int("max_retries", settings.MaxRetries))
 
-		if attempt < s.config.MaxRetries {
+		if attempt < settings.MaxRetries {
 			select {
-			case <-s.ctx.Done():
+			case <-ctx.Done():
 				return
-			case <-time.After(s.config.RetryDelay):
+			case <-time.After(settings.RetryDelay):
 				continue
 			}
 		}
 	}
 
 	logger.Error("All message processing attempts failed",
-		zap.Int("max_retries", s.config.MaxRetries))
+		zap.Int("max_retries", settings.MaxRetries))
 }
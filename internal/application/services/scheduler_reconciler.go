@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"insider-message-system/pkg/errors"
+	"insider-message-system/pkg/logger"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SchedulerReconciler defines the interface for the service that keeps this
+// instance's scheduler in line with the persisted desired state.
+type SchedulerReconciler interface {
+	Start(ctx context.Context) error
+	Stop() error
+}
+
+type schedulerReconciler struct {
+	scheduler Scheduler
+	interval  time.Duration
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+	running   bool
+	mu        sync.Mutex
+}
+
+// NewSchedulerReconciler creates a new SchedulerReconciler that reconciles the
+// scheduler every interval, so that a start or stop made on another instance is
+// followed here within one interval.
+func NewSchedulerReconciler(scheduler Scheduler, interval time.Duration) SchedulerReconciler {
+	return &schedulerReconciler{
+		scheduler: scheduler,
+		interval:  interval,
+	}
+}
+
+// Start reconciles once before returning, so that the scheduler is already in the
+// desired state when startup completes, then keeps reconciling in the background.
+// A failed first pass is logged and retried at the next interval.
+func (r *schedulerReconciler) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running {
+		logger.Warn("Scheduler reconciler is already running")
+		return nil
+	}
+
+	if r.interval <= 0 {
+		return errors.NewErrorWithDetails("INVALID_SCHEDULER_CONFIG", "Invalid scheduler configuration", "scheduler.reconcile_interval must be positive", http.StatusInternalServerError)
+	}
+
+	r.ctx, r.cancel = context.WithCancel(ctx)
+	r.running = true
+
+	r.reconcile()
+
+	r.wg.Add(1)
+	go r.run()
+
+	logger.Info("Scheduler reconciler started", zap.Duration("interval", r.interval))
+	return nil
+}
+
+func (r *schedulerReconciler) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.running {
+		return nil
+	}
+
+	r.cancel()
+	r.running = false
+	r.wg.Wait()
+
+	logger.Info("Scheduler reconciler stopped")
+	return nil
+}
+
+func (r *schedulerReconciler) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.reconcile()
+		}
+	}
+}
+
+func (r *schedulerReconciler) reconcile() {
+	if err := r.scheduler.Reconcile(r.ctx); err != nil && r.ctx.Err() == nil {
+		logger.Error("Failed to reconcile scheduler state", zap.Error(err))
+	}
+}
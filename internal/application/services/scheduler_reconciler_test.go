@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerReconciler_FollowsPersistedState(t *testing.T) {
+	repo := &memorySchedulerState{}
+	assert.NoError(t, repo.Save(context.Background(), domain.SchedulerDesiredState{Running: true, ChangedBy: "ops"}))
+
+	s := NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1})
+	s.SetMessageProcessor(new(mockProcessor))
+	s.SetStateRepository(repo)
+	defer s.Stop()
+
+	reconciler := NewSchedulerReconciler(s, 10*time.Millisecond)
+	assert.NoError(t, reconciler.Start(context.Background()))
+	defer reconciler.Stop()
+
+	// The first pass runs before Start returns.
+	assert.True(t, s.IsRunning())
+
+	// A stop made on another instance is followed at the next pass.
+	assert.NoError(t, repo.Save(context.Background(), domain.SchedulerDesiredState{Running: false, ChangedBy: "ops", Reason: "provider maintenance"}))
+	assert.Eventually(t, func() bool { return !s.IsRunning() }, time.Second, 5*time.Millisecond)
+
+	assert.NoError(t, reconciler.Stop())
+	assert.NoError(t, reconciler.Stop())
+}
+
+func TestSchedulerReconciler_RejectsInvalidInterval(t *testing.T) {
+	reconciler := NewSchedulerReconciler(NewScheduler(config.SchedulerConfig{}), 0)
+	assert.Error(t, reconciler.Start(context.Background()))
+}
@@ -3,20 +3,33 @@ package services
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/election"
+	"insider-message-system/internal/infrastructure/notifier"
 	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/constants/enums/batchdecisions"
+	"insider-message-system/pkg/constants/enums/cycletriggers"
+	"insider-message-system/pkg/cron"
+	pkgerrors "insider-message-system/pkg/errors"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type mockProcessor struct{ mock.Mock }
 
-func (m *mockProcessor) ProcessMessages(ctx context.Context) error {
-	args := m.Called(ctx)
-	return args.Error(0)
+func (m *mockProcessor) ProcessMessages(ctx context.Context, batchSize int) (*domain.BatchResult, error) {
+	args := m.Called(ctx, batchSize)
+	result, _ := args.Get(0).(*domain.BatchResult)
+	return result, args.Error(1)
 }
 
 func TestScheduler_StartStopStatus(t *testing.T) {
@@ -61,14 +74,14 @@ func TestScheduler_ProcessError(t *testing.T) {
 	s := NewScheduler(cfg)
 	s.SetMessageProcessor(processor)
 
-	processor.On("ProcessMessages", mock.Anything).Return(errors.New("fail")).Maybe()
+	processor.On("ProcessMessages", mock.Anything, mock.Anything).Return(nil, errors.New("fail")).Maybe()
 
 	err := s.Start(context.Background())
 	assert.NoError(t, err)
 	time.Sleep(5 * time.Millisecond)
 	err = s.Stop()
 	assert.NoError(t, err)
-	processor.AssertCalled(t, "ProcessMessages", mock.Anything)
+	processor.AssertCalled(t, "ProcessMessages", mock.Anything, mock.Anything)
 }
 
 func TestScheduler_Start_NoProcessorSet(t *testing.T) {
@@ -109,3 +122,684 @@ func TestScheduler_Stop_NotRunning(t *testing.T) {
 	// Not started
 	assert.NoError(t, s.Stop())
 }
+
+func TestScheduler_WakeupRunsBeforeTick(t *testing.T) {
+	processor := new(mockProcessor)
+	processed := make(chan struct{}, 10)
+	processor.On("ProcessMessages", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		processed <- struct{}{}
+	}).Return(&domain.BatchResult{}, nil)
+
+	wakeups := notifier.NewLocal()
+	s := NewScheduler(config.SchedulerConfig{
+		Interval:   time.Hour,
+		MaxRetries: 1,
+		Wakeup:     config.WakeupConfig{Debounce: 20 * time.Millisecond},
+	})
+	s.SetMessageProcessor(processor)
+	s.SetNotifier(wakeups)
+
+	assert.NoError(t, s.Start(context.Background()))
+	defer s.Stop()
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, wakeups.Notify(context.Background()))
+		time.Sleep(time.Millisecond)
+	}
+
+	select {
+	case <-processed:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler was not woken up")
+	}
+
+	// The burst of signals within the debounce window results in a single run.
+	select {
+	case <-processed:
+		t.Fatal("debounced wake-ups triggered more than one run")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+type mockSchedulerSettingsRepository struct{ mock.Mock }
+
+func (m *mockSchedulerSettingsRepository) Get(ctx context.Context) (*domain.SchedulerSettings, error) {
+	args := m.Called(ctx)
+	settings, _ := args.Get(0).(*domain.SchedulerSettings)
+	return settings, args.Error(1)
+}
+
+func (m *mockSchedulerSettingsRepository) Save(ctx context.Context, settings domain.SchedulerSettings) error {
+	return m.Called(ctx, settings).Error(0)
+}
+
+func TestScheduler_UpdateSettings_AppliesFromNextTick(t *testing.T) {
+	processor := new(mockProcessor)
+	batchSizes := make(chan int, 10)
+	processor.On("ProcessMessages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		batchSizes <- args.Int(1)
+	}).Return(&domain.BatchResult{}, nil)
+
+	s := NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1})
+	s.SetMessageProcessor(processor)
+	assert.NoError(t, s.Start(context.Background()))
+	defer s.Stop()
+
+	settings := domain.SchedulerSettings{Interval: time.Second, BatchSize: 50, MaxRetries: 2, RetryDelay: time.Second}
+	assert.NoError(t, s.UpdateSettings(context.Background(), settings))
+	assert.Equal(t, settings, s.Settings())
+
+	select {
+	case size := <-batchSizes:
+		assert.Equal(t, 50, size)
+	case <-time.After(3 * time.Second):
+		t.Fatal("ticker was not reset to the new interval")
+	}
+}
+
+func TestScheduler_UpdateSettings_RejectsOutOfBounds(t *testing.T) {
+	s := NewScheduler(config.SchedulerConfig{Interval: time.Minute, BatchSize: 2, MaxRetries: 3, RetryDelay: time.Second})
+	before := s.Settings()
+
+	err := s.UpdateSettings(context.Background(), domain.SchedulerSettings{Interval: time.Minute, BatchSize: 0, MaxRetries: 3})
+	assert.Error(t, err)
+	assert.Equal(t, before, s.Settings())
+}
+
+func TestScheduler_UpdateSettings_Persists(t *testing.T) {
+	repo := new(mockSchedulerSettingsRepository)
+	settings := domain.SchedulerSettings{Interval: 30 * time.Second, BatchSize: 10, MaxRetries: 3, RetryDelay: time.Second}
+	repo.On("Save", mock.Anything, settings).Return(nil).Once()
+	repo.On("Save", mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+	s := NewScheduler(config.SchedulerConfig{Interval: time.Minute, BatchSize: 2, MaxRetries: 3, RetryDelay: time.Second})
+	s.SetSettingsRepository(repo)
+
+	assert.NoError(t, s.UpdateSettings(context.Background(), settings))
+	assert.Equal(t, settings, s.Settings())
+
+	// A change that cannot be persisted is not applied either.
+	assert.Error(t, s.UpdateSettings(context.Background(), domain.SchedulerSettings{Interval: time.Minute, BatchSize: 5, MaxRetries: 1}))
+	assert.Equal(t, settings, s.Settings())
+	repo.AssertExpectations(t)
+}
+
+func TestScheduler_LoadSettings(t *testing.T) {
+	stored := &domain.SchedulerSettings{Interval: 45 * time.Second, BatchSize: 20, MaxRetries: 5, RetryDelay: 2 * time.Second}
+
+	repo := new(mockSchedulerSettingsRepository)
+	repo.On("Get", mock.Anything).Return(stored, nil)
+
+	s := NewScheduler(config.SchedulerConfig{Interval: time.Minute, BatchSize: 2, MaxRetries: 3, RetryDelay: time.Second})
+	s.SetSettingsRepository(repo)
+
+	assert.NoError(t, s.LoadSettings(context.Background()))
+	assert.Equal(t, *stored, s.Settings())
+}
+
+func TestScheduler_LoadSettings_IgnoresMissingAndInvalid(t *testing.T) {
+	cfg := config.SchedulerConfig{Interval: time.Minute, BatchSize: 2, MaxRetries: 3, RetryDelay: time.Second}
+
+	missing := new(mockSchedulerSettingsRepository)
+	missing.On("Get", mock.Anything).Return(nil, nil)
+	s := NewScheduler(cfg)
+	s.SetSettingsRepository(missing)
+	assert.NoError(t, s.LoadSettings(context.Background()))
+	assert.Equal(t, 2, s.Settings().BatchSize)
+
+	invalid := new(mockSchedulerSettingsRepository)
+	invalid.On("Get", mock.Anything).Return(&domain.SchedulerSettings{Interval: time.Millisecond, BatchSize: 5, MaxRetries: 1}, nil)
+	s = NewScheduler(cfg)
+	s.SetSettingsRepository(invalid)
+	assert.NoError(t, s.LoadSettings(context.Background()))
+	assert.Equal(t, time.Minute, s.Settings().Interval)
+}
+
+// memorySchedulerState is a SchedulerState repository shared by the schedulers
+// of a test, standing in for the row every instance reads.
+type memorySchedulerState struct {
+	mu    sync.Mutex
+	state *domain.SchedulerDesiredState
+}
+
+func (r *memorySchedulerState) Get(ctx context.Context) (*domain.SchedulerDesiredState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.state == nil {
+		return nil, nil
+	}
+	state := *r.state
+	return &state, nil
+}
+
+func (r *memorySchedulerState) Save(ctx context.Context, state domain.SchedulerDesiredState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.state = &state
+	return nil
+}
+
+func (r *memorySchedulerState) Initialize(ctx context.Context, state domain.SchedulerDesiredState) (*domain.SchedulerDesiredState, error) {
+	r.mu.Lock()
+	if r.state == nil {
+		r.state = &state
+	}
+	r.mu.Unlock()
+	return r.Get(ctx)
+}
+
+func TestScheduler_Reconcile_InitializesFromAutoStart(t *testing.T) {
+	processor := new(mockProcessor)
+	repo := &memorySchedulerState{}
+
+	s := NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1, AutoStart: true})
+	s.SetMessageProcessor(processor)
+	s.SetStateRepository(repo)
+	defer s.Stop()
+
+	assert.NoError(t, s.Reconcile(context.Background()))
+	assert.True(t, s.IsRunning())
+
+	status := s.Status()
+	if assert.NotNil(t, status.DesiredState) {
+		assert.True(t, status.DesiredState.Running)
+		assert.Equal(t, "config", status.DesiredState.ChangedBy)
+	}
+
+	// auto_start only sets the initial state: a stored stop wins over it.
+	other := NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1, AutoStart: true})
+	other.SetMessageProcessor(processor)
+	other.SetStateRepository(repo)
+	assert.NoError(t, other.SetDesiredState(context.Background(), domain.SchedulerDesiredState{Running: false, ChangedBy: "ops", Reason: "provider maintenance"}))
+	assert.False(t, other.IsRunning())
+
+	assert.NoError(t, s.Reconcile(context.Background()))
+	assert.False(t, s.IsRunning())
+	assert.Equal(t, "provider maintenance", s.Status().DesiredState.Reason)
+}
+
+func TestScheduler_SetDesiredState_WithoutRepository(t *testing.T) {
+	processor := new(mockProcessor)
+	s := NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1})
+	s.SetMessageProcessor(processor)
+
+	assert.NoError(t, s.Reconcile(context.Background()))
+	assert.False(t, s.IsRunning())
+	assert.Nil(t, s.Status().DesiredState)
+
+	assert.NoError(t, s.SetDesiredState(context.Background(), domain.SchedulerDesiredState{Running: true, ChangedBy: "ops"}))
+	assert.True(t, s.IsRunning())
+	assert.Equal(t, "ops", s.Status().DesiredState.ChangedBy)
+	assert.False(t, s.Status().DesiredState.ChangedAt.IsZero())
+
+	assert.NoError(t, s.SetDesiredState(context.Background(), domain.SchedulerDesiredState{Running: false, ChangedBy: "ops"}))
+	assert.False(t, s.IsRunning())
+}
+
+func TestScheduler_SetDesiredState_ComparesStoredState(t *testing.T) {
+	processor := new(mockProcessor)
+	repo := &memorySchedulerState{}
+	ctx := context.Background()
+
+	s := NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1})
+	s.SetMessageProcessor(processor)
+	s.SetStateRepository(repo)
+	defer s.Stop()
+	other := NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1})
+	other.SetMessageProcessor(processor)
+	other.SetStateRepository(repo)
+	defer other.Stop()
+
+	assert.NoError(t, s.SetDesiredState(ctx, domain.SchedulerDesiredState{Running: true, ChangedBy: "ops"}))
+	assert.True(t, s.IsRunning())
+
+	// The other instance has not reconciled yet, but the stored state already is
+	// running: the request is rejected, and the instance catches up.
+	assert.False(t, other.IsRunning())
+	assert.Equal(t, pkgerrors.ErrSchedulerAlreadyRunning, other.SetDesiredState(ctx, domain.SchedulerDesiredState{Running: true, ChangedBy: "other"}))
+	assert.True(t, other.IsRunning())
+	stored, err := repo.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ops", stored.ChangedBy)
+
+	// Stopping from an instance that still runs stops every instance, even one
+	// that has not caught up and would otherwise refuse.
+	assert.NoError(t, other.SetDesiredState(ctx, domain.SchedulerDesiredState{Running: false, ChangedBy: "other"}))
+	assert.True(t, s.IsRunning())
+	assert.Equal(t, pkgerrors.ErrSchedulerNotRunning, s.SetDesiredState(ctx, domain.SchedulerDesiredState{Running: false, ChangedBy: "ops"}))
+	assert.False(t, s.IsRunning())
+
+	// A stopped instance can start the scheduler for everyone again.
+	assert.NoError(t, s.SetDesiredState(ctx, domain.SchedulerDesiredState{Running: true, ChangedBy: "ops"}))
+	assert.True(t, s.IsRunning())
+}
+
+func TestScheduler_SetDesiredState_RejectsUnchangedState(t *testing.T) {
+	processor := new(mockProcessor)
+	s := NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1})
+	s.SetMessageProcessor(processor)
+	defer s.Stop()
+
+	assert.Equal(t, pkgerrors.ErrSchedulerNotRunning, s.SetDesiredState(context.Background(), domain.SchedulerDesiredState{Running: false}))
+	assert.Nil(t, s.Status().DesiredState)
+
+	assert.NoError(t, s.SetDesiredState(context.Background(), domain.SchedulerDesiredState{Running: true}))
+	assert.Equal(t, pkgerrors.ErrSchedulerAlreadyRunning, s.SetDesiredState(context.Background(), domain.SchedulerDesiredState{Running: true}))
+	assert.True(t, s.IsRunning())
+}
+
+// fixedBatchController always picks size and records what it observes.
+type fixedBatchController struct {
+	size     int
+	mu       sync.Mutex
+	observed int
+}
+
+func (c *fixedBatchController) Next(ctx context.Context) int { return c.size }
+
+func (c *fixedBatchController) Observe(result *domain.BatchResult, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.observed++
+}
+
+func (c *fixedBatchController) Status() domain.BatchSizing {
+	return domain.BatchSizing{BatchSize: c.size, Decision: batchdecisions.Hold}
+}
+
+func TestScheduler_BatchControllerSizesPeriodicCycles(t *testing.T) {
+	processor := new(mockProcessor)
+	batchSizes := make(chan int, 10)
+	processor.On("ProcessMessages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		batchSizes <- args.Int(1)
+	}).Return(&domain.BatchResult{}, nil)
+
+	controller := &fixedBatchController{size: 64}
+	s := NewScheduler(config.SchedulerConfig{Interval: 10 * time.Millisecond, BatchSize: 2, MaxRetries: 1})
+	s.SetMessageProcessor(processor)
+	s.SetBatchController(controller)
+	assert.NoError(t, s.Start(context.Background()))
+
+	assert.Equal(t, 64, <-batchSizes)
+	assert.NoError(t, s.Stop())
+
+	controller.mu.Lock()
+	assert.Positive(t, controller.observed)
+	controller.mu.Unlock()
+
+	status := s.Status()
+	if assert.NotNil(t, status.BatchSizing) {
+		assert.Equal(t, 64, status.BatchSizing.BatchSize)
+	}
+	assert.Equal(t, 64, status.Cycles[0].BatchSize)
+
+	// Manual runs keep using the configured batch size.
+	_, err := s.RunOnce(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, <-batchSizes)
+}
+
+func TestScheduler_RunOnce(t *testing.T) {
+	processor := new(mockProcessor)
+	processor.On("ProcessMessages", mock.Anything, 2).Return(&domain.BatchResult{Fetched: 1, Sent: 1}, nil).Once()
+	processor.On("ProcessMessages", mock.Anything, 25).Return(&domain.BatchResult{Fetched: 3, Sent: 3}, nil).Once()
+
+	s := NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1})
+	s.SetMessageProcessor(processor)
+
+	// The periodic scheduler does not have to be running.
+	result, err := s.RunOnce(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.BatchResult{Fetched: 1, Sent: 1}, result)
+
+	result, err = s.RunOnce(context.Background(), 25)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Sent)
+	assert.False(t, s.IsRunning())
+	processor.AssertExpectations(t)
+}
+
+func TestScheduler_RunOnce_ProcessorNotSet(t *testing.T) {
+	s := NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1})
+
+	_, err := s.RunOnce(context.Background(), 0)
+	assert.Equal(t, pkgerrors.ErrProcessorNotSet, err)
+	assert.Equal(t, pkgerrors.ErrProcessorNotSet, s.RunOnceAsync(context.Background(), 0))
+}
+
+func TestScheduler_RunOnce_RefusesOverlap(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	processor := new(mockProcessor)
+	processor.On("ProcessMessages", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Return(&domain.BatchResult{Fetched: 1, Sent: 1}, nil).Once()
+
+	s := NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1})
+	s.SetMessageProcessor(processor)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, s.RunOnceAsync(ctx, 0))
+	// Cancelling the triggering context does not stop an async run.
+	cancel()
+	<-started
+
+	_, err := s.RunOnce(context.Background(), 0)
+	assert.Equal(t, pkgerrors.ErrSchedulerCycleRunning, err)
+	assert.Equal(t, pkgerrors.ErrSchedulerCycleRunning, s.RunOnceAsync(context.Background(), 0))
+
+	close(release)
+	processor.On("ProcessMessages", mock.Anything, mock.Anything).Return(&domain.BatchResult{}, nil)
+	assert.Eventually(t, func() bool {
+		_, err := s.RunOnce(context.Background(), 0)
+		return err == nil
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestScheduler_TickSkippedDuringManualRun(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls atomic.Int32
+	processor := new(mockProcessor)
+	processor.On("ProcessMessages", mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+		if calls.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+	}).Return(&domain.BatchResult{}, nil)
+
+	s := NewScheduler(config.SchedulerConfig{Interval: 10 * time.Millisecond, BatchSize: 2, MaxRetries: 1})
+	s.SetMessageProcessor(processor)
+
+	assert.NoError(t, s.RunOnceAsync(context.Background(), 0))
+	<-started
+
+	assert.NoError(t, s.Start(context.Background()))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load(), "ticks must not overlap the manual run")
+
+	close(release)
+	assert.Eventually(t, func() bool { return calls.Load() > 1 }, time.Second, 5*time.Millisecond)
+	assert.NoError(t, s.Stop())
+}
+
+func TestScheduler_Status_RecordsCycles(t *testing.T) {
+	processor := new(mockProcessor)
+	processor.On("ProcessMessages", mock.Anything, 2).Return(nil, errors.New("db down")).Once()
+	processor.On("ProcessMessages", mock.Anything, 2).Return(&domain.BatchResult{Fetched: 2, Sent: 1, Failed: 1}, nil)
+
+	s := NewScheduler(config.SchedulerConfig{
+		Interval:   10 * time.Millisecond,
+		BatchSize:  2,
+		MaxRetries: 3,
+		RetryDelay: time.Millisecond,
+		InstanceID: "test-instance",
+	})
+	s.SetMessageProcessor(processor)
+
+	assert.NoError(t, s.Start(context.Background()))
+	assert.Eventually(t, func() bool { return len(s.Status().Cycles) >= 2 }, time.Second, 5*time.Millisecond)
+
+	status := s.Status()
+	assert.True(t, status.Running)
+	assert.Equal(t, "test-instance", status.InstanceID)
+	assert.NotNil(t, status.StartedAt)
+	assert.NotNil(t, status.NextRunAt)
+	assert.True(t, status.NextRunAt.After(*status.StartedAt))
+
+	// Cycles are newest first, so the retried cycle is the last one.
+	first := status.Cycles[len(status.Cycles)-1]
+	assert.Equal(t, cycletriggers.Tick, first.Trigger)
+	assert.Equal(t, 2, first.Attempts)
+	assert.Equal(t, []string{"db down"}, first.Errors)
+	assert.Equal(t, 2, first.Fetched)
+	assert.Equal(t, 1, first.Sent)
+	assert.Equal(t, 1, first.Failed)
+	assert.True(t, first.Succeeded())
+	assert.False(t, first.FinishedAt.Before(first.StartedAt))
+
+	assert.NoError(t, s.Stop())
+
+	status = s.Status()
+	assert.False(t, status.Running)
+	assert.Nil(t, status.StartedAt)
+	assert.Nil(t, status.NextRunAt)
+	assert.Equal(t, int64(len(status.Cycles)), status.Totals.Cycles)
+	assert.Zero(t, status.Totals.FailedCycles)
+}
+
+func TestScheduler_CircuitOpenSkipsRetries(t *testing.T) {
+	processor := new(mockProcessor)
+	processor.On("ProcessMessages", mock.Anything, 2).Return(&domain.BatchResult{}, pkgerrors.ErrWebhookCircuitOpen)
+
+	s := NewScheduler(config.SchedulerConfig{
+		Interval:   10 * time.Millisecond,
+		BatchSize:  2,
+		MaxRetries: 3,
+		RetryDelay: time.Millisecond,
+	})
+	s.SetMessageProcessor(processor)
+
+	assert.NoError(t, s.Start(context.Background()))
+	assert.Eventually(t, func() bool { return len(s.Status().Cycles) >= 1 }, time.Second, 5*time.Millisecond)
+	assert.NoError(t, s.Stop())
+
+	// Every cycle gave up after its first attempt instead of retrying.
+	for _, cycle := range s.Status().Cycles {
+		assert.Equal(t, 1, cycle.Attempts)
+		assert.Equal(t, []string{pkgerrors.ErrWebhookCircuitOpen.Error()}, cycle.Errors)
+	}
+}
+
+func TestScheduler_Status_TrimsHistoryAndKeepsTotals(t *testing.T) {
+	processor := new(mockProcessor)
+	processor.On("ProcessMessages", mock.Anything, 2).Return(&domain.BatchResult{Fetched: 1, Sent: 1}, nil).Times(3)
+	processor.On("ProcessMessages", mock.Anything, 2).Return(nil, errors.New("fail")).Once()
+
+	s := NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1, HistorySize: 2})
+	s.SetMessageProcessor(processor)
+
+	for i := 0; i < 4; i++ {
+		_, _ = s.RunOnce(context.Background(), 0)
+	}
+
+	status := s.Status()
+	assert.Len(t, status.Cycles, 2)
+	assert.Equal(t, cycletriggers.Manual, status.Cycles[0].Trigger)
+	assert.Equal(t, []string{"fail"}, status.Cycles[0].Errors)
+	assert.False(t, status.Cycles[0].Succeeded())
+	assert.Equal(t, domain.SchedulerTotals{Cycles: 4, FailedCycles: 1, Fetched: 3, Sent: 3}, status.Totals)
+	assert.NotEmpty(t, status.InstanceID)
+	processor.AssertExpectations(t)
+}
+
+func TestScheduler_Stop_CancelsAndWaitsForAsyncRun(t *testing.T) {
+	started := make(chan struct{})
+	var finished atomic.Bool
+	processor := new(mockProcessor)
+	processor.On("ProcessMessages", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		close(started)
+		<-args.Get(0).(context.Context).Done()
+		finished.Store(true)
+	}).Return(&domain.BatchResult{}, context.Canceled).Once()
+
+	s := NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1})
+	s.SetMessageProcessor(processor)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, s.RunOnceAsync(ctx, 0))
+	// The request context ending does not stop the run.
+	cancel()
+	<-started
+	assert.False(t, finished.Load())
+
+	// Stop works even though the periodic scheduler was never started.
+	assert.NoError(t, s.Stop())
+	assert.True(t, finished.Load(), "Stop must wait for the async run")
+	assert.Len(t, s.Status().Cycles, 1)
+
+	// A new manual run can start after Stop.
+	processor.On("ProcessMessages", mock.Anything, mock.Anything).Return(&domain.BatchResult{}, nil).Once()
+	_, err := s.RunOnce(context.Background(), 0)
+	assert.NoError(t, err)
+	processor.AssertExpectations(t)
+}
+
+func TestScheduler_Start_CronSchedule(t *testing.T) {
+	holidays := filepath.Join(t.TempDir(), "holidays.txt")
+	assert.NoError(t, os.WriteFile(holidays, []byte("# none yet\n"), 0o600))
+
+	s := NewScheduler(config.SchedulerConfig{
+		Interval:   time.Millisecond,
+		BatchSize:  1,
+		MaxRetries: 1,
+		Cron: config.CronConfig{
+			Expression:   "0 9 * * MON-FRI",
+			Timezone:     "Europe/Istanbul",
+			HolidaysFile: holidays,
+		},
+	})
+	// The processor must not be called: the interval is ignored in favour of the cron schedule.
+	processor := new(mockProcessor)
+	s.SetMessageProcessor(processor)
+
+	before := time.Now()
+	assert.NoError(t, s.Start(context.Background()))
+	defer s.Stop()
+
+	location, err := time.LoadLocation("Europe/Istanbul")
+	assert.NoError(t, err)
+	schedule, err := cron.Parse("0 9 * * MON-FRI", location)
+	assert.NoError(t, err)
+
+	// The loop arms its timer as soon as it starts.
+	assert.Eventually(t, func() bool { return s.Status().NextRunAt != nil }, time.Second, time.Millisecond)
+	assert.True(t, schedule.Next(before).Equal(*s.Status().NextRunAt))
+
+	time.Sleep(20 * time.Millisecond)
+	processor.AssertNotCalled(t, "ProcessMessages", mock.Anything, mock.Anything)
+
+	// Changing the interval at runtime does not affect a cron schedule.
+	assert.NoError(t, s.UpdateSettings(context.Background(), domain.SchedulerSettings{
+		Interval: time.Second, BatchSize: 1, MaxRetries: 1, RetryDelay: time.Second,
+	}))
+	assert.True(t, schedule.Next(before).Equal(*s.Status().NextRunAt))
+}
+
+func TestScheduler_ScheduleNextRun_Cron(t *testing.T) {
+	schedule, err := cron.Parse("*/15 * * * *", time.UTC)
+	assert.NoError(t, err)
+
+	s := NewScheduler(config.SchedulerConfig{}).(*scheduler)
+	s.schedule = schedule
+	s.timer = time.NewTimer(time.Hour)
+	defer s.timer.Stop()
+
+	slot := time.Date(2025, 1, 15, 10, 15, 0, 0, time.UTC)
+	s.nextRunAt = slot
+
+	// A tick that arrives slightly before the slot by the wall clock still moves
+	// on to the following slot.
+	s.scheduleNextRun(slot.Add(-time.Millisecond))
+	assert.Equal(t, slot.Add(15*time.Minute), s.nextRunAt)
+
+	s.scheduleNextRun(slot.Add(40 * time.Minute))
+	assert.Equal(t, time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC), s.nextRunAt)
+}
+
+func TestScheduler_Start_InvalidCron(t *testing.T) {
+	tests := []struct {
+		name string
+		cron config.CronConfig
+	}{
+		{"expression", config.CronConfig{Expression: "0 25 * * *"}},
+		{"timezone", config.CronConfig{Expression: "0 9 * * *", Timezone: "Mars/Olympus_Mons"}},
+		{"holidays file", config.CronConfig{Expression: "0 9 * * *", HolidaysFile: filepath.Join(t.TempDir(), "missing.txt")}},
+		{"never fires", config.CronConfig{Expression: "0 0 30 2 *"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScheduler(config.SchedulerConfig{Interval: time.Second, MaxRetries: 1, Cron: tt.cron})
+			s.SetMessageProcessor(new(mockProcessor))
+
+			err := s.Start(context.Background())
+			var appErr *pkgerrors.Error
+			assert.ErrorAs(t, err, &appErr)
+			assert.Equal(t, "INVALID_SCHEDULER_CONFIG", appErr.Code)
+			assert.False(t, s.IsRunning())
+		})
+	}
+}
+
+// sharedLock is an election.Lock shared by schedulers in the same test.
+type sharedLock struct {
+	mu     sync.Mutex
+	holder string
+}
+
+func (l *sharedLock) Acquire(ctx context.Context, id string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.holder == "" {
+		l.holder = id
+	}
+	return l.holder, nil
+}
+
+func (l *sharedLock) Release(ctx context.Context, id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.holder == id {
+		l.holder = ""
+	}
+	return nil
+}
+
+func (l *sharedLock) Close() error { return nil }
+
+func TestScheduler_LeaderElection_OnlyLeaderRunsCycles(t *testing.T) {
+	lock := &sharedLock{}
+
+	newReplica := func(id string) (Scheduler, *atomic.Int32) {
+		var cycles atomic.Int32
+		processor := new(mockProcessor)
+		processor.On("ProcessMessages", mock.Anything, 1).
+			Run(func(mock.Arguments) { cycles.Add(1) }).
+			Return(&domain.BatchResult{}, nil)
+
+		s := NewScheduler(config.SchedulerConfig{
+			Interval:   5 * time.Millisecond,
+			BatchSize:  1,
+			MaxRetries: 1,
+			InstanceID: id,
+		})
+		s.SetMessageProcessor(processor)
+		s.SetElector(election.New(lock, id, time.Millisecond))
+		return s, &cycles
+	}
+
+	first, firstCycles := newReplica("first")
+	second, secondCycles := newReplica("second")
+
+	assert.NoError(t, first.Start(context.Background()))
+	assert.Eventually(t, func() bool { return firstCycles.Load() > 0 }, time.Second, time.Millisecond)
+
+	assert.NoError(t, second.Start(context.Background()))
+	defer second.Stop()
+	assert.Eventually(t, func() bool { return second.Status().LeaderID == "first" }, time.Second, time.Millisecond)
+
+	// The follower is running but does not run cycles or report a next run.
+	time.Sleep(20 * time.Millisecond)
+	assert.Zero(t, secondCycles.Load())
+	status := second.Status()
+	assert.True(t, status.Running)
+	assert.Nil(t, status.NextRunAt)
+
+	// Stopping the leader hands leadership over.
+	assert.NoError(t, first.Stop())
+	assert.Eventually(t, func() bool { return secondCycles.Load() > 0 }, time.Second, time.Millisecond)
+	assert.Equal(t, "second", second.Status().LeaderID)
+	assert.Empty(t, first.Status().LeaderID)
+}
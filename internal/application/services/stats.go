@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/database/repos"
+	"insider-message-system/pkg/constants/enums/statsintervals"
+	"insider-message-system/pkg/errors"
+	"insider-message-system/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// MaxStatsBuckets caps how many time series points a single stats request may produce.
+const MaxStatsBuckets = 24 * 93
+
+// Stats defines the interface for delivery statistics.
+type Stats interface {
+	GetStats(ctx context.Context, from, to time.Time, interval statsintervals.StatsInterval) (*domain.MessageStats, error)
+}
+
+type stats struct {
+	messageRepo repos.Message
+}
+
+// NewStats creates a new Stats service backed by the given message repository.
+func NewStats(messageRepo repos.Message) Stats {
+	return &stats{messageRepo: messageRepo}
+}
+
+func (s *stats) GetStats(ctx context.Context, from, to time.Time, interval statsintervals.StatsInterval) (*domain.MessageStats, error) {
+	from, to = from.UTC(), to.UTC()
+
+	if !from.Before(to) {
+		return nil, errors.ErrInvalidStatsRange
+	}
+
+	if to.Sub(from)/interval.Duration() > MaxStatsBuckets {
+		return nil, errors.ErrStatsRangeTooLarge
+	}
+
+	totals, err := s.messageRepo.CountByStatus(ctx, from, to)
+	if err != nil {
+		logger.Error("Failed to get status totals", zap.Error(err))
+		return nil, err
+	}
+
+	points, err := s.messageRepo.GetStatusTimeSeries(ctx, from, to, interval)
+	if err != nil {
+		logger.Error("Failed to get status time series", zap.Error(err))
+		return nil, err
+	}
+
+	latency, err := s.messageRepo.GetSendLatency(ctx, from, to)
+	if err != nil {
+		logger.Error("Failed to get send latency", zap.Error(err))
+		return nil, err
+	}
+
+	return &domain.MessageStats{
+		From:       from,
+		To:         to,
+		Interval:   interval,
+		Totals:     totals,
+		TimeSeries: fillTimeSeries(points, from, to, interval),
+		Latency:    *latency,
+	}, nil
+}
+
+// fillTimeSeries returns one point per bucket in [from, to), using zero counts
+// for buckets without messages so that clients can chart the result directly.
+func fillTimeSeries(points []domain.TimeSeriesPoint, from, to time.Time, interval statsintervals.StatsInterval) []domain.TimeSeriesPoint {
+	step := interval.Duration()
+	byBucket := make(map[time.Time]domain.StatusCounts, len(points))
+	for _, point := range points {
+		byBucket[point.Bucket.UTC()] = point.Counts
+	}
+
+	filled := make([]domain.TimeSeriesPoint, 0, int(to.Sub(from)/step)+1)
+	for bucket := from.Truncate(step); bucket.Before(to); bucket = bucket.Add(step) {
+		counts, ok := byBucket[bucket]
+		if !ok {
+			counts = domain.StatusCounts{}
+		}
+		filled = append(filled, domain.TimeSeriesPoint{Bucket: bucket, Counts: counts})
+	}
+
+	return filled
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/statsintervals"
+	pkgerrors "insider-message-system/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStatsService_GetStats(t *testing.T) {
+	repo := &mockMessageRepository{}
+	from := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Hour)
+
+	repo.On("CountByStatus", mock.Anything, from, to).Return(domain.StatusCounts{messagestatus.Sent: 3}, nil)
+	repo.On("GetStatusTimeSeries", mock.Anything, from, to, statsintervals.Hour).Return([]domain.TimeSeriesPoint{
+		{Bucket: from.Add(time.Hour), Counts: domain.StatusCounts{messagestatus.Sent: 3}},
+	}, nil)
+	repo.On("GetSendLatency", mock.Anything, from, to).Return(&domain.LatencyStats{Count: 3, P50Ms: 10}, nil)
+
+	stats, err := NewStats(repo).GetStats(context.Background(), from, to, statsintervals.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), stats.Totals[messagestatus.Sent])
+	assert.Equal(t, int64(3), stats.Latency.Count)
+	assert.Len(t, stats.TimeSeries, 3)
+	assert.Empty(t, stats.TimeSeries[0].Counts)
+	assert.Equal(t, int64(3), stats.TimeSeries[1].Counts[messagestatus.Sent])
+	assert.Empty(t, stats.TimeSeries[2].Counts)
+	repo.AssertExpectations(t)
+}
+
+func TestStatsService_GetStats_InvalidRange(t *testing.T) {
+	repo := &mockMessageRepository{}
+	service := NewStats(repo)
+	now := time.Now()
+
+	_, err := service.GetStats(context.Background(), now, now, statsintervals.Hour)
+	assert.ErrorIs(t, err, pkgerrors.ErrInvalidStatsRange)
+
+	_, err = service.GetStats(context.Background(), now.AddDate(-1, 0, 0), now, statsintervals.Hour)
+	assert.ErrorIs(t, err, pkgerrors.ErrStatsRangeTooLarge)
+
+	repo.AssertNotCalled(t, "CountByStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStatsService_GetStats_RepositoryError(t *testing.T) {
+	repo := &mockMessageRepository{}
+	from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	repo.On("CountByStatus", mock.Anything, from, to).Return(domain.StatusCounts(nil), errors.New("db error"))
+
+	_, err := NewStats(repo).GetStats(context.Background(), from, to, statsintervals.Hour)
+	assert.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package usecases
+
+import (
+	"context"
+	"insider-message-system/internal/application/services"
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/constants/enums/joboverlaps"
+	"insider-message-system/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// JobStatusResponse represents the status and run policy of a background job.
+// Durations use Go syntax, and a zero timeout means the job's attempts are not bounded.
+// Status is "standby" for a running leader-only job on an instance that is not the leader.
+type JobStatusResponse struct {
+	Name        string                 `json:"name" example:"retention"`
+	Status      string                 `json:"status" example:"running"`
+	LeaderOnly  bool                   `json:"leader_only" example:"true"`
+	Interval    string                 `json:"interval" example:"1h0m0s"`
+	Jitter      string                 `json:"jitter" example:"5m0s"`
+	Overlap     joboverlaps.JobOverlap `json:"overlap" example:"skip"`
+	Timeout     string                 `json:"timeout" example:"30m0s"`
+	MaxAttempts int                    `json:"max_attempts" example:"2"`
+	RetryDelay  string                 `json:"retry_delay" example:"1m0s"`
+	InFlight    int                    `json:"in_flight" example:"0"`
+	NextRunAt   *time.Time             `json:"next_run_at,omitempty" example:"2024-01-15T11:00:00Z"`
+	LastRun     *domain.JobRun         `json:"last_run,omitempty"`
+	Runs        int64                  `json:"runs" example:"12"`
+	Failures    int64                  `json:"failures" example:"1"`
+	Skipped     int64                  `json:"skipped" example:"0"`
+}
+
+// ControlJobsUseCase handles listing, starting, stopping and querying background jobs.
+type ControlJobsUseCase struct {
+	jobRunner services.JobRunner
+}
+
+// NewControlJobsUseCase creates a new ControlJobsUseCase with the given job runner.
+func NewControlJobsUseCase(jobRunner services.JobRunner) *ControlJobsUseCase {
+	return &ControlJobsUseCase{
+		jobRunner: jobRunner,
+	}
+}
+
+// List returns the status of every registered job, ordered by name.
+func (uc *ControlJobsUseCase) List(ctx context.Context) []JobStatusResponse {
+	statuses := uc.jobRunner.Statuses()
+
+	responses := make([]JobStatusResponse, len(statuses))
+	for i, status := range statuses {
+		responses[i] = newJobStatusResponse(status)
+	}
+	return responses
+}
+
+// GetStatus returns the status of a single job.
+func (uc *ControlJobsUseCase) GetStatus(ctx context.Context, name string) (*JobStatusResponse, error) {
+	status, err := uc.jobRunner.Status(name)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newJobStatusResponse(*status)
+	return &response, nil
+}
+
+// Start starts a job on this instance and returns its status.
+func (uc *ControlJobsUseCase) Start(ctx context.Context, name string) (*JobStatusResponse, error) {
+	if err := uc.jobRunner.StartJob(ctx, name); err != nil {
+		logger.Warn("Failed to start job in use case", zap.String("job", name), zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("Job started via use case", zap.String("job", name))
+	return uc.GetStatus(ctx, name)
+}
+
+// Stop stops a job on this instance, waiting for its run in progress, and returns its status.
+func (uc *ControlJobsUseCase) Stop(ctx context.Context, name string) (*JobStatusResponse, error) {
+	if err := uc.jobRunner.StopJob(name); err != nil {
+		logger.Warn("Failed to stop job in use case", zap.String("job", name), zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("Job stopped via use case", zap.String("job", name))
+	return uc.GetStatus(ctx, name)
+}
+
+func newJobStatusResponse(status domain.JobStatus) JobStatusResponse {
+	response := JobStatusResponse{
+		Name:        status.Name,
+		Status:      "stopped",
+		LeaderOnly:  status.LeaderOnly,
+		Interval:    status.Interval.String(),
+		Jitter:      status.Jitter.String(),
+		Overlap:     status.Overlap,
+		Timeout:     status.Timeout.String(),
+		MaxAttempts: status.MaxAttempts,
+		RetryDelay:  status.RetryDelay.String(),
+		InFlight:    status.InFlight,
+		NextRunAt:   status.NextRunAt,
+		LastRun:     status.LastRun,
+		Runs:        status.Runs,
+		Failures:    status.Failures,
+		Skipped:     status.Skipped,
+	}
+	switch {
+	case status.Running && status.Standby:
+		response.Status = "standby"
+	case status.Running:
+		response.Status = "running"
+	}
+	return response
+}
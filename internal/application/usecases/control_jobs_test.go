@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"insider-message-system/internal/application/services"
+	"insider-message-system/pkg/constants/enums/joboverlaps"
+	pkgerrors "insider-message-system/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestJobsUseCase(t *testing.T) *ControlJobsUseCase {
+	runner := services.NewJobRunner()
+	require.NoError(t, runner.Register(services.Job{
+		Name:        "retention",
+		Run:         func(ctx context.Context) error { return nil },
+		Interval:    time.Hour,
+		Jitter:      5 * time.Minute,
+		Overlap:     joboverlaps.Skip,
+		Timeout:     30 * time.Minute,
+		MaxAttempts: 2,
+		RetryDelay:  time.Minute,
+	}))
+	t.Cleanup(func() { runner.Stop() })
+	return NewControlJobsUseCase(runner)
+}
+
+func TestControlJobsUseCase_List(t *testing.T) {
+	uc := newTestJobsUseCase(t)
+
+	jobs := uc.List(context.Background())
+	require.Len(t, jobs, 1)
+	assert.Equal(t, JobStatusResponse{
+		Name:        "retention",
+		Status:      "stopped",
+		Interval:    "1h0m0s",
+		Jitter:      "5m0s",
+		Overlap:     joboverlaps.Skip,
+		Timeout:     "30m0s",
+		MaxAttempts: 2,
+		RetryDelay:  "1m0s",
+	}, jobs[0])
+}
+
+func TestControlJobsUseCase_StartStop(t *testing.T) {
+	uc := newTestJobsUseCase(t)
+	ctx := context.Background()
+
+	response, err := uc.Start(ctx, "retention")
+	require.NoError(t, err)
+	assert.Equal(t, "running", response.Status)
+	assert.NotNil(t, response.NextRunAt)
+
+	_, err = uc.Start(ctx, "retention")
+	assert.Equal(t, pkgerrors.ErrJobAlreadyRunning, err)
+
+	response, err = uc.Stop(ctx, "retention")
+	require.NoError(t, err)
+	assert.Equal(t, "stopped", response.Status)
+	assert.Nil(t, response.NextRunAt)
+
+	_, err = uc.Stop(ctx, "retention")
+	assert.Equal(t, pkgerrors.ErrJobNotRunning, err)
+}
+
+func TestControlJobsUseCase_UnknownJob(t *testing.T) {
+	uc := newTestJobsUseCase(t)
+
+	_, err := uc.GetStatus(context.Background(), "missing")
+	assert.Equal(t, pkgerrors.ErrJobNotFound, err)
+	_, err = uc.Start(context.Background(), "missing")
+	assert.Equal(t, pkgerrors.ErrJobNotFound, err)
+}
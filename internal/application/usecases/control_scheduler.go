@@ -2,17 +2,71 @@ package usecases
 
 import (
 	"context"
+	"fmt"
 	"insider-message-system/internal/application/services"
+	"insider-message-system/internal/domain"
 	"insider-message-system/pkg/errors"
 	"insider-message-system/pkg/logger"
+	"time"
 
 	"go.uber.org/zap"
 )
 
-// SchedulerStatusResponse represents the status of the scheduler.
+// SchedulerStatusResponse represents the status of the scheduler. The activity
+// fields are only filled in by GetStatus.
 type SchedulerStatusResponse struct {
-	Status  string `json:"status" example:"running"`
-	Message string `json:"message" example:"Scheduler is running"`
+	Status        string                        `json:"status" example:"running"`
+	Message       string                        `json:"message" example:"Scheduler is running"`
+	InstanceID    string                        `json:"instance_id,omitempty" example:"api-7f9c-1"`
+	LeaderID      string                        `json:"leader_id,omitempty" example:"api-7f9c-1"`
+	DesiredState  *domain.SchedulerDesiredState `json:"desired_state,omitempty"`
+	StartedAt     *time.Time                    `json:"started_at,omitempty" example:"2024-01-15T09:00:00Z"`
+	UptimeSeconds int64                         `json:"uptime_seconds,omitempty" example:"3600"`
+	NextRunAt     *time.Time                    `json:"next_run_at,omitempty" example:"2024-01-15T10:02:00Z"`
+	LastCycle     *domain.SchedulerCycle        `json:"last_cycle,omitempty"`
+	Cycles        []domain.SchedulerCycle       `json:"cycles,omitempty"`
+	Totals        *domain.SchedulerTotals       `json:"totals,omitempty"`
+	BatchSizing   *domain.BatchSizing           `json:"batch_sizing,omitempty"`
+}
+
+// ChangeSchedulerStateRequest records who starts or stops the scheduler and why.
+// Both fields are optional.
+type ChangeSchedulerStateRequest struct {
+	ChangedBy string `json:"changed_by" example:"ops@example.com"`
+	Reason    string `json:"reason" example:"provider maintenance"`
+}
+
+// SchedulerConfigResponse represents the scheduler settings that can be changed at runtime.
+type SchedulerConfigResponse struct {
+	Interval   string `json:"interval" example:"2m0s"`
+	BatchSize  int    `json:"batch_size" example:"2"`
+	MaxRetries int    `json:"max_retries" example:"3"`
+	RetryDelay string `json:"retry_delay" example:"5s"`
+}
+
+// UpdateSchedulerConfigRequest represents a partial update of the scheduler settings.
+// Omitted fields keep their current value. Durations use Go syntax, such as "30s" or "2m".
+type UpdateSchedulerConfigRequest struct {
+	Interval   *string `json:"interval,omitempty" example:"30s"`
+	BatchSize  *int    `json:"batch_size,omitempty" example:"10"`
+	MaxRetries *int    `json:"max_retries,omitempty" example:"3"`
+	RetryDelay *string `json:"retry_delay,omitempty" example:"5s"`
+}
+
+// RunSchedulerRequest represents a manual processing cycle. BatchSize overrides the
+// scheduler's batch size for this run only, and Async returns before the cycle finishes.
+type RunSchedulerRequest struct {
+	BatchSize int  `json:"batch_size,omitempty" binding:"omitempty,min=1,max=1000" example:"100"`
+	Async     bool `json:"async" example:"false"`
+}
+
+// RunSchedulerResponse describes a manual processing cycle. Result is only set for
+// synchronous runs.
+type RunSchedulerResponse struct {
+	Mode       string              `json:"mode" example:"sync"`
+	BatchSize  int                 `json:"batch_size" example:"100"`
+	Result     *domain.BatchResult `json:"result,omitempty"`
+	DurationMs int64               `json:"duration_ms,omitempty" example:"840"`
 }
 
 // ControlSchedulerUseCase handles starting, stopping, and querying the scheduler.
@@ -27,14 +81,16 @@ func NewControlSchedulerUseCase(schedulerService services.Scheduler) *ControlSch
 	}
 }
 
-// Start attempts to start the scheduler and returns its status.
-func (uc *ControlSchedulerUseCase) Start(ctx context.Context) (*SchedulerStatusResponse, error) {
-	if uc.schedulerService.IsRunning() {
-		logger.Warn("Attempted to start scheduler that is already running")
-		return nil, errors.ErrSchedulerAlreadyRunning
-	}
-
-	if err := uc.schedulerService.Start(ctx); err != nil {
+// Start sets the desired state to running, which starts the scheduler on this
+// instance and, when the state is persisted, on every other one, and returns its
+// status. It fails when the desired state already is running, whether or not this
+// instance has caught up with it.
+func (uc *ControlSchedulerUseCase) Start(ctx context.Context, request ChangeSchedulerStateRequest) (*SchedulerStatusResponse, error) {
+	if err := uc.schedulerService.SetDesiredState(ctx, newSchedulerDesiredState(true, request)); err != nil {
+		if err == errors.ErrSchedulerAlreadyRunning {
+			logger.Warn("Attempted to start scheduler that is already running")
+			return nil, err
+		}
 		logger.Error("Failed to start scheduler in use case", zap.Error(err))
 		return nil, err
 	}
@@ -46,14 +102,16 @@ func (uc *ControlSchedulerUseCase) Start(ctx context.Context) (*SchedulerStatusR
 	}, nil
 }
 
-// Stop attempts to stop the scheduler and returns its status.
-func (uc *ControlSchedulerUseCase) Stop(ctx context.Context) (*SchedulerStatusResponse, error) {
-	if !uc.schedulerService.IsRunning() {
-		logger.Warn("Attempted to stop scheduler that is not running")
-		return nil, errors.ErrSchedulerNotRunning
-	}
-
-	if err := uc.schedulerService.Stop(); err != nil {
+// Stop sets the desired state to stopped, which stops the scheduler on this
+// instance and, when the state is persisted, on every other one, and returns its
+// status. It fails when the desired state already is stopped, whether or not this
+// instance has caught up with it.
+func (uc *ControlSchedulerUseCase) Stop(ctx context.Context, request ChangeSchedulerStateRequest) (*SchedulerStatusResponse, error) {
+	if err := uc.schedulerService.SetDesiredState(ctx, newSchedulerDesiredState(false, request)); err != nil {
+		if err == errors.ErrSchedulerNotRunning {
+			logger.Warn("Attempted to stop scheduler that is not running")
+			return nil, err
+		}
 		logger.Error("Failed to stop scheduler in use case", zap.Error(err))
 		return nil, err
 	}
@@ -65,19 +123,134 @@ func (uc *ControlSchedulerUseCase) Stop(ctx context.Context) (*SchedulerStatusRe
 	}, nil
 }
 
-// GetStatus returns the current status of the scheduler.
+// GetStatus returns the current status of the scheduler together with its recent
+// cycles, newest first, the next scheduled tick, uptime and cumulative counters.
 func (uc *ControlSchedulerUseCase) GetStatus(ctx context.Context) *SchedulerStatusResponse {
-	isRunning := uc.schedulerService.IsRunning()
+	status := uc.schedulerService.Status()
+
+	response := &SchedulerStatusResponse{
+		Status:        "stopped",
+		Message:       "Scheduler is currently stopped",
+		InstanceID:    status.InstanceID,
+		LeaderID:      status.LeaderID,
+		DesiredState:  status.DesiredState,
+		StartedAt:     status.StartedAt,
+		UptimeSeconds: int64(status.Uptime.Seconds()),
+		NextRunAt:     status.NextRunAt,
+		Cycles:        status.Cycles,
+		Totals:        &status.Totals,
+		BatchSizing:   status.BatchSizing,
+	}
+
+	if status.Running {
+		response.Status = "running"
+		response.Message = "Scheduler is currently running"
+	}
+
+	if len(status.Cycles) > 0 {
+		response.LastCycle = &status.Cycles[0]
+	}
+
+	return response
+}
+
+// GetConfig returns the settings the scheduler is currently using.
+func (uc *ControlSchedulerUseCase) GetConfig(ctx context.Context) *SchedulerConfigResponse {
+	return newSchedulerConfigResponse(uc.schedulerService.Settings())
+}
+
+// UpdateConfig merges the request into the current settings and applies them.
+func (uc *ControlSchedulerUseCase) UpdateConfig(ctx context.Context, request UpdateSchedulerConfigRequest) (*SchedulerConfigResponse, error) {
+	settings := uc.schedulerService.Settings()
+
+	if request.Interval != nil {
+		interval, err := parseSchedulerDuration("interval", *request.Interval)
+		if err != nil {
+			return nil, err
+		}
+		settings.Interval = interval
+	}
+	if request.BatchSize != nil {
+		settings.BatchSize = *request.BatchSize
+	}
+	if request.MaxRetries != nil {
+		settings.MaxRetries = *request.MaxRetries
+	}
+	if request.RetryDelay != nil {
+		retryDelay, err := parseSchedulerDuration("retry_delay", *request.RetryDelay)
+		if err != nil {
+			return nil, err
+		}
+		settings.RetryDelay = retryDelay
+	}
+
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.schedulerService.UpdateSettings(ctx, settings); err != nil {
+		logger.Error("Failed to update scheduler settings in use case", zap.Error(err))
+		return nil, err
+	}
+
+	return newSchedulerConfigResponse(settings), nil
+}
+
+// RunOnce runs a single processing cycle, waiting for it unless the request is async.
+func (uc *ControlSchedulerUseCase) RunOnce(ctx context.Context, request RunSchedulerRequest) (*RunSchedulerResponse, error) {
+	batchSize := request.BatchSize
+	if batchSize == 0 {
+		batchSize = uc.schedulerService.Settings().BatchSize
+	}
+	if batchSize < domain.MinSchedulerBatchSize || batchSize > domain.MaxSchedulerBatchSize {
+		return nil, domain.NewInvalidSchedulerSettingsError(fmt.Sprintf("batch_size must be between %d and %d", domain.MinSchedulerBatchSize, domain.MaxSchedulerBatchSize))
+	}
 
-	if isRunning {
-		return &SchedulerStatusResponse{
-			Status:  "running",
-			Message: "Scheduler is currently running",
+	if request.Async {
+		if err := uc.schedulerService.RunOnceAsync(ctx, batchSize); err != nil {
+			return nil, err
 		}
+
+		logger.Info("Manual scheduler run started via use case", zap.Int("batch_size", batchSize))
+		return &RunSchedulerResponse{Mode: "async", BatchSize: batchSize}, nil
 	}
 
-	return &SchedulerStatusResponse{
-		Status:  "stopped",
-		Message: "Scheduler is currently stopped",
+	start := time.Now()
+	result, err := uc.schedulerService.RunOnce(ctx, batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RunSchedulerResponse{
+		Mode:       "sync",
+		BatchSize:  batchSize,
+		Result:     result,
+		DurationMs: time.Since(start).Milliseconds(),
+	}, nil
+}
+
+func newSchedulerDesiredState(running bool, request ChangeSchedulerStateRequest) domain.SchedulerDesiredState {
+	return domain.SchedulerDesiredState{
+		Running:   running,
+		ChangedBy: request.ChangedBy,
+		Reason:    request.Reason,
+		ChangedAt: time.Now(),
+	}
+}
+
+func parseSchedulerDuration(field, value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, domain.NewInvalidSchedulerSettingsError(fmt.Sprintf("%s must be a duration such as 30s or 2m", field))
+	}
+	return duration, nil
+}
+
+func newSchedulerConfigResponse(settings domain.SchedulerSettings) *SchedulerConfigResponse {
+	return &SchedulerConfigResponse{
+		Interval:   settings.Interval.String(),
+		BatchSize:  settings.BatchSize,
+		MaxRetries: settings.MaxRetries,
+		RetryDelay: settings.RetryDelay.String(),
 	}
 }
@@ -3,8 +3,14 @@ package usecases
 import (
 	"context"
 	"testing"
+	"time"
 
 	"insider-message-system/internal/application/services"
+	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/database/repos"
+	"insider-message-system/internal/infrastructure/election"
+	"insider-message-system/internal/infrastructure/notifier"
+	"insider-message-system/pkg/constants/enums/cycletriggers"
 	pkgerrors "insider-message-system/pkg/errors"
 
 	"github.com/stretchr/testify/assert"
@@ -14,6 +20,7 @@ import (
 type mockScheduler struct {
 	mock.Mock
 	running bool
+	status  *domain.SchedulerStatus
 }
 
 func (m *mockScheduler) Start(ctx context.Context) error {
@@ -37,24 +44,61 @@ func (m *mockScheduler) IsRunning() bool {
 }
 
 func (m *mockScheduler) SetMessageProcessor(_ services.MessageProcessor) {}
+func (m *mockScheduler) SetNotifier(_ notifier.Notifier)                 {}
+func (m *mockScheduler) SetElector(_ election.Elector)                   {}
+func (m *mockScheduler) SetBatchController(_ services.BatchController)   {}
+func (m *mockScheduler) SetSettingsRepository(_ repos.SchedulerSettings) {}
+func (m *mockScheduler) SetStateRepository(_ repos.SchedulerState)       {}
+func (m *mockScheduler) LoadSettings(_ context.Context) error            { return nil }
+func (m *mockScheduler) Reconcile(_ context.Context) error               { return nil }
+
+func (m *mockScheduler) SetDesiredState(ctx context.Context, state domain.SchedulerDesiredState) error {
+	args := m.Called(ctx, state.Running, state.ChangedBy, state.Reason)
+	if args.Error(0) == nil {
+		m.running = state.Running
+	}
+	return args.Error(0)
+}
+
+func (m *mockScheduler) Settings() domain.SchedulerSettings {
+	return m.Called().Get(0).(domain.SchedulerSettings)
+}
+
+func (m *mockScheduler) UpdateSettings(ctx context.Context, settings domain.SchedulerSettings) error {
+	return m.Called(ctx, settings).Error(0)
+}
+
+func (m *mockScheduler) RunOnce(ctx context.Context, batchSize int) (*domain.BatchResult, error) {
+	args := m.Called(ctx, batchSize)
+	result, _ := args.Get(0).(*domain.BatchResult)
+	return result, args.Error(1)
+}
+
+func (m *mockScheduler) RunOnceAsync(ctx context.Context, batchSize int) error {
+	return m.Called(ctx, batchSize).Error(0)
+}
+
+func (m *mockScheduler) Status() domain.SchedulerStatus {
+	if m.status != nil {
+		return *m.status
+	}
+	return domain.SchedulerStatus{Running: m.running}
+}
 
 func TestControlSchedulerUseCase_Start(t *testing.T) {
 	ctx := context.Background()
 	mockSch := new(mockScheduler)
 	uc := NewControlSchedulerUseCase(mockSch)
 
-	mockSch.running = false
-	mockSch.On("IsRunning").Return(false)
-	mockSch.On("Start", ctx).Return(nil)
-	resp, err := uc.Start(ctx)
+	mockSch.On("SetDesiredState", ctx, true, "ops", "maintenance over").Return(nil)
+	resp, err := uc.Start(ctx, ChangeSchedulerStateRequest{ChangedBy: "ops", Reason: "maintenance over"})
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
 	assert.Equal(t, "running", resp.Status)
-	mockSch.AssertCalled(t, "Start", ctx)
+	mockSch.AssertCalled(t, "SetDesiredState", ctx, true, "ops", "maintenance over")
 
-	mockSch.running = true
-	mockSch.On("IsRunning").Return(true)
-	resp, err = uc.Start(ctx)
+	mockSch.On("SetDesiredState", ctx, true, "", "").Return(pkgerrors.ErrSchedulerAlreadyRunning)
+	resp, err = uc.Start(ctx, ChangeSchedulerStateRequest{})
 	assert.ErrorIs(t, err, pkgerrors.ErrSchedulerAlreadyRunning)
 	assert.Nil(t, resp)
 }
@@ -64,18 +108,15 @@ func TestControlSchedulerUseCase_Stop(t *testing.T) {
 	mockSch := new(mockScheduler)
 	uc := NewControlSchedulerUseCase(mockSch)
 
-	mockSch.running = true
-	mockSch.On("IsRunning").Return(true)
-	mockSch.On("Stop").Return(nil)
-	resp, err := uc.Stop(ctx)
+	mockSch.On("SetDesiredState", ctx, false, "ops", "provider maintenance").Return(nil)
+	resp, err := uc.Stop(ctx, ChangeSchedulerStateRequest{ChangedBy: "ops", Reason: "provider maintenance"})
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
 	assert.Equal(t, "stopped", resp.Status)
-	mockSch.AssertCalled(t, "Stop")
+	mockSch.AssertCalled(t, "SetDesiredState", ctx, false, "ops", "provider maintenance")
 
-	mockSch.running = false
-	mockSch.On("IsRunning").Return(false)
-	resp, err = uc.Stop(ctx)
+	mockSch.On("SetDesiredState", ctx, false, "", "").Return(pkgerrors.ErrSchedulerNotRunning)
+	resp, err = uc.Stop(ctx, ChangeSchedulerStateRequest{})
 	assert.ErrorIs(t, err, pkgerrors.ErrSchedulerNotRunning)
 	assert.Nil(t, resp)
 }
@@ -85,10 +126,8 @@ func TestControlSchedulerUseCase_Start_ErrorFromService(t *testing.T) {
 	mockSch := new(mockScheduler)
 	uc := NewControlSchedulerUseCase(mockSch)
 
-	mockSch.running = false
-	mockSch.On("IsRunning").Return(false)
-	mockSch.On("Start", ctx).Return(assert.AnError)
-	resp, err := uc.Start(ctx)
+	mockSch.On("SetDesiredState", ctx, true, "", "").Return(assert.AnError)
+	resp, err := uc.Start(ctx, ChangeSchedulerStateRequest{})
 	assert.Error(t, err)
 	assert.Nil(t, resp)
 }
@@ -98,10 +137,8 @@ func TestControlSchedulerUseCase_Stop_ErrorFromService(t *testing.T) {
 	mockSch := new(mockScheduler)
 	uc := NewControlSchedulerUseCase(mockSch)
 
-	mockSch.running = true
-	mockSch.On("IsRunning").Return(true)
-	mockSch.On("Stop").Return(assert.AnError)
-	resp, err := uc.Stop(ctx)
+	mockSch.On("SetDesiredState", ctx, false, "", "").Return(assert.AnError)
+	resp, err := uc.Stop(ctx, ChangeSchedulerStateRequest{})
 	assert.Error(t, err)
 	assert.Nil(t, resp)
 }
@@ -118,3 +155,169 @@ func TestControlSchedulerUseCase_GetStatus(t *testing.T) {
 	resp = uc.GetStatus(context.Background())
 	assert.Equal(t, "stopped", resp.Status)
 }
+
+func TestControlSchedulerUseCase_GetStatus_Activity(t *testing.T) {
+	startedAt := time.Now().Add(-time.Hour)
+	nextRunAt := time.Now().Add(time.Minute)
+	cycles := []domain.SchedulerCycle{
+		{Trigger: cycletriggers.Manual, Attempts: 1, Fetched: 2, Sent: 2},
+		{Trigger: cycletriggers.Tick, Attempts: 2, Fetched: 1, Failed: 1, Errors: []string{"db down"}},
+	}
+	mockSch := &mockScheduler{status: &domain.SchedulerStatus{
+		Running:    true,
+		InstanceID: "api-1",
+		LeaderID:   "api-2",
+		StartedAt:  &startedAt,
+		NextRunAt:  &nextRunAt,
+		Uptime:     time.Hour,
+		Cycles:     cycles,
+		Totals:     domain.SchedulerTotals{Cycles: 2, Fetched: 3, Sent: 2, Failed: 1},
+	}}
+	uc := NewControlSchedulerUseCase(mockSch)
+
+	resp := uc.GetStatus(context.Background())
+	assert.Equal(t, "running", resp.Status)
+	assert.Equal(t, "api-1", resp.InstanceID)
+	assert.Equal(t, "api-2", resp.LeaderID)
+	assert.Equal(t, &startedAt, resp.StartedAt)
+	assert.Equal(t, &nextRunAt, resp.NextRunAt)
+	assert.Equal(t, int64(3600), resp.UptimeSeconds)
+	assert.Equal(t, &cycles[0], resp.LastCycle)
+	assert.Equal(t, cycles, resp.Cycles)
+	assert.Equal(t, int64(2), resp.Totals.Cycles)
+}
+
+func TestControlSchedulerUseCase_GetStatus_NoCycles(t *testing.T) {
+	uc := NewControlSchedulerUseCase(&mockScheduler{})
+
+	resp := uc.GetStatus(context.Background())
+	assert.Equal(t, "stopped", resp.Status)
+	assert.Nil(t, resp.LastCycle)
+	assert.Nil(t, resp.StartedAt)
+	assert.Nil(t, resp.NextRunAt)
+	assert.Equal(t, &domain.SchedulerTotals{}, resp.Totals)
+}
+
+func TestControlSchedulerUseCase_GetConfig(t *testing.T) {
+	mockSch := new(mockScheduler)
+	uc := NewControlSchedulerUseCase(mockSch)
+
+	mockSch.On("Settings").Return(domain.SchedulerSettings{Interval: 2 * time.Minute, BatchSize: 2, MaxRetries: 3, RetryDelay: 5 * time.Second})
+
+	resp := uc.GetConfig(context.Background())
+	assert.Equal(t, &SchedulerConfigResponse{Interval: "2m0s", BatchSize: 2, MaxRetries: 3, RetryDelay: "5s"}, resp)
+}
+
+func TestControlSchedulerUseCase_UpdateConfig(t *testing.T) {
+	ctx := context.Background()
+	mockSch := new(mockScheduler)
+	uc := NewControlSchedulerUseCase(mockSch)
+
+	current := domain.SchedulerSettings{Interval: 2 * time.Minute, BatchSize: 2, MaxRetries: 3, RetryDelay: 5 * time.Second}
+	mockSch.On("Settings").Return(current)
+
+	updated := current
+	updated.Interval = 30 * time.Second
+	updated.BatchSize = 10
+	mockSch.On("UpdateSettings", ctx, updated).Return(nil)
+
+	interval, batchSize := "30s", 10
+	resp, err := uc.UpdateConfig(ctx, UpdateSchedulerConfigRequest{Interval: &interval, BatchSize: &batchSize})
+	assert.NoError(t, err)
+	assert.Equal(t, &SchedulerConfigResponse{Interval: "30s", BatchSize: 10, MaxRetries: 3, RetryDelay: "5s"}, resp)
+	mockSch.AssertExpectations(t)
+}
+
+func TestControlSchedulerUseCase_UpdateConfig_InvalidDuration(t *testing.T) {
+	mockSch := new(mockScheduler)
+	uc := NewControlSchedulerUseCase(mockSch)
+
+	mockSch.On("Settings").Return(domain.SchedulerSettings{Interval: time.Minute, BatchSize: 2, MaxRetries: 3})
+
+	retryDelay := "soon"
+	resp, err := uc.UpdateConfig(context.Background(), UpdateSchedulerConfigRequest{RetryDelay: &retryDelay})
+	assert.Nil(t, resp)
+	if appErr, ok := err.(*pkgerrors.Error); assert.True(t, ok) {
+		assert.Equal(t, "INVALID_SCHEDULER_SETTINGS", appErr.Code)
+		assert.Contains(t, appErr.Details, "retry_delay")
+	}
+	mockSch.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything)
+}
+
+func TestControlSchedulerUseCase_UpdateConfig_ErrorFromService(t *testing.T) {
+	mockSch := new(mockScheduler)
+	uc := NewControlSchedulerUseCase(mockSch)
+
+	mockSch.On("Settings").Return(domain.SchedulerSettings{Interval: time.Minute, BatchSize: 2, MaxRetries: 3})
+	mockSch.On("UpdateSettings", mock.Anything, mock.Anything).Return(assert.AnError)
+
+	batchSize := 5
+	resp, err := uc.UpdateConfig(context.Background(), UpdateSchedulerConfigRequest{BatchSize: &batchSize})
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+}
+
+func TestControlSchedulerUseCase_UpdateConfig_OutOfBounds(t *testing.T) {
+	mockSch := new(mockScheduler)
+	uc := NewControlSchedulerUseCase(mockSch)
+
+	mockSch.On("Settings").Return(domain.SchedulerSettings{Interval: time.Minute, BatchSize: 2, MaxRetries: 3})
+
+	batchSize := 0
+	resp, err := uc.UpdateConfig(context.Background(), UpdateSchedulerConfigRequest{BatchSize: &batchSize})
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	mockSch.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything)
+}
+
+func TestControlSchedulerUseCase_RunOnce(t *testing.T) {
+	ctx := context.Background()
+	mockSch := new(mockScheduler)
+	uc := NewControlSchedulerUseCase(mockSch)
+
+	mockSch.On("Settings").Return(domain.SchedulerSettings{Interval: time.Minute, BatchSize: 2, MaxRetries: 3})
+	mockSch.On("RunOnce", ctx, 2).Return(&domain.BatchResult{Fetched: 2, Sent: 2}, nil)
+	mockSch.On("RunOnce", ctx, 50).Return(&domain.BatchResult{Fetched: 7, Sent: 6, Failed: 1}, nil)
+
+	resp, err := uc.RunOnce(ctx, RunSchedulerRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, "sync", resp.Mode)
+	assert.Equal(t, 2, resp.BatchSize)
+	assert.Equal(t, &domain.BatchResult{Fetched: 2, Sent: 2}, resp.Result)
+
+	resp, err = uc.RunOnce(ctx, RunSchedulerRequest{BatchSize: 50})
+	assert.NoError(t, err)
+	assert.Equal(t, 50, resp.BatchSize)
+	assert.Equal(t, 1, resp.Result.Failed)
+	mockSch.AssertExpectations(t)
+}
+
+func TestControlSchedulerUseCase_RunOnce_Async(t *testing.T) {
+	ctx := context.Background()
+	mockSch := new(mockScheduler)
+	uc := NewControlSchedulerUseCase(mockSch)
+
+	mockSch.On("RunOnceAsync", ctx, 10).Return(nil)
+
+	resp, err := uc.RunOnce(ctx, RunSchedulerRequest{BatchSize: 10, Async: true})
+	assert.NoError(t, err)
+	assert.Equal(t, &RunSchedulerResponse{Mode: "async", BatchSize: 10}, resp)
+	mockSch.AssertNotCalled(t, "RunOnce", mock.Anything, mock.Anything)
+}
+
+func TestControlSchedulerUseCase_RunOnce_Errors(t *testing.T) {
+	ctx := context.Background()
+	mockSch := new(mockScheduler)
+	uc := NewControlSchedulerUseCase(mockSch)
+
+	resp, err := uc.RunOnce(ctx, RunSchedulerRequest{BatchSize: 5000})
+	assert.Nil(t, resp)
+	if appErr, ok := err.(*pkgerrors.Error); assert.True(t, ok) {
+		assert.Equal(t, "INVALID_SCHEDULER_SETTINGS", appErr.Code)
+	}
+
+	mockSch.On("RunOnce", ctx, 5).Return(nil, pkgerrors.ErrSchedulerCycleRunning)
+	resp, err = uc.RunOnce(ctx, RunSchedulerRequest{BatchSize: 5})
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, pkgerrors.ErrSchedulerCycleRunning)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"insider-message-system/internal/domain"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -23,7 +24,15 @@ func (m *mockMessageService) CreateMessage(ctx context.Context, req domain.Messa
 	return nil, nil
 }
 
-func (m *mockMessageService) SendPendingMessages(ctx context.Context, limit int) error {
+func (m *mockMessageService) ClaimPendingMessages(ctx context.Context, limit int, lease time.Duration) ([]*domain.Message, error) {
+	return nil, nil
+}
+
+func (m *mockMessageService) ReleaseClaim(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *mockMessageService) SendMessage(ctx context.Context, message *domain.Message) error {
 	return nil
 }
 
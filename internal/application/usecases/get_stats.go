@@ -0,0 +1,66 @@
+package usecases
+
+import (
+	"context"
+	"insider-message-system/internal/application/services"
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/constants/enums/statsintervals"
+	"insider-message-system/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// GetStatsRequest represents the query parameters for retrieving delivery statistics.
+type GetStatsRequest struct {
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-01-14T00:00:00Z"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" example:"2024-01-15T00:00:00Z"`
+	Interval string    `form:"interval" binding:"omitempty,oneof=hour day" example:"hour"`
+}
+
+// GetStatsUseCase handles the business logic for retrieving delivery statistics.
+type GetStatsUseCase struct {
+	statsService services.Stats
+	now          func() time.Time
+}
+
+// NewGetStatsUseCase creates a new GetStatsUseCase with the given stats service.
+func NewGetStatsUseCase(statsService services.Stats) *GetStatsUseCase {
+	return &GetStatsUseCase{
+		statsService: statsService,
+		now:          time.Now,
+	}
+}
+
+// Execute returns statistics for the requested range. When omitted, the range
+// ends now and covers the last day for hourly buckets or the last week for daily ones.
+func (uc *GetStatsUseCase) Execute(ctx context.Context, request GetStatsRequest) (*domain.MessageStats, error) {
+	interval := statsintervals.FromString(request.Interval)
+
+	to := request.To
+	if to.IsZero() {
+		to = uc.now()
+	}
+
+	from := request.From
+	if from.IsZero() {
+		if interval == statsintervals.Day {
+			from = to.AddDate(0, 0, -7)
+		} else {
+			from = to.Add(-24 * time.Hour)
+		}
+	}
+
+	logger.Info("Getting message stats",
+		zap.Time("from", from),
+		zap.Time("to", to),
+		zap.String("interval", interval.String()))
+
+	stats, err := uc.statsService.GetStats(ctx, from, to, interval)
+	if err != nil {
+		logger.Error("Failed to get message stats in use case", zap.Error(err))
+		return nil, err
+	}
+
+	return stats, nil
+}
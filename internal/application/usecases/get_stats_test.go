@@ -0,0 +1,56 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/constants/enums/statsintervals"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockStatsService struct {
+	mock.Mock
+}
+
+func (m *mockStatsService) GetStats(ctx context.Context, from, to time.Time, interval statsintervals.StatsInterval) (*domain.MessageStats, error) {
+	args := m.Called(ctx, from, to, interval)
+	stats, _ := args.Get(0).(*domain.MessageStats)
+	return stats, args.Error(1)
+}
+
+func TestGetStatsUseCase_Execute_Defaults(t *testing.T) {
+	mockSvc := new(mockStatsService)
+	uc := NewGetStatsUseCase(mockSvc)
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+
+	expected := &domain.MessageStats{Interval: statsintervals.Hour}
+	mockSvc.On("GetStats", mock.Anything, now.Add(-24*time.Hour), now, statsintervals.Hour).Return(expected, nil)
+	mockSvc.On("GetStats", mock.Anything, now.AddDate(0, 0, -7), now, statsintervals.Day).Return(expected, nil)
+
+	stats, err := uc.Execute(context.Background(), GetStatsRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, stats)
+
+	_, err = uc.Execute(context.Background(), GetStatsRequest{Interval: "day"})
+	assert.NoError(t, err)
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGetStatsUseCase_Execute_ExplicitRangeAndError(t *testing.T) {
+	mockSvc := new(mockStatsService)
+	uc := NewGetStatsUseCase(mockSvc)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 2)
+
+	mockSvc.On("GetStats", mock.Anything, from, to, statsintervals.Day).Return(nil, assert.AnError)
+
+	stats, err := uc.Execute(context.Background(), GetStatsRequest{From: from, To: to, Interval: "day"})
+	assert.Error(t, err)
+	assert.Nil(t, stats)
+}
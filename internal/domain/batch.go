@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// BatchResult summarizes a single processing cycle over pending messages.
+// Messages that were fetched but not sent to completion because the cycle was
+// cancelled are counted as unsent and remain pending. Deferred messages were held
+// back by the send rate limit or the circuit breaker and also remain pending, to
+// be sent by a later cycle. SendLatency is the mean time taken to send the
+// messages that reached the provider, sent or failed.
+type BatchResult struct {
+	Fetched  int `json:"fetched" example:"10"`
+	Sent     int `json:"sent" example:"8"`
+	Failed   int `json:"failed" example:"1"`
+	Skipped  int `json:"skipped" example:"1"`
+	Deferred int `json:"deferred" example:"0"`
+	Unsent   int `json:"unsent" example:"0"`
+
+	SendLatency time.Duration `json:"-"`
+}
+
+// Attempted returns how many of the fetched messages were processed to an outcome.
+func (r *BatchResult) Attempted() int {
+	return r.Sent + r.Failed + r.Skipped + r.Deferred
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"insider-message-system/pkg/constants/enums/batchdecisions"
+	"time"
+)
+
+// BatchSizing is the latest decision of the adaptive batch size controller
+// together with the signals it was based on. Backlog is the number of pending
+// messages, or -1 when it could not be counted; LatencyMs and ErrorRate describe
+// the sends of the cycles since the previous decision.
+type BatchSizing struct {
+	BatchSize    int                          `json:"batch_size" example:"40"`
+	MinBatchSize int                          `json:"min_batch_size" example:"1"`
+	MaxBatchSize int                          `json:"max_batch_size" example:"200"`
+	Decision     batchdecisions.BatchDecision `json:"decision" example:"increase"`
+	Reason       string                       `json:"reason" example:"backlog of 340 exceeds batch size 20"`
+	Backlog      int64                        `json:"backlog" example:"340"`
+	LatencyMs    int64                        `json:"latency_ms" example:"180"`
+	ErrorRate    float64                      `json:"error_rate" example:"0"`
+	DecidedAt    time.Time                    `json:"decided_at" example:"2024-01-15T10:00:00Z"`
+}
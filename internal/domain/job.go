@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"insider-message-system/pkg/constants/enums/joboverlaps"
+	"time"
+)
+
+// JobRun records a single run of a background job, including every retry attempt
+// made within it. Error is the error of the last attempt when the run failed.
+type JobRun struct {
+	StartedAt  time.Time `json:"started_at" example:"2024-01-15T10:00:00Z"`
+	FinishedAt time.Time `json:"finished_at" example:"2024-01-15T10:00:01Z"`
+	DurationMs int64     `json:"duration_ms" example:"840"`
+	Attempts   int       `json:"attempts" example:"1"`
+	Error      string    `json:"error,omitempty" example:"context deadline exceeded"`
+}
+
+// JobStatus is a snapshot of a background job's policy and recent activity.
+// NextRunAt is only set while the job is running. Standby is set for a
+// leader-only job on an instance that is not the leader, where due runs are
+// dropped. InFlight counts the runs in progress, and Runs, Failures and Skipped
+// are cumulative since the process started; a run cut short by stopping the job
+// is not counted as a failure.
+type JobStatus struct {
+	Name        string                 `json:"name" example:"retention"`
+	Running     bool                   `json:"running" example:"true"`
+	Interval    time.Duration          `json:"-"`
+	Jitter      time.Duration          `json:"-"`
+	Overlap     joboverlaps.JobOverlap `json:"overlap" example:"skip"`
+	Timeout     time.Duration          `json:"-"`
+	MaxAttempts int                    `json:"max_attempts" example:"3"`
+	RetryDelay  time.Duration          `json:"-"`
+	LeaderOnly  bool                   `json:"leader_only" example:"true"`
+	Standby     bool                   `json:"standby" example:"false"`
+	InFlight    int                    `json:"in_flight" example:"0"`
+	NextRunAt   *time.Time             `json:"next_run_at,omitempty" example:"2024-01-15T11:00:00Z"`
+	LastRun     *JobRun                `json:"last_run,omitempty"`
+	Runs        int64                  `json:"runs" example:"12"`
+	Failures    int64                  `json:"failures" example:"1"`
+	Skipped     int64                  `json:"skipped" example:"0"`
+}
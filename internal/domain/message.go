@@ -2,22 +2,37 @@ package domain
 
 import (
 	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
-// Message represents a message entity in the system.
+// Message represents a message entity in the system. Provider names the provider
+// that delivered the message, or that last failed to. FailureCode and
+// FailureReason describe the latest failed send, which a pending message may
+// still be retried after. FailedAttempts counts the transient failures so far.
 type Message struct {
-	ID            uuid.UUID                   `json:"id" db:"id"`
-	To            string                      `json:"to" db:"to"`
-	Content       string                      `json:"content" db:"content"`
-	Status        messagestatus.MessageStatus `json:"status" db:"status"`
-	CreatedAt     time.Time                   `json:"created_at" db:"created_at"`
-	SentAt        *time.Time                  `json:"sent_at,omitempty" db:"sent_at"`
-	MessageID     *string                     `json:"message_id,omitempty" db:"message_id"`
-	FailureReason *string                     `json:"failure_reason,omitempty" db:"failure_reason"`
+	ID             uuid.UUID                       `json:"id" db:"id"`
+	To             string                          `json:"to" db:"to"`
+	Content        string                          `json:"content" db:"content"`
+	Status         messagestatus.MessageStatus     `json:"status" db:"status"`
+	CreatedAt      time.Time                       `json:"created_at" db:"created_at"`
+	SentAt         *time.Time                      `json:"sent_at,omitempty" db:"sent_at"`
+	FailedAt       *time.Time                      `json:"failed_at,omitempty" db:"failed_at"`
+	MessageID      *string                         `json:"message_id,omitempty" db:"message_id"`
+	Provider       *string                         `json:"provider,omitempty" db:"provider"`
+	FailureCode    *webhookfailures.WebhookFailure `json:"failure_code,omitempty" db:"failure_code"`
+	FailureReason  *string                         `json:"failure_reason,omitempty" db:"failure_reason"`
+	FailedAttempts int                             `json:"failed_attempts,omitempty" db:"failed_attempts" gorm:"not null;default:0"`
+	ClaimedUntil   *time.Time                      `json:"-" db:"claimed_until"`
+}
+
+// MessageFailure describes a failed send. Code is the webhook failure category.
+type MessageFailure struct {
+	Code   webhookfailures.WebhookFailure
+	Reason string
 }
 
 // MessageRequest contains the data required to create a new message.
@@ -30,6 +45,8 @@ type MessageRequest struct {
 type MessageResponse struct {
 	Message   string `json:"message" example:"Accepted"`
 	MessageID string `json:"messageId" example:"67f2f8a8-ea58-4ed0-a6f9-ff217df4d849"`
+	// Provider names the provider that accepted the message.
+	Provider string `json:"-"`
 }
 
 // WebhookRequest represents the payload sent to a webhook.
@@ -63,12 +80,27 @@ func (m *Message) MarkAsSent(messageID string) {
 	m.MessageID = &messageID
 }
 
-// MarkAsFailed marks the message as failed and sets the failure reason.
+// MarkAsFailed marks the message as failed and sets the failure time and reason.
 func (m *Message) MarkAsFailed(reason string) {
+	now := time.Now()
 	m.Status = messagestatus.Failed
+	m.FailedAt = &now
 	m.FailureReason = &reason
 }
 
+// StatusChangedAt returns when the message entered its current status: SentAt for
+// sent messages, FailedAt for failed ones and CreatedAt for pending messages or
+// when the status time is missing.
+func (m *Message) StatusChangedAt() time.Time {
+	switch {
+	case m.Status == messagestatus.Sent && m.SentAt != nil:
+		return *m.SentAt
+	case m.Status == messagestatus.Failed && m.FailedAt != nil:
+		return *m.FailedAt
+	}
+	return m.CreatedAt
+}
+
 // IsValidForSending checks if the message is valid for sending (pending status and content length).
 func (m *Message) IsValidForSending() bool {
 	return m.Status == messagestatus.Pending && len(m.Content) <= 160
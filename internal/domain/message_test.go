@@ -49,6 +49,7 @@ func TestMessage_MarkAsFailed(t *testing.T) {
 
 	assert.Equal(t, messagestatus.Failed, message.Status)
 	assert.Nil(t, message.SentAt)
+	assert.NotNil(t, message.FailedAt)
 	assert.Nil(t, message.MessageID)
 	assert.NotNil(t, message.FailureReason)
 	assert.Equal(t, reason, *message.FailureReason)
@@ -106,3 +107,21 @@ func TestMessage_IsValidForSending(t *testing.T) {
 		})
 	}
 }
+
+func TestMessage_StatusChangedAt(t *testing.T) {
+	createdAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	sentAt := createdAt.Add(time.Hour)
+
+	sent := &Message{Status: messagestatus.Sent, CreatedAt: createdAt, SentAt: &sentAt}
+	assert.Equal(t, sentAt, sent.StatusChangedAt())
+
+	sentWithoutTime := &Message{Status: messagestatus.Sent, CreatedAt: createdAt}
+	assert.Equal(t, createdAt, sentWithoutTime.StatusChangedAt())
+
+	failedAt := createdAt.Add(2 * time.Hour)
+	failed := &Message{Status: messagestatus.Failed, CreatedAt: createdAt, FailedAt: &failedAt}
+	assert.Equal(t, failedAt, failed.StatusChangedAt())
+
+	pending := &Message{Status: messagestatus.Pending, CreatedAt: createdAt, SentAt: &sentAt}
+	assert.Equal(t, createdAt, pending.StatusChangedAt())
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"encoding/json"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEventType identifies the kind of message lifecycle event.
+type OutboxEventType string
+
+const (
+	EventMessageCreated OutboxEventType = "message.created"
+	EventMessageSent    OutboxEventType = "message.sent"
+	EventMessageFailed  OutboxEventType = "message.failed"
+	EventMessagePending OutboxEventType = "message.pending"
+)
+
+// EventTypeForStatus returns the event type emitted when a message moves to the given status.
+func EventTypeForStatus(status messagestatus.MessageStatus) OutboxEventType {
+	return OutboxEventType("message." + status.String())
+}
+
+// OutboxEvent is a message lifecycle event written in the same transaction as the state change.
+// IDs are allocated in insert order but transactions may commit out of ID order, so a
+// lower ID can become visible after a higher one. Events for the same message are
+// written after the message row is locked by the update, so their IDs follow the
+// order of the changes and relaying in ID order preserves it per message.
+type OutboxEvent struct {
+	ID        int64           `json:"id" gorm:"primaryKey;autoIncrement"`
+	MessageID uuid.UUID       `json:"message_id" gorm:"index"`
+	EventType OutboxEventType `json:"type"`
+	Payload   json.RawMessage `json:"data" gorm:"type:text"`
+	CreatedAt time.Time       `json:"occurred_at"`
+}
+
+// TableName returns the database table name for the OutboxEvent model.
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// OutboxDelivery records that a sink has successfully published an event. Tracking
+// deliveries per event rather than a per-sink high-water mark means an event that
+// becomes visible after a higher ID has been relayed is still delivered.
+type OutboxDelivery struct {
+	Sink        string    `json:"sink" gorm:"primaryKey"`
+	EventID     int64     `json:"event_id" gorm:"primaryKey;autoIncrement:false"`
+	DeliveredAt time.Time `json:"delivered_at"`
+}
+
+// TableName returns the database table name for the OutboxDelivery model.
+func (OutboxDelivery) TableName() string {
+	return "outbox_deliveries"
+}
+
+// MessageEventPayload is the event data describing the message state after the change.
+type MessageEventPayload struct {
+	MessageID     uuid.UUID                       `json:"message_id"`
+	To            string                          `json:"to,omitempty"`
+	Status        messagestatus.MessageStatus     `json:"status"`
+	Provider      *string                         `json:"provider,omitempty"`
+	ProviderID    *string                         `json:"provider_message_id,omitempty"`
+	FailureCode   *webhookfailures.WebhookFailure `json:"failure_code,omitempty"`
+	FailureReason *string                         `json:"failure_reason,omitempty"`
+}
+
+// NewOutboxEvent builds an outbox event for the given message state.
+func NewOutboxEvent(eventType OutboxEventType, payload MessageEventPayload) (*OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxEvent{
+		MessageID: payload.MessageID,
+		EventType: eventType,
+		Payload:   data,
+		CreatedAt: time.Now(),
+	}, nil
+}
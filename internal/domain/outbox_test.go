@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"encoding/json"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventTypeForStatus(t *testing.T) {
+	assert.Equal(t, EventMessageSent, EventTypeForStatus(messagestatus.Sent))
+	assert.Equal(t, EventMessageFailed, EventTypeForStatus(messagestatus.Failed))
+	assert.Equal(t, EventMessagePending, EventTypeForStatus(messagestatus.Pending))
+}
+
+func TestNewOutboxEvent(t *testing.T) {
+	providerID := "provider-123"
+	payload := MessageEventPayload{
+		MessageID:  uuid.New(),
+		Status:     messagestatus.Sent,
+		ProviderID: &providerID,
+	}
+
+	event, err := NewOutboxEvent(EventMessageSent, payload)
+	require.NoError(t, err)
+
+	assert.Equal(t, payload.MessageID, event.MessageID)
+	assert.Equal(t, EventMessageSent, event.EventType)
+	assert.False(t, event.CreatedAt.IsZero())
+
+	var decoded MessageEventPayload
+	require.NoError(t, json.Unmarshal(event.Payload, &decoded))
+	assert.Equal(t, payload.MessageID, decoded.MessageID)
+	require.NotNil(t, decoded.ProviderID)
+	assert.Equal(t, providerID, *decoded.ProviderID)
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"insider-message-system/pkg/constants/enums/cycletriggers"
+	"time"
+)
+
+// SchedulerCycle records a single processing cycle, including every retry attempt
+// made by the scheduler within it.
+type SchedulerCycle struct {
+	Trigger    cycletriggers.CycleTrigger `json:"trigger" example:"tick"`
+	StartedAt  time.Time                  `json:"started_at" example:"2024-01-15T10:00:00Z"`
+	FinishedAt time.Time                  `json:"finished_at" example:"2024-01-15T10:00:01Z"`
+	DurationMs int64                      `json:"duration_ms" example:"840"`
+	BatchSize  int                        `json:"batch_size" example:"2"`
+	Attempts   int                        `json:"attempts" example:"1"`
+	Fetched    int                        `json:"fetched" example:"2"`
+	Sent       int                        `json:"sent" example:"2"`
+	Failed     int                        `json:"failed" example:"0"`
+	Skipped    int                        `json:"skipped" example:"0"`
+	Deferred   int                        `json:"deferred" example:"0"`
+	Unsent     int                        `json:"unsent" example:"0"`
+	Errors     []string                   `json:"errors,omitempty"`
+}
+
+// AddAttempt folds the outcome of one processing attempt into the cycle.
+func (c *SchedulerCycle) AddAttempt(result *BatchResult, err error) {
+	c.Attempts++
+	if result != nil {
+		c.Fetched += result.Fetched
+		c.Sent += result.Sent
+		c.Failed += result.Failed
+		c.Skipped += result.Skipped
+		c.Deferred += result.Deferred
+		c.Unsent += result.Unsent
+	}
+	if err != nil {
+		c.Errors = append(c.Errors, err.Error())
+	}
+}
+
+// Succeeded reports whether the cycle ended without a processing error. Messages
+// that failed to send individually do not make the cycle itself fail.
+func (c *SchedulerCycle) Succeeded() bool {
+	return len(c.Errors) < c.Attempts
+}
+
+// SchedulerTotals are cumulative counters over every cycle since the process started.
+type SchedulerTotals struct {
+	Cycles       int64 `json:"cycles" example:"42"`
+	FailedCycles int64 `json:"failed_cycles" example:"1"`
+	Fetched      int64 `json:"fetched" example:"80"`
+	Sent         int64 `json:"sent" example:"77"`
+	Failed       int64 `json:"failed" example:"2"`
+	Skipped      int64 `json:"skipped" example:"1"`
+	Deferred     int64 `json:"deferred" example:"0"`
+	Unsent       int64 `json:"unsent" example:"0"`
+}
+
+// Add accumulates a finished cycle into the totals.
+func (t *SchedulerTotals) Add(cycle SchedulerCycle) {
+	t.Cycles++
+	if !cycle.Succeeded() {
+		t.FailedCycles++
+	}
+	t.Fetched += int64(cycle.Fetched)
+	t.Sent += int64(cycle.Sent)
+	t.Failed += int64(cycle.Failed)
+	t.Skipped += int64(cycle.Skipped)
+	t.Deferred += int64(cycle.Deferred)
+	t.Unsent += int64(cycle.Unsent)
+}
+
+// SchedulerStatus is a snapshot of the scheduler's state and recent activity.
+// StartedAt is only set while the scheduler is running, and NextRunAt while this
+// instance runs periodic cycles. With leader election, LeaderID is the instance
+// that runs them. DesiredState is the running state every instance reconciles to,
+// once it is known. Cycles lists the most recent cycles, newest first.
+// BatchSizing is the latest adaptive batch size decision, when adaptive sizing
+// is enabled.
+type SchedulerStatus struct {
+	Running      bool                   `json:"running" example:"true"`
+	InstanceID   string                 `json:"instance_id" example:"api-7f9c-1"`
+	LeaderID     string                 `json:"leader_id,omitempty" example:"api-7f9c-1"`
+	DesiredState *SchedulerDesiredState `json:"desired_state,omitempty"`
+	StartedAt    *time.Time             `json:"started_at,omitempty" example:"2024-01-15T09:00:00Z"`
+	NextRunAt    *time.Time             `json:"next_run_at,omitempty" example:"2024-01-15T10:02:00Z"`
+	Uptime       time.Duration          `json:"-"`
+	Cycles       []SchedulerCycle       `json:"cycles"`
+	Totals       SchedulerTotals        `json:"totals"`
+	BatchSizing  *BatchSizing           `json:"batch_sizing,omitempty"`
+}
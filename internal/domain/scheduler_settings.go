@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"insider-message-system/pkg/errors"
+	"net/http"
+	"time"
+)
+
+// Bounds for scheduler settings that can be changed at runtime.
+const (
+	MinSchedulerInterval   = time.Second
+	MaxSchedulerInterval   = 24 * time.Hour
+	MinSchedulerBatchSize  = 1
+	MaxSchedulerBatchSize  = 1000
+	MinSchedulerMaxRetries = 1
+	MaxSchedulerMaxRetries = 10
+	MaxSchedulerRetryDelay = 10 * time.Minute
+)
+
+// SchedulerSettings are the scheduler parameters that can be changed while the
+// service is running. When persistence is enabled they are stored as a single row
+// so that runtime changes survive restarts.
+type SchedulerSettings struct {
+	ID         int           `gorm:"primaryKey"`
+	Interval   time.Duration `gorm:"column:interval_ns;not null"`
+	BatchSize  int           `gorm:"not null"`
+	MaxRetries int           `gorm:"not null"`
+	RetryDelay time.Duration `gorm:"column:retry_delay_ns;not null"`
+	UpdatedAt  time.Time     `gorm:"not null"`
+}
+
+func (SchedulerSettings) TableName() string {
+	return "scheduler_settings"
+}
+
+// Validate checks that every setting is within its allowed bounds.
+func (s SchedulerSettings) Validate() error {
+	switch {
+	case s.Interval < MinSchedulerInterval || s.Interval > MaxSchedulerInterval:
+		return NewInvalidSchedulerSettingsError(fmt.Sprintf("interval must be between %s and %s", MinSchedulerInterval, MaxSchedulerInterval))
+	case s.BatchSize < MinSchedulerBatchSize || s.BatchSize > MaxSchedulerBatchSize:
+		return NewInvalidSchedulerSettingsError(fmt.Sprintf("batch_size must be between %d and %d", MinSchedulerBatchSize, MaxSchedulerBatchSize))
+	case s.MaxRetries < MinSchedulerMaxRetries || s.MaxRetries > MaxSchedulerMaxRetries:
+		return NewInvalidSchedulerSettingsError(fmt.Sprintf("max_retries must be between %d and %d", MinSchedulerMaxRetries, MaxSchedulerMaxRetries))
+	case s.RetryDelay < 0 || s.RetryDelay > MaxSchedulerRetryDelay:
+		return NewInvalidSchedulerSettingsError(fmt.Sprintf("retry_delay must be between 0s and %s", MaxSchedulerRetryDelay))
+	}
+	return nil
+}
+
+// NewInvalidSchedulerSettingsError returns the error reported for out-of-bounds or
+// malformed scheduler settings, with details naming the offending field.
+func NewInvalidSchedulerSettingsError(details string) error {
+	return errors.NewErrorWithDetails("INVALID_SCHEDULER_SETTINGS", "Invalid scheduler settings", details, http.StatusBadRequest)
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"insider-message-system/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerSettings_Validate(t *testing.T) {
+	valid := SchedulerSettings{Interval: time.Minute, BatchSize: 10, MaxRetries: 3, RetryDelay: 5 * time.Second}
+	assert.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name   string
+		modify func(*SchedulerSettings)
+		field  string
+	}{
+		{"interval too short", func(s *SchedulerSettings) { s.Interval = 500 * time.Millisecond }, "interval"},
+		{"interval too long", func(s *SchedulerSettings) { s.Interval = 25 * time.Hour }, "interval"},
+		{"batch size zero", func(s *SchedulerSettings) { s.BatchSize = 0 }, "batch_size"},
+		{"batch size too large", func(s *SchedulerSettings) { s.BatchSize = MaxSchedulerBatchSize + 1 }, "batch_size"},
+		{"max retries zero", func(s *SchedulerSettings) { s.MaxRetries = 0 }, "max_retries"},
+		{"max retries too large", func(s *SchedulerSettings) { s.MaxRetries = 11 }, "max_retries"},
+		{"negative retry delay", func(s *SchedulerSettings) { s.RetryDelay = -time.Second }, "retry_delay"},
+		{"retry delay too long", func(s *SchedulerSettings) { s.RetryDelay = time.Hour }, "retry_delay"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := valid
+			tt.modify(&settings)
+
+			err := settings.Validate()
+			appErr, ok := err.(*errors.Error)
+			if assert.True(t, ok) {
+				assert.Equal(t, "INVALID_SCHEDULER_SETTINGS", appErr.Code)
+				assert.Equal(t, 400, appErr.Status)
+				assert.Contains(t, appErr.Details, tt.field)
+			}
+		})
+	}
+}
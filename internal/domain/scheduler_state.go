@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// SchedulerDesiredState is whether operators want the scheduler running, with who
+// changed it, when and why. When persistence is enabled it is stored as a single
+// row that every instance reconciles to.
+type SchedulerDesiredState struct {
+	ID        int       `gorm:"primaryKey" json:"-"`
+	Running   bool      `gorm:"not null" json:"running" example:"false"`
+	ChangedBy string    `gorm:"not null" json:"changed_by" example:"ops@example.com"`
+	Reason    string    `gorm:"not null" json:"reason,omitempty" example:"provider maintenance"`
+	ChangedAt time.Time `gorm:"not null" json:"changed_at" example:"2024-01-15T09:30:00Z"`
+}
+
+func (SchedulerDesiredState) TableName() string {
+	return "scheduler_state"
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/statsintervals"
+	"time"
+)
+
+// StatusCounts maps each message status to the number of messages in it.
+type StatusCounts map[messagestatus.MessageStatus]int64
+
+// TimeSeriesPoint holds the message counts for a single time bucket.
+type TimeSeriesPoint struct {
+	Bucket time.Time    `json:"bucket" example:"2024-01-15T10:00:00Z"`
+	Counts StatusCounts `json:"counts"`
+}
+
+// LatencyStats holds queue-to-send latency percentiles in milliseconds.
+type LatencyStats struct {
+	Count int64   `json:"count" example:"120"`
+	P50Ms float64 `json:"p50_ms" example:"61000"`
+	P95Ms float64 `json:"p95_ms" example:"118500"`
+	P99Ms float64 `json:"p99_ms" example:"120000"`
+}
+
+// MessageStats is the aggregated delivery report for a date range.
+type MessageStats struct {
+	From       time.Time                    `json:"from"`
+	To         time.Time                    `json:"to"`
+	Interval   statsintervals.StatsInterval `json:"interval" swaggertype:"string" example:"hour"`
+	Totals     StatusCounts                 `json:"totals"`
+	TimeSeries []TimeSeriesPoint            `json:"time_series"`
+	Latency    LatencyStats                 `json:"latency"`
+}
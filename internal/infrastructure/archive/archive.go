@@ -0,0 +1,129 @@
+package archive
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/logger"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// FileExtension is the suffix used for archive files.
+const FileExtension = ".ndjson.gz"
+
+// Archiver defines the interface for exporting messages before they are deleted.
+type Archiver interface {
+	Write(status messagestatus.MessageStatus, messages []*domain.Message) (string, error)
+}
+
+type fileArchiver struct {
+	dir string
+	now func() time.Time
+}
+
+// NewFileArchiver creates an Archiver that writes gzip-compressed NDJSON files into dir.
+func NewFileArchiver(dir string) Archiver {
+	return &fileArchiver{
+		dir: dir,
+		now: time.Now,
+	}
+}
+
+// Write stores the messages in a new archive file and returns its path.
+// The file is written under a temporary name and renamed once fully synced,
+// so a partially written archive is never mistaken for a complete one.
+func (a *fileArchiver) Write(status messagestatus.MessageStatus, messages []*domain.Message) (string, error) {
+	if err := os.MkdirAll(a.dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	name := fmt.Sprintf("messages-%s-%s%s", status, a.now().UTC().Format("20060102T150405.000000000Z"), FileExtension)
+	path := filepath.Join(a.dir, name)
+	tmpPath := path + ".tmp"
+
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("failed to create archive file: %w", err)
+	}
+
+	if err := writeMessages(file, messages); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return "", err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to sync archive file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to close archive file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to finalize archive file: %w", err)
+	}
+
+	logger.Info("Messages archived",
+		zap.String("path", path),
+		zap.String("status", string(status)),
+		zap.Int("count", len(messages)))
+
+	return path, nil
+}
+
+func writeMessages(file *os.File, messages []*domain.Message) error {
+	gz := gzip.NewWriter(file)
+	encoder := json.NewEncoder(gz)
+
+	for _, message := range messages {
+		if err := encoder.Encode(message); err != nil {
+			gz.Close()
+			return fmt.Errorf("failed to encode message %s: %w", message.ID, err)
+		}
+	}
+
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to compress archive: %w", err)
+	}
+
+	return nil
+}
+
+// ReadFile decodes all messages stored in an archive file.
+func ReadFile(path string) ([]*domain.Message, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive file: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive file: %w", err)
+	}
+	defer gz.Close()
+
+	var messages []*domain.Message
+	decoder := json.NewDecoder(bufio.NewReader(gz))
+	for decoder.More() {
+		var message domain.Message
+		if err := decoder.Decode(&message); err != nil {
+			return nil, fmt.Errorf("failed to decode archived message %d: %w", len(messages)+1, err)
+		}
+		messages = append(messages, &message)
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,82 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileArchiver_WriteAndRead(t *testing.T) {
+	dir := t.TempDir()
+	archiver := NewFileArchiver(dir)
+
+	sentAt := time.Now().UTC().Truncate(time.Millisecond)
+	externalID := "external-id"
+	messages := []*domain.Message{
+		{
+			ID:        uuid.New(),
+			To:        "+905551111111",
+			Content:   "first",
+			Status:    messagestatus.Sent,
+			CreatedAt: sentAt.Add(-time.Minute),
+			SentAt:    &sentAt,
+			MessageID: &externalID,
+		},
+		{
+			ID:        uuid.New(),
+			To:        "+905552222222",
+			Content:   "second",
+			Status:    messagestatus.Sent,
+			CreatedAt: sentAt.Add(-time.Minute),
+		},
+	}
+
+	path, err := archiver.Write(messagestatus.Sent, messages)
+	require.NoError(t, err)
+	assert.Equal(t, dir, filepath.Dir(path))
+	assert.True(t, strings.HasPrefix(filepath.Base(path), "messages-sent-"))
+	assert.True(t, strings.HasSuffix(path, FileExtension))
+
+	_, err = os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+
+	restored, err := ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, restored, 2)
+	assert.Equal(t, messages[0].ID, restored[0].ID)
+	assert.Equal(t, messages[0].Content, restored[0].Content)
+	assert.True(t, messages[0].SentAt.Equal(*restored[0].SentAt))
+	assert.Equal(t, externalID, *restored[0].MessageID)
+	assert.Equal(t, messages[1].ID, restored[1].ID)
+	assert.Nil(t, restored[1].SentAt)
+}
+
+func TestFileArchiver_CreatesDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "archive")
+	archiver := NewFileArchiver(dir)
+
+	path, err := archiver.Write(messagestatus.Failed, []*domain.Message{{ID: uuid.New(), Status: messagestatus.Failed}})
+	require.NoError(t, err)
+
+	_, err = os.Stat(path)
+	assert.NoError(t, err)
+}
+
+func TestReadFile_Errors(t *testing.T) {
+	_, err := ReadFile(filepath.Join(t.TempDir(), "missing.ndjson.gz"))
+	assert.Error(t, err)
+
+	plain := filepath.Join(t.TempDir(), "plain.ndjson.gz")
+	require.NoError(t, os.WriteFile(plain, []byte("{}\n"), 0o644))
+	_, err = ReadFile(plain)
+	assert.Error(t, err)
+}
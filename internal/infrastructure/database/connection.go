@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"insider-message-system/internal/domain"
 	"insider-message-system/pkg/config"
@@ -9,14 +10,19 @@ import (
 	"strings"
 	"time"
 
+	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	gormLogger "gorm.io/gorm/logger"
 )
 
 // DB wraps the GORM database connection for the application.
+// The embedded connection is the primary; read-only queries can be routed to
+// healthy read replicas through Reader.
 type DB struct {
 	*gorm.DB
+	replicas *replicaSet
+	metrics  *QueryMetrics
 }
 
 // RetryConfig configures retry logic for database operations.
@@ -69,7 +75,15 @@ func retryWithBackoff(operation func() error, config RetryConfig) error {
 
 // Add a NewConnectionWithDialector for testability
 func NewConnectionWithDialector(cfg config.DatabaseConfig, dialector gorm.Dialector) (*DB, error) {
+	return NewConnectionWithDialectors(cfg, dialector)
+}
+
+// NewConnectionWithDialectors connects to the primary and opens the given read replicas.
+// Replicas that cannot be reached are marked unhealthy instead of failing startup,
+// and are re-checked every cfg.ReplicaHealthCheckInterval.
+func NewConnectionWithDialectors(cfg config.DatabaseConfig, dialector gorm.Dialector, replicaDialectors ...gorm.Dialector) (*DB, error) {
 	retryConfig := DefaultRetryConfig()
+	metrics := NewQueryMetrics(cfg.SlowQueryThreshold)
 
 	var db *gorm.DB
 	var err error
@@ -97,7 +111,7 @@ func NewConnectionWithDialector(cfg config.DatabaseConfig, dialector gorm.Dialec
 			return fmt.Errorf("failed to ping database: %w", err)
 		}
 
-		return nil
+		return metrics.Register(db)
 	}, retryConfig)
 
 	if err != nil {
@@ -106,14 +120,73 @@ func NewConnectionWithDialector(cfg config.DatabaseConfig, dialector gorm.Dialec
 
 	logger.Info("Database connection established successfully")
 
-	return &DB{
-		DB: db,
-	}, nil
+	conn := &DB{
+		DB:      db,
+		metrics: metrics,
+	}
+
+	if len(replicaDialectors) > 0 {
+		interval := cfg.ReplicaHealthCheckInterval
+		if interval <= 0 {
+			interval = DefaultReplicaHealthCheckInterval
+		}
+
+		conn.replicas = openReplicas(cfg, replicaDialectors, metrics)
+		conn.replicas.monitor(interval)
+		logger.Info("Read replicas configured", zap.Int("replicas", len(conn.replicas.replicas)))
+	}
+
+	return conn, nil
 }
 
 // Update NewConnection to call NewConnectionWithDialector with Postgres by default
 func NewConnection(cfg config.DatabaseConfig) (*DB, error) {
-	return NewConnectionWithDialector(cfg, postgres.Open(cfg.DSN))
+	replicas := make([]gorm.Dialector, len(cfg.Replicas))
+	for i, dsn := range cfg.Replicas {
+		replicas[i] = postgres.Open(dsn)
+	}
+
+	return NewConnectionWithDialectors(cfg, postgres.Open(cfg.DSN), replicas...)
+}
+
+// Reader returns a session for read-only queries. It uses a healthy replica when
+// one is available and falls back to the primary otherwise, or when ctx was
+// created with WithPrimary.
+func (db *DB) Reader(ctx context.Context) *gorm.DB {
+	if !usePrimary(ctx) {
+		if r := db.replicas.pick(); r != nil {
+			return r.db.WithContext(ctx)
+		}
+	}
+
+	return db.WithContext(ctx)
+}
+
+// QueryStats returns the per-operation query metrics recorded since startup.
+func (db *DB) QueryStats() []OperationStats {
+	if db.metrics == nil {
+		return []OperationStats{}
+	}
+	return db.metrics.Snapshot()
+}
+
+// PoolStats returns the connection pool statistics of the primary and every replica.
+func (db *DB) PoolStats() map[string]PoolStats {
+	pools := make(map[string]PoolStats)
+
+	if stats, err := poolStats(db.DB); err == nil {
+		pools["primary"] = stats
+	}
+
+	if db.replicas != nil {
+		for _, r := range db.replicas.replicas {
+			if stats, err := poolStats(r.db); err == nil {
+				pools[r.name] = stats
+			}
+		}
+	}
+
+	return pools
 }
 
 // Migrate runs database migrations and creates necessary enums and indexes.
@@ -121,7 +194,7 @@ func (db *DB) Migrate() error {
 	retryConfig := DefaultRetryConfig()
 
 	return retryWithBackoff(func() error {
-		err := db.AutoMigrate(&domain.Message{})
+		err := db.AutoMigrate(&domain.Message{}, &domain.OutboxEvent{}, &domain.OutboxDelivery{}, &domain.SchedulerSettings{}, &domain.SchedulerDesiredState{})
 		if err != nil {
 			return fmt.Errorf("failed to run auto migration: %w", err)
 		}
@@ -158,6 +231,11 @@ func (db *DB) createIndexes() error {
 		"CREATE INDEX IF NOT EXISTS idx_messages_created_at ON messages(created_at)",
 		"CREATE INDEX IF NOT EXISTS idx_messages_sent_at ON messages(sent_at)",
 		"CREATE INDEX IF NOT EXISTS idx_messages_status_created ON messages(status, created_at) WHERE status = '" + messagestatus.Pending.String() + "'",
+		// Retention ages sent and failed messages from the time they entered their
+		// status, so these match the expressions of repos.statusChangedAtColumn.
+		"DROP INDEX IF EXISTS idx_messages_retention",
+		"CREATE INDEX IF NOT EXISTS idx_messages_retention_sent ON messages((COALESCE(sent_at, created_at)), id) WHERE status = '" + messagestatus.Sent.String() + "'",
+		"CREATE INDEX IF NOT EXISTS idx_messages_retention_failed ON messages((COALESCE(failed_at, created_at)), id) WHERE status = '" + messagestatus.Failed.String() + "'",
 	}
 
 	for _, idx := range indexes {
@@ -172,6 +250,10 @@ func (db *DB) createIndexes() error {
 // Close closes the underlying database connection.
 func (db *DB) Close() error {
 	logger.Info("Closing database connection")
+	if err := db.replicas.close(); err != nil {
+		logger.Warn("Failed to close read replicas", zap.Error(err))
+	}
+
 	sqlDB, err := db.DB.DB()
 	if err != nil {
 		return err
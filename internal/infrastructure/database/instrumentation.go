@@ -0,0 +1,244 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"insider-message-system/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const startTimeKey = "instrumentation:start_time"
+
+type operationKey struct{}
+
+// WithOperation labels the queries issued with ctx so their metrics are
+// recorded under the given repository operation name, e.g. "message.Create".
+func WithOperation(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationKey{}, name)
+}
+
+// OperationStats is a snapshot of the query metrics recorded for one operation.
+type OperationStats struct {
+	Operation    string  `json:"operation" example:"message.GetSentMessages"`
+	Count        int64   `json:"count" example:"120"`
+	Errors       int64   `json:"errors" example:"0"`
+	SlowQueries  int64   `json:"slow_queries" example:"2"`
+	RowsAffected int64   `json:"rows_affected" example:"2400"`
+	AvgMs        float64 `json:"avg_ms" example:"3.2"`
+	MaxMs        float64 `json:"max_ms" example:"250.4"`
+}
+
+type operationStats struct {
+	count        int64
+	errors       int64
+	slowQueries  int64
+	rowsAffected int64
+	total        time.Duration
+	max          time.Duration
+}
+
+// QueryMetrics records duration, rows affected and errors for every query,
+// grouped by repository operation, and logs queries slower than the threshold.
+type QueryMetrics struct {
+	slowThreshold time.Duration
+	mu            sync.Mutex
+	operations    map[string]*operationStats
+}
+
+// NewQueryMetrics creates a QueryMetrics. A zero slowThreshold disables slow-query logging.
+func NewQueryMetrics(slowThreshold time.Duration) *QueryMetrics {
+	return &QueryMetrics{
+		slowThreshold: slowThreshold,
+		operations:    make(map[string]*operationStats),
+	}
+}
+
+// Register installs the instrumentation callbacks on db.
+func (m *QueryMetrics) Register(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	registrations := []struct {
+		name   string
+		before func(string, func(*gorm.DB)) error
+		after  func(string, func(*gorm.DB)) error
+	}{
+		{"create", callbacks.Create().Before("gorm:create").Register, callbacks.Create().After("gorm:create").Register},
+		{"query", callbacks.Query().Before("gorm:query").Register, callbacks.Query().After("gorm:query").Register},
+		{"update", callbacks.Update().Before("gorm:update").Register, callbacks.Update().After("gorm:update").Register},
+		{"delete", callbacks.Delete().Before("gorm:delete").Register, callbacks.Delete().After("gorm:delete").Register},
+		{"row", callbacks.Row().Before("gorm:row").Register, callbacks.Row().After("gorm:row").Register},
+		{"raw", callbacks.Raw().Before("gorm:raw").Register, callbacks.Raw().After("gorm:raw").Register},
+	}
+
+	for _, r := range registrations {
+		if err := r.before("instrumentation:before_"+r.name, m.before); err != nil {
+			return fmt.Errorf("failed to register %s instrumentation: %w", r.name, err)
+		}
+		if err := r.after("instrumentation:after_"+r.name, m.after); err != nil {
+			return fmt.Errorf("failed to register %s instrumentation: %w", r.name, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *QueryMetrics) before(db *gorm.DB) {
+	db.InstanceSet(startTimeKey, time.Now())
+}
+
+func (m *QueryMetrics) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(startTimeKey)
+	if !ok {
+		return
+	}
+
+	start, ok := value.(time.Time)
+	if !ok {
+		return
+	}
+
+	operation := operationName(db)
+	m.record(operation, time.Since(start), db.Statement.RowsAffected, db.Error, db.Statement.SQL.String())
+}
+
+func (m *QueryMetrics) record(operation string, duration time.Duration, rows int64, err error, sql string) {
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+	slow := m.slowThreshold > 0 && duration >= m.slowThreshold
+
+	m.mu.Lock()
+	stats, ok := m.operations[operation]
+	if !ok {
+		stats = &operationStats{}
+		m.operations[operation] = stats
+	}
+	stats.count++
+	stats.total += duration
+	if duration > stats.max {
+		stats.max = duration
+	}
+	if rows > 0 {
+		stats.rowsAffected += rows
+	}
+	if failed {
+		stats.errors++
+	}
+	if slow {
+		stats.slowQueries++
+	}
+	m.mu.Unlock()
+
+	if slow {
+		// The SQL is logged with its placeholders only; bound values may contain
+		// message content or phone numbers and are never written to the log.
+		fields := []zap.Field{
+			zap.String("operation", operation),
+			zap.Duration("duration", duration),
+			zap.Duration("threshold", m.slowThreshold),
+			zap.Int64("rows_affected", rows),
+			zap.String("sql", sql),
+		}
+		if failed {
+			fields = append(fields, zap.Error(err))
+		}
+		logger.Warn("Slow database query", fields...)
+	}
+}
+
+// Snapshot returns the metrics recorded so far, sorted by operation name.
+func (m *QueryMetrics) Snapshot() []OperationStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make([]OperationStats, 0, len(m.operations))
+	for name, stats := range m.operations {
+		snapshot = append(snapshot, OperationStats{
+			Operation:    name,
+			Count:        stats.count,
+			Errors:       stats.errors,
+			SlowQueries:  stats.slowQueries,
+			RowsAffected: stats.rowsAffected,
+			AvgMs:        milliseconds(stats.total) / float64(stats.count),
+			MaxMs:        milliseconds(stats.max),
+		})
+	}
+
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Operation < snapshot[j].Operation })
+	return snapshot
+}
+
+// operationName returns the label set with WithOperation, or "<table>.<kind>"
+// for queries issued without one.
+func operationName(db *gorm.DB) string {
+	if ctx := db.Statement.Context; ctx != nil {
+		if name, ok := ctx.Value(operationKey{}).(string); ok && name != "" {
+			return name
+		}
+	}
+
+	table := db.Statement.Table
+	if table == "" {
+		table = "raw"
+	}
+
+	return table + "." + statementKind(db)
+}
+
+func statementKind(db *gorm.DB) string {
+	if len(db.Statement.BuildClauses) > 0 {
+		switch db.Statement.BuildClauses[0] {
+		case "INSERT":
+			return "insert"
+		case "UPDATE":
+			return "update"
+		case "DELETE":
+			return "delete"
+		case "SELECT":
+			return "select"
+		}
+	}
+	return "exec"
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// PoolStats is a JSON-friendly view of sql.DBStats for one connection pool.
+type PoolStats struct {
+	MaxOpenConnections int     `json:"max_open_connections" example:"25"`
+	OpenConnections    int     `json:"open_connections" example:"4"`
+	InUse              int     `json:"in_use" example:"1"`
+	Idle               int     `json:"idle" example:"3"`
+	WaitCount          int64   `json:"wait_count" example:"0"`
+	WaitDurationMs     float64 `json:"wait_duration_ms" example:"0"`
+	MaxIdleClosed      int64   `json:"max_idle_closed" example:"0"`
+	MaxIdleTimeClosed  int64   `json:"max_idle_time_closed" example:"0"`
+	MaxLifetimeClosed  int64   `json:"max_lifetime_closed" example:"2"`
+}
+
+func poolStats(db *gorm.DB) (PoolStats, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return PoolStats{}, err
+	}
+
+	stats := sqlDB.Stats()
+	return PoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     milliseconds(stats.WaitDuration),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}, nil
+}
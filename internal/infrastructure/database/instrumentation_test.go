@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/logger"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/driver/sqlite"
+)
+
+type instrumented struct {
+	ID    uint
+	Phone string
+}
+
+func newInstrumentedDB(t *testing.T, slowThreshold time.Duration) *DB {
+	cfg := config.DatabaseConfig{MaxOpenConnections: 1, MaxIdleConnections: 1, SlowQueryThreshold: slowThreshold}
+	db, err := NewConnectionWithDialector(cfg, sqlite.Open(":memory:"))
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&instrumented{}))
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func findOperation(stats []OperationStats, name string) *OperationStats {
+	for i := range stats {
+		if stats[i].Operation == name {
+			return &stats[i]
+		}
+	}
+	return nil
+}
+
+func TestQueryMetrics_RecordsPerOperation(t *testing.T) {
+	db := newInstrumentedDB(t, 0)
+	ctx := WithOperation(context.Background(), "instrumented.Create")
+
+	require.NoError(t, db.WithContext(ctx).Create(&instrumented{Phone: "+100"}).Error)
+	require.NoError(t, db.WithContext(ctx).Create(&instrumented{Phone: "+200"}).Error)
+
+	var rows []instrumented
+	require.NoError(t, db.WithContext(WithOperation(context.Background(), "instrumented.List")).Find(&rows).Error)
+
+	err := db.WithContext(WithOperation(context.Background(), "instrumented.Broken")).Exec("SELECT * FROM missing_table").Error
+	require.Error(t, err)
+
+	stats := db.QueryStats()
+
+	create := findOperation(stats, "instrumented.Create")
+	require.NotNil(t, create)
+	assert.Equal(t, int64(2), create.Count)
+	assert.Equal(t, int64(2), create.RowsAffected)
+	assert.Zero(t, create.Errors)
+
+	list := findOperation(stats, "instrumented.List")
+	require.NotNil(t, list)
+	assert.Equal(t, int64(1), list.Count)
+	assert.Equal(t, int64(2), list.RowsAffected)
+
+	broken := findOperation(stats, "instrumented.Broken")
+	require.NotNil(t, broken)
+	assert.Equal(t, int64(1), broken.Errors)
+}
+
+func TestQueryMetrics_UnlabelledQueriesUseTableAndKind(t *testing.T) {
+	db := newInstrumentedDB(t, 0)
+
+	require.NoError(t, db.Create(&instrumented{Phone: "+100"}).Error)
+
+	assert.NotNil(t, findOperation(db.QueryStats(), "instrumenteds.insert"))
+}
+
+func TestQueryMetrics_SlowQueryLogRedactsValues(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	previous := logger.Logger
+	logger.Logger = zap.New(core)
+	defer func() { logger.Logger = previous }()
+
+	db := newInstrumentedDB(t, time.Nanosecond)
+	ctx := WithOperation(context.Background(), "instrumented.Create")
+	require.NoError(t, db.WithContext(ctx).Create(&instrumented{Phone: "+905551111111"}).Error)
+
+	slow := logs.FilterMessage("Slow database query").FilterField(zap.String("operation", "instrumented.Create")).All()
+	require.Len(t, slow, 1)
+
+	sql := slow[0].ContextMap()["sql"].(string)
+	assert.Contains(t, sql, "INSERT INTO")
+	assert.NotContains(t, sql, "+905551111111")
+
+	create := findOperation(db.QueryStats(), "instrumented.Create")
+	require.NotNil(t, create)
+	assert.Equal(t, int64(1), create.SlowQueries)
+}
+
+func TestPoolStats(t *testing.T) {
+	db := newInstrumentedDB(t, 0)
+
+	pools := db.PoolStats()
+	require.Contains(t, pools, "primary")
+	assert.Equal(t, 1, pools["primary"].MaxOpenConnections)
+}
+
+func TestQueryStats_WithoutMetrics(t *testing.T) {
+	db := &DB{}
+	assert.Empty(t, db.QueryStats())
+}
@@ -0,0 +1,170 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/logger"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// DefaultReplicaHealthCheckInterval is used when no replica health check interval is configured.
+const DefaultReplicaHealthCheckInterval = 10 * time.Second
+
+type primaryKey struct{}
+
+// WithPrimary returns a context that makes Reader use the primary, for reads
+// that must observe a write made just before, such as GetByID after Create.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func usePrimary(ctx context.Context) bool {
+	forced, _ := ctx.Value(primaryKey{}).(bool)
+	return forced
+}
+
+type replica struct {
+	name    string
+	db      *gorm.DB
+	healthy atomic.Bool
+}
+
+// replicaSet holds the read replicas and tracks which of them are healthy.
+type replicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+	stop     chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+}
+
+func openReplicas(cfg config.DatabaseConfig, dialectors []gorm.Dialector, metrics *QueryMetrics) *replicaSet {
+	set := &replicaSet{stop: make(chan struct{})}
+
+	for i, dialector := range dialectors {
+		r := &replica{name: fmt.Sprintf("replica-%d", i)}
+
+		db, err := gorm.Open(dialector, &gorm.Config{
+			Logger:               gormLogger.Default.LogMode(gormLogger.Silent),
+			DisableAutomaticPing: true,
+		})
+		if err != nil {
+			logger.Warn("Failed to open read replica, reads will use the primary", zap.String("replica", r.name), zap.Error(err))
+			continue
+		}
+
+		if err := metrics.Register(db); err != nil {
+			logger.Warn("Failed to instrument read replica", zap.String("replica", r.name), zap.Error(err))
+		}
+
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.SetMaxOpenConns(cfg.MaxOpenConnections)
+			sqlDB.SetMaxIdleConns(cfg.MaxIdleConnections)
+			sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		}
+
+		r.db = db
+		set.replicas = append(set.replicas, r)
+	}
+
+	set.checkHealth()
+	return set
+}
+
+// pick returns the next healthy replica in round-robin order, or nil if none is healthy.
+func (s *replicaSet) pick() *replica {
+	if s == nil {
+		return nil
+	}
+
+	healthy := make([]*replica, 0, len(s.replicas))
+	for _, r := range s.replicas {
+		if r.healthy.Load() {
+			healthy = append(healthy, r)
+		}
+	}
+
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	return healthy[s.next.Add(1)%uint64(len(healthy))]
+}
+
+func (s *replicaSet) checkHealth() {
+	for _, r := range s.replicas {
+		healthy := ping(r.db) == nil
+		if previous := r.healthy.Swap(healthy); previous != healthy {
+			if healthy {
+				logger.Info("Read replica is healthy", zap.String("replica", r.name))
+			} else {
+				logger.Warn("Read replica is unhealthy, falling back to the primary", zap.String("replica", r.name))
+			}
+		}
+	}
+}
+
+func (s *replicaSet) monitor(interval time.Duration) {
+	if len(s.replicas) == 0 {
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.checkHealth()
+			}
+		}
+	}()
+}
+
+func (s *replicaSet) close() error {
+	if s == nil {
+		return nil
+	}
+
+	s.once.Do(func() { close(s.stop) })
+	s.wg.Wait()
+
+	var firstErr error
+	for _, r := range s.replicas {
+		sqlDB, err := r.db.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+func ping(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	return sqlDB.PingContext(ctx)
+}
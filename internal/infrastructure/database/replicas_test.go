@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"insider-message-system/pkg/config"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type marker struct {
+	ID     uint
+	Source string
+}
+
+func openMarked(t *testing.T, path, source string) gorm.Dialector {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&marker{}))
+	require.NoError(t, db.Create(&marker{Source: source}).Error)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+	return sqlite.Open(path)
+}
+
+func readSource(t *testing.T, db *gorm.DB) string {
+	var m marker
+	require.NoError(t, db.First(&m).Error)
+	return m.Source
+}
+
+func newReplicatedDB(t *testing.T) *DB {
+	dir := t.TempDir()
+	cfg := config.DatabaseConfig{MaxOpenConnections: 1, MaxIdleConnections: 1, ConnMaxLifetime: time.Minute, ReplicaHealthCheckInterval: time.Hour}
+
+	db, err := NewConnectionWithDialectors(cfg,
+		openMarked(t, filepath.Join(dir, "primary.db"), "primary"),
+		openMarked(t, filepath.Join(dir, "replica.db"), "replica"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestReader_WithoutReplicasUsesPrimary(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewConnectionWithDialector(config.DatabaseConfig{MaxOpenConnections: 1}, openMarked(t, filepath.Join(dir, "primary.db"), "primary"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	assert.Equal(t, "primary", readSource(t, db.Reader(context.Background())))
+}
+
+func TestReader_UsesHealthyReplica(t *testing.T) {
+	db := newReplicatedDB(t)
+
+	assert.Equal(t, "replica", readSource(t, db.Reader(context.Background())))
+	assert.Equal(t, "primary", readSource(t, db.WithContext(context.Background())))
+}
+
+func TestReader_WithPrimaryForcesPrimary(t *testing.T) {
+	db := newReplicatedDB(t)
+
+	assert.Equal(t, "primary", readSource(t, db.Reader(WithPrimary(context.Background()))))
+}
+
+func TestReader_FallsBackWhenReplicaUnhealthy(t *testing.T) {
+	db := newReplicatedDB(t)
+
+	sqlDB, err := db.replicas.replicas[0].db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+	db.replicas.checkHealth()
+
+	assert.Equal(t, "primary", readSource(t, db.Reader(context.Background())))
+}
+
+func TestReplicaSet_PickRoundRobinSkipsUnhealthy(t *testing.T) {
+	a, b, c := &replica{name: "a"}, &replica{name: "b"}, &replica{name: "c"}
+	a.healthy.Store(true)
+	c.healthy.Store(true)
+	set := &replicaSet{replicas: []*replica{a, b, c}}
+
+	seen := map[string]int{}
+	for i := 0; i < 6; i++ {
+		seen[set.pick().name]++
+	}
+
+	assert.Equal(t, 3, seen["a"])
+	assert.Equal(t, 3, seen["c"])
+	assert.Zero(t, seen["b"])
+
+	a.healthy.Store(false)
+	c.healthy.Store(false)
+	assert.Nil(t, set.pick())
+}
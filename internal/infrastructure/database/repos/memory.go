@@ -0,0 +1,385 @@
+package repos
+
+import (
+	"context"
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/statsintervals"
+	"insider-message-system/pkg/errors"
+	"insider-message-system/pkg/logger"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type memoryMessage struct {
+	mu       sync.RWMutex
+	messages map[uuid.UUID]*domain.Message
+	now      func() time.Time
+}
+
+// NewMemoryMessage creates a message repository that keeps messages in process memory.
+// It follows the same ordering, pagination and error semantics as the SQL repository
+// and is meant for demo mode and tests; nothing is persisted across restarts.
+func NewMemoryMessage() Message {
+	return &memoryMessage{
+		messages: make(map[uuid.UUID]*domain.Message),
+		now:      time.Now,
+	}
+}
+
+func (r *memoryMessage) Create(ctx context.Context, message *domain.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.messages[message.ID]; exists {
+		logger.Error("Failed to create message", zap.String("message_id", message.ID.String()))
+		return errors.NewError("DATABASE_ERROR", "Failed to create message", 500)
+	}
+
+	r.messages[message.ID] = copyMessage(message)
+
+	logger.Info("Message created successfully", zap.String("message_id", message.ID.String()))
+	return nil
+}
+
+func (r *memoryMessage) GetPendingMessages(ctx context.Context, limit int) ([]*domain.Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	messages := r.filter(func(m *domain.Message) bool { return m.Status == messagestatus.Pending })
+	sortByCreatedAt(messages)
+
+	return paginate(messages, 0, limit), nil
+}
+
+func (r *memoryMessage) ReleaseClaim(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.messages[id]; ok && stored.Status == messagestatus.Pending {
+		stored.ClaimedUntil = nil
+	}
+	return nil
+}
+
+func (r *memoryMessage) RetryLater(ctx context.Context, id uuid.UUID, failedAttempts int, until time.Time, failure domain.MessageFailure) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.messages[id]; ok && stored.Status == messagestatus.Pending {
+		claimedUntil := until
+		stored.ClaimedUntil = &claimedUntil
+		stored.FailedAttempts = failedAttempts
+		stored.FailureCode, stored.FailureReason = failureColumns(&failure)
+	}
+	return nil
+}
+
+func (r *memoryMessage) ClaimPendingMessages(ctx context.Context, limit int, lease time.Duration) ([]*domain.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	messages := r.filter(func(m *domain.Message) bool {
+		return m.Status == messagestatus.Pending && (m.ClaimedUntil == nil || m.ClaimedUntil.Before(now))
+	})
+	sortByCreatedAt(messages)
+	messages = paginate(messages, 0, limit)
+
+	claimedUntil := now.Add(lease)
+	for _, m := range messages {
+		stored := r.messages[m.ID]
+		stored.ClaimedUntil = &claimedUntil
+		m.ClaimedUntil = &claimedUntil
+	}
+
+	return messages, nil
+}
+
+func (r *memoryMessage) GetSentMessages(ctx context.Context, offset, limit int) ([]*domain.Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	messages := r.filter(func(m *domain.Message) bool { return m.Status == messagestatus.Sent })
+
+	// ORDER BY sent_at DESC; like Postgres, NULLs sort first in descending order.
+	sort.SliceStable(messages, func(i, j int) bool {
+		a, b := messages[i].SentAt, messages[j].SentAt
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		if !a.Equal(*b) {
+			return a.After(*b)
+		}
+		return messages[i].ID.String() < messages[j].ID.String()
+	})
+
+	return paginate(messages, offset, limit), nil
+}
+
+func (r *memoryMessage) UpdateStatus(ctx context.Context, id uuid.UUID, status messagestatus.MessageStatus, messageID, provider *string, failure *domain.MessageFailure) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.messages[id]
+	if !ok {
+		return errors.ErrMessageNotFound
+	}
+
+	stored.Status = status
+	stored.MessageID = copyString(messageID)
+	stored.Provider = copyString(provider)
+	stored.FailureCode, stored.FailureReason = failureColumns(failure)
+	stored.ClaimedUntil = nil
+	switch status {
+	case messagestatus.Sent:
+		sentAt := r.now()
+		stored.SentAt = &sentAt
+	case messagestatus.Failed:
+		failedAt := r.now()
+		stored.FailedAt = &failedAt
+	}
+
+	logger.Info("Message status updated successfully",
+		zap.String("message_id", id.String()),
+		zap.String("status", string(status)))
+	return nil
+}
+
+func (r *memoryMessage) GetByID(ctx context.Context, id uuid.UUID) (*domain.Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored, ok := r.messages[id]
+	if !ok {
+		return nil, errors.ErrMessageNotFound
+	}
+
+	return copyMessage(stored), nil
+}
+
+func (r *memoryMessage) GetTotalSentCount(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, m := range r.messages {
+		if m.Status == messagestatus.Sent {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *memoryMessage) CountPending(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, m := range r.messages {
+		if m.Status == messagestatus.Pending {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (r *memoryMessage) GetExpiredMessages(ctx context.Context, status messagestatus.MessageStatus, before time.Time, limit int) ([]*domain.Message, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	messages := r.filter(func(m *domain.Message) bool {
+		return m.Status == status && m.StatusChangedAt().Before(before)
+	})
+	sortByStatusChangedAt(messages)
+
+	return paginate(messages, 0, limit), nil
+}
+
+func (r *memoryMessage) DeleteByIDs(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for _, id := range ids {
+		if _, ok := r.messages[id]; ok {
+			delete(r.messages, id)
+			deleted++
+		}
+	}
+
+	if deleted > 0 {
+		logger.Info("Messages deleted successfully", zap.Int64("count", deleted))
+	}
+	return deleted, nil
+}
+
+func (r *memoryMessage) Restore(ctx context.Context, messages []*domain.Message) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var restored int64
+	for _, m := range messages {
+		if _, exists := r.messages[m.ID]; exists {
+			continue
+		}
+		r.messages[m.ID] = copyMessage(m)
+		restored++
+	}
+
+	if len(messages) > 0 {
+		logger.Info("Messages restored successfully",
+			zap.Int64("restored", restored),
+			zap.Int("skipped", len(messages)-int(restored)))
+	}
+	return restored, nil
+}
+
+func (r *memoryMessage) CountByStatus(ctx context.Context, from, to time.Time) (domain.StatusCounts, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(domain.StatusCounts)
+	for _, m := range r.messages {
+		if inRange(m.StatusChangedAt(), from, to) {
+			counts[m.Status]++
+		}
+	}
+
+	return counts, nil
+}
+
+func (r *memoryMessage) GetStatusTimeSeries(ctx context.Context, from, to time.Time, interval statsintervals.StatsInterval) ([]domain.TimeSeriesPoint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	byBucket := make(map[time.Time]domain.StatusCounts)
+	for _, m := range r.messages {
+		changedAt := m.StatusChangedAt()
+		if !inRange(changedAt, from, to) {
+			continue
+		}
+
+		bucket := changedAt.UTC().Truncate(interval.Duration())
+		if byBucket[bucket] == nil {
+			byBucket[bucket] = make(domain.StatusCounts)
+		}
+		byBucket[bucket][m.Status]++
+	}
+
+	points := make([]domain.TimeSeriesPoint, 0, len(byBucket))
+	for bucket, counts := range byBucket {
+		points = append(points, domain.TimeSeriesPoint{Bucket: bucket, Counts: counts})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Bucket.Before(points[j].Bucket) })
+
+	return points, nil
+}
+
+func (r *memoryMessage) GetSendLatency(ctx context.Context, from, to time.Time) (*domain.LatencyStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latencies []float64
+	for _, m := range r.messages {
+		if m.Status == messagestatus.Sent && m.SentAt != nil && inRange(*m.SentAt, from, to) {
+			latencies = append(latencies, float64(m.SentAt.Sub(m.CreatedAt))/float64(time.Millisecond))
+		}
+	}
+
+	return LatencyStatsFromSamples(latencies), nil
+}
+
+// filter returns copies of the stored messages matching keep, so callers can
+// never mutate repository state through the returned pointers.
+func (r *memoryMessage) filter(keep func(*domain.Message) bool) []*domain.Message {
+	messages := make([]*domain.Message, 0)
+	for _, m := range r.messages {
+		if keep(m) {
+			messages = append(messages, copyMessage(m))
+		}
+	}
+	return messages
+}
+
+// sortByCreatedAt orders messages by created_at ascending, breaking ties by ID
+// so that results are deterministic.
+func sortByCreatedAt(messages []*domain.Message) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		if !messages[i].CreatedAt.Equal(messages[j].CreatedAt) {
+			return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+		}
+		return messages[i].ID.String() < messages[j].ID.String()
+	})
+}
+
+// sortByStatusChangedAt orders messages by the time they entered their status,
+// ascending, breaking ties by ID.
+func sortByStatusChangedAt(messages []*domain.Message) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		a, b := messages[i].StatusChangedAt(), messages[j].StatusChangedAt()
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return messages[i].ID.String() < messages[j].ID.String()
+	})
+}
+
+// paginate applies GORM's OFFSET and LIMIT semantics: a limit of zero returns no
+// rows and a negative limit omits the LIMIT clause altogether.
+func paginate(messages []*domain.Message, offset, limit int) []*domain.Message {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(messages) {
+		return []*domain.Message{}
+	}
+
+	messages = messages[offset:]
+	if limit >= 0 && limit < len(messages) {
+		messages = messages[:limit]
+	}
+	return messages
+}
+
+func inRange(t, from, to time.Time) bool {
+	return !t.Before(from) && t.Before(to)
+}
+
+func copyMessage(m *domain.Message) *domain.Message {
+	c := *m
+	if m.SentAt != nil {
+		sentAt := *m.SentAt
+		c.SentAt = &sentAt
+	}
+	if m.FailedAt != nil {
+		failedAt := *m.FailedAt
+		c.FailedAt = &failedAt
+	}
+	if m.ClaimedUntil != nil {
+		claimedUntil := *m.ClaimedUntil
+		c.ClaimedUntil = &claimedUntil
+	}
+	if m.FailureCode != nil {
+		failureCode := *m.FailureCode
+		c.FailureCode = &failureCode
+	}
+	c.MessageID = copyString(m.MessageID)
+	c.Provider = copyString(m.Provider)
+	c.FailureReason = copyString(m.FailureReason)
+	return &c
+}
+
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
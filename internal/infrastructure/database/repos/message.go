@@ -5,26 +5,41 @@ import (
 	"insider-message-system/internal/domain"
 	"insider-message-system/internal/infrastructure/database"
 	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/statsintervals"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
 	"insider-message-system/pkg/errors"
 	"insider-message-system/pkg/logger"
+	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Message defines the interface for message repository operations.
 type Message interface {
 	Create(ctx context.Context, message *domain.Message) error
 	GetPendingMessages(ctx context.Context, limit int) ([]*domain.Message, error)
+	ClaimPendingMessages(ctx context.Context, limit int, lease time.Duration) ([]*domain.Message, error)
+	ReleaseClaim(ctx context.Context, id uuid.UUID) error
+	RetryLater(ctx context.Context, id uuid.UUID, failedAttempts int, until time.Time, failure domain.MessageFailure) error
 	GetSentMessages(ctx context.Context, offset, limit int) ([]*domain.Message, error)
-	UpdateStatus(ctx context.Context, id uuid.UUID, status messagestatus.MessageStatus, messageID *string, failureReason *string) error
+	UpdateStatus(ctx context.Context, id uuid.UUID, status messagestatus.MessageStatus, messageID, provider *string, failure *domain.MessageFailure) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Message, error)
 	GetTotalSentCount(ctx context.Context) (int64, error)
+	CountPending(ctx context.Context) (int64, error)
+	GetExpiredMessages(ctx context.Context, status messagestatus.MessageStatus, before time.Time, limit int) ([]*domain.Message, error)
+	DeleteByIDs(ctx context.Context, ids []uuid.UUID) (int64, error)
+	Restore(ctx context.Context, messages []*domain.Message) (int64, error)
+	CountByStatus(ctx context.Context, from, to time.Time) (domain.StatusCounts, error)
+	GetStatusTimeSeries(ctx context.Context, from, to time.Time, interval statsintervals.StatsInterval) ([]domain.TimeSeriesPoint, error)
+	GetSendLatency(ctx context.Context, from, to time.Time) (*domain.LatencyStats, error)
 }
 
 type message struct {
-	db *database.DB
+	db     *database.DB
+	outbox bool
 }
 
 // NewMessage creates a new message repository with the given database connection.
@@ -32,11 +47,30 @@ func NewMessage(db *database.DB) Message {
 	return &message{db: db}
 }
 
+// NewMessageWithOutbox creates a message repository that also records a lifecycle
+// event in the outbox table, in the same transaction, for every create and status change.
+func NewMessageWithOutbox(db *database.DB) Message {
+	return &message{db: db, outbox: true}
+}
+
 func (r *message) Create(ctx context.Context, message *domain.Message) error {
-	result := r.db.WithContext(ctx).Create(message)
-	if result.Error != nil {
-		logger.Error("Failed to create message", zap.Error(result.Error), zap.String("message_id", message.ID.String()))
-		return errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to create message", 500)
+	ctx = database.WithOperation(ctx, "message.Create")
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+
+		return r.writeEvent(tx, domain.EventMessageCreated, domain.MessageEventPayload{
+			MessageID: message.ID,
+			To:        message.To,
+			Status:    message.Status,
+		})
+	})
+
+	if err != nil {
+		logger.Error("Failed to create message", zap.Error(err), zap.String("message_id", message.ID.String()))
+		return errors.WrapError(err, "DATABASE_ERROR", "Failed to create message", 500)
 	}
 
 	logger.Info("Message created successfully", zap.String("message_id", message.ID.String()))
@@ -44,6 +78,8 @@ func (r *message) Create(ctx context.Context, message *domain.Message) error {
 }
 
 func (r *message) GetPendingMessages(ctx context.Context, limit int) ([]*domain.Message, error) {
+	ctx = database.WithOperation(ctx, "message.GetPendingMessages")
+
 	var messages []*domain.Message
 
 	result := r.db.WithContext(ctx).
@@ -60,12 +96,106 @@ func (r *message) GetPendingMessages(ctx context.Context, limit int) ([]*domain.
 	return messages, nil
 }
 
+// ClaimPendingMessages returns up to limit pending messages, oldest first, that no
+// one else holds a claim on, and claims them until lease from now. A claim is
+// released when the status changes and expires on its own if the holder dies, so
+// another instance or cycle can pick the message up again. On Postgres, candidate
+// rows are locked with FOR UPDATE SKIP LOCKED so concurrent claimers never wait
+// for or receive the same rows.
+func (r *message) ClaimPendingMessages(ctx context.Context, limit int, lease time.Duration) ([]*domain.Message, error) {
+	ctx = database.WithOperation(ctx, "message.ClaimPendingMessages")
+
+	var messages []*domain.Message
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		query := tx.Where("status = ? AND (claimed_until IS NULL OR claimed_until < ?)", messagestatus.Pending, now).
+			Order("created_at ASC").
+			Limit(limit)
+		if r.isPostgres() {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		if err := query.Find(&messages).Error; err != nil {
+			return err
+		}
+
+		if len(messages) == 0 {
+			return nil
+		}
+
+		claimedUntil := now.Add(lease)
+		ids := make([]uuid.UUID, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+			message.ClaimedUntil = &claimedUntil
+		}
+
+		return tx.Model(&domain.Message{}).
+			Where("id IN ?", ids).
+			Update("claimed_until", claimedUntil).Error
+	})
+
+	if err != nil {
+		logger.Error("Failed to claim pending messages", zap.Error(err))
+		return nil, errors.WrapError(err, "DATABASE_ERROR", "Failed to claim pending messages", 500)
+	}
+
+	return messages, nil
+}
+
+// ReleaseClaim gives up the claim on a pending message that was not sent, so the
+// next claim can pick it up without waiting for the lease to expire. It is a
+// no-op for messages that are no longer pending.
+func (r *message) ReleaseClaim(ctx context.Context, id uuid.UUID) error {
+	ctx = database.WithOperation(ctx, "message.ReleaseClaim")
+
+	err := r.db.WithContext(ctx).Model(&domain.Message{}).
+		Where("id = ? AND status = ?", id, messagestatus.Pending).
+		Update("claimed_until", nil).Error
+
+	if err != nil {
+		logger.Error("Failed to release message claim", zap.Error(err), zap.String("id", id.String()))
+		return errors.WrapError(err, "DATABASE_ERROR", "Failed to release message claim", 500)
+	}
+
+	return nil
+}
+
+// RetryLater keeps a pending message pending after a failed send, recording the
+// failure and holding its claim until the given time so that no cycle picks it
+// up earlier. It records no outbox event, since the status does not change.
+func (r *message) RetryLater(ctx context.Context, id uuid.UUID, failedAttempts int, until time.Time, failure domain.MessageFailure) error {
+	ctx = database.WithOperation(ctx, "message.RetryLater")
+
+	failureCode, failureReason := failureColumns(&failure)
+	err := r.db.WithContext(ctx).Model(&domain.Message{}).
+		Where("id = ? AND status = ?", id, messagestatus.Pending).
+		Updates(map[string]any{
+			"claimed_until":   until,
+			"failed_attempts": failedAttempts,
+			"failure_code":    failureCode,
+			"failure_reason":  failureReason,
+		}).Error
+
+	if err != nil {
+		logger.Error("Failed to schedule message retry", zap.Error(err), zap.String("id", id.String()))
+		return errors.WrapError(err, "DATABASE_ERROR", "Failed to schedule message retry", 500)
+	}
+
+	return nil
+}
+
 func (r *message) GetSentMessages(ctx context.Context, offset, limit int) ([]*domain.Message, error) {
+	ctx = database.WithOperation(ctx, "message.GetSentMessages")
+
 	var messages []*domain.Message
 
-	result := r.db.WithContext(ctx).
+	result := r.db.Reader(ctx).
 		Where("status = ?", messagestatus.Sent).
-		Order("sent_at DESC").
+		// NULLS FIRST is Postgres' default for DESC, spelled out so every backend agrees.
+		Order("sent_at DESC NULLS FIRST, id ASC").
 		Offset(offset).
 		Limit(limit).
 		Find(&messages)
@@ -78,29 +208,56 @@ func (r *message) GetSentMessages(ctx context.Context, offset, limit int) ([]*do
 	return messages, nil
 }
 
-func (r *message) UpdateStatus(ctx context.Context, id uuid.UUID, status messagestatus.MessageStatus, messageID *string, failureReason *string) error {
+func (r *message) UpdateStatus(ctx context.Context, id uuid.UUID, status messagestatus.MessageStatus, messageID, provider *string, failure *domain.MessageFailure) error {
+	ctx = database.WithOperation(ctx, "message.UpdateStatus")
+
+	failureCode, failureReason := failureColumns(failure)
 	updates := map[string]any{
 		"status":         status,
 		"message_id":     messageID,
+		"provider":       provider,
+		"failure_code":   failureCode,
 		"failure_reason": failureReason,
+		"claimed_until":  nil,
 	}
 
-	if status == messagestatus.Sent {
+	switch status {
+	case messagestatus.Sent:
 		updates["sent_at"] = gorm.Expr("NOW()")
+	case messagestatus.Failed:
+		updates["failed_at"] = gorm.Expr("NOW()")
 	}
 
-	result := r.db.WithContext(ctx).
-		Model(&domain.Message{}).
-		Where("id = ?", id).
-		Updates(updates)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Message{}).
+			Where("id = ?", id).
+			Updates(updates)
 
-	if result.Error != nil {
-		logger.Error("Failed to update message status", zap.Error(result.Error), zap.String("message_id", id.String()))
-		return errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to update message status", 500)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.ErrMessageNotFound
+		}
+
+		return r.writeEvent(tx, domain.EventTypeForStatus(status), domain.MessageEventPayload{
+			MessageID:     id,
+			Status:        status,
+			Provider:      provider,
+			ProviderID:    messageID,
+			FailureCode:   failureCode,
+			FailureReason: failureReason,
+		})
+	})
+
+	if err == errors.ErrMessageNotFound {
+		return err
 	}
 
-	if result.RowsAffected == 0 {
-		return errors.ErrMessageNotFound
+	if err != nil {
+		logger.Error("Failed to update message status", zap.Error(err), zap.String("message_id", id.String()))
+		return errors.WrapError(err, "DATABASE_ERROR", "Failed to update message status", 500)
 	}
 
 	logger.Info("Message status updated successfully",
@@ -109,10 +266,37 @@ func (r *message) UpdateStatus(ctx context.Context, id uuid.UUID, status message
 	return nil
 }
 
+// failureColumns splits a failure into its nullable failure_code and
+// failure_reason column values.
+func failureColumns(failure *domain.MessageFailure) (*webhookfailures.WebhookFailure, *string) {
+	if failure == nil {
+		return nil, nil
+	}
+	code, reason := failure.Code, failure.Reason
+	return &code, &reason
+}
+
+func (r *message) writeEvent(tx *gorm.DB, eventType domain.OutboxEventType, payload domain.MessageEventPayload) error {
+	if !r.outbox {
+		return nil
+	}
+
+	event, err := domain.NewOutboxEvent(eventType, payload)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(event).Error
+}
+
+// GetByID reads from a replica when one is configured. Callers that need to see
+// their own preceding write should pass a context from database.WithPrimary.
 func (r *message) GetByID(ctx context.Context, id uuid.UUID) (*domain.Message, error) {
+	ctx = database.WithOperation(ctx, "message.GetByID")
+
 	var message domain.Message
 
-	result := r.db.WithContext(ctx).Where("id = ?", id).First(&message)
+	result := r.db.Reader(ctx).Where("id = ?", id).First(&message)
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
 			return nil, errors.ErrMessageNotFound
@@ -125,9 +309,11 @@ func (r *message) GetByID(ctx context.Context, id uuid.UUID) (*domain.Message, e
 }
 
 func (r *message) GetTotalSentCount(ctx context.Context) (int64, error) {
+	ctx = database.WithOperation(ctx, "message.GetTotalSentCount")
+
 	var count int64
 
-	result := r.db.WithContext(ctx).
+	result := r.db.Reader(ctx).
 		Model(&domain.Message{}).
 		Where("status = ?", messagestatus.Sent).
 		Count(&count)
@@ -139,3 +325,100 @@ func (r *message) GetTotalSentCount(ctx context.Context) (int64, error) {
 
 	return count, nil
 }
+
+// CountPending returns how many messages are waiting to be sent, including those
+// currently claimed by a cycle.
+func (r *message) CountPending(ctx context.Context) (int64, error) {
+	ctx = database.WithOperation(ctx, "message.CountPending")
+
+	var count int64
+
+	result := r.db.Reader(ctx).
+		Model(&domain.Message{}).
+		Where("status = ?", messagestatus.Pending).
+		Count(&count)
+
+	if result.Error != nil {
+		logger.Error("Failed to count pending messages", zap.Error(result.Error))
+		return 0, errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to count pending messages", 500)
+	}
+
+	return count, nil
+}
+
+// GetExpiredMessages returns messages in the given status that entered it before
+// the given time, oldest first. Sent messages are aged from sent_at, failed ones
+// from failed_at and pending ones from created_at; see domain.Message.StatusChangedAt.
+func (r *message) GetExpiredMessages(ctx context.Context, status messagestatus.MessageStatus, before time.Time, limit int) ([]*domain.Message, error) {
+	ctx = database.WithOperation(ctx, "message.GetExpiredMessages")
+
+	var messages []*domain.Message
+
+	changedAt := statusChangedAtColumn(status)
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND "+changedAt+" < ?", status, before).
+		Order(changedAt + " ASC, id ASC").
+		Limit(limit).
+		Find(&messages)
+
+	if result.Error != nil {
+		logger.Error("Failed to get expired messages", zap.Error(result.Error), zap.String("status", string(status)))
+		return nil, errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to get expired messages", 500)
+	}
+
+	return messages, nil
+}
+
+// statusChangedAtColumn is the SQL counterpart of domain.Message.StatusChangedAt
+// for rows known to be in the given status.
+func statusChangedAtColumn(status messagestatus.MessageStatus) string {
+	switch status {
+	case messagestatus.Sent:
+		return "COALESCE(sent_at, created_at)"
+	case messagestatus.Failed:
+		return "COALESCE(failed_at, created_at)"
+	}
+	return "created_at"
+}
+
+func (r *message) DeleteByIDs(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	ctx = database.WithOperation(ctx, "message.DeleteByIDs")
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).
+		Where("id IN ?", ids).
+		Delete(&domain.Message{})
+
+	if result.Error != nil {
+		logger.Error("Failed to delete messages", zap.Error(result.Error), zap.Int("count", len(ids)))
+		return 0, errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to delete messages", 500)
+	}
+
+	logger.Info("Messages deleted successfully", zap.Int64("count", result.RowsAffected))
+	return result.RowsAffected, nil
+}
+
+func (r *message) Restore(ctx context.Context, messages []*domain.Message) (int64, error) {
+	ctx = database.WithOperation(ctx, "message.Restore")
+
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(messages)
+
+	if result.Error != nil {
+		logger.Error("Failed to restore messages", zap.Error(result.Error), zap.Int("count", len(messages)))
+		return 0, errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to restore messages", 500)
+	}
+
+	logger.Info("Messages restored successfully",
+		zap.Int64("restored", result.RowsAffected),
+		zap.Int("skipped", len(messages)-int(result.RowsAffected)))
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,497 @@
+package repos
+
+import (
+	"context"
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/statsintervals"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
+	"insider-message-system/pkg/errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runMessageConformance checks that a repos.Message implementation follows the
+// semantics of the SQL repository. Every backend must pass the same suite.
+func runMessageConformance(t *testing.T, newRepo func(t *testing.T) Message) {
+	ctx := context.Background()
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	newMessage := func(status messagestatus.MessageStatus, createdAt time.Time) *domain.Message {
+		return &domain.Message{
+			ID:        uuid.New(),
+			To:        "+905551111111",
+			Content:   "hello",
+			Status:    status,
+			CreatedAt: createdAt,
+		}
+	}
+
+	sentMessage := func(createdAt, sentAt time.Time) *domain.Message {
+		m := newMessage(messagestatus.Sent, createdAt)
+		m.SentAt = &sentAt
+		return m
+	}
+
+	ids := func(messages []*domain.Message) []uuid.UUID {
+		result := make([]uuid.UUID, len(messages))
+		for i, m := range messages {
+			result[i] = m.ID
+		}
+		return result
+	}
+
+	t.Run("CreateAndGetByID", func(t *testing.T) {
+		repo := newRepo(t)
+		msg := newMessage(messagestatus.Pending, base)
+		require.NoError(t, repo.Create(ctx, msg))
+
+		got, err := repo.GetByID(ctx, msg.ID)
+		require.NoError(t, err)
+		assert.Equal(t, msg.To, got.To)
+		assert.Equal(t, msg.Content, got.Content)
+		assert.Equal(t, messagestatus.Pending, got.Status)
+		assert.True(t, msg.CreatedAt.Equal(got.CreatedAt))
+		assert.Nil(t, got.SentAt)
+
+		got.Content = "changed"
+		again, err := repo.GetByID(ctx, msg.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", again.Content)
+	})
+
+	t.Run("CreateDuplicateID", func(t *testing.T) {
+		repo := newRepo(t)
+		msg := newMessage(messagestatus.Pending, base)
+		require.NoError(t, repo.Create(ctx, msg))
+		assert.Error(t, repo.Create(ctx, msg))
+	})
+
+	t.Run("GetByIDNotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.GetByID(ctx, uuid.New())
+		assert.Equal(t, errors.ErrMessageNotFound, err)
+	})
+
+	t.Run("GetPendingMessagesOldestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		newest := newMessage(messagestatus.Pending, base.Add(2*time.Minute))
+		oldest := newMessage(messagestatus.Pending, base)
+		middle := newMessage(messagestatus.Pending, base.Add(time.Minute))
+		failed := newMessage(messagestatus.Failed, base.Add(-time.Minute))
+		for _, m := range []*domain.Message{newest, oldest, middle, failed} {
+			require.NoError(t, repo.Create(ctx, m))
+		}
+
+		pending, err := repo.GetPendingMessages(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{oldest.ID, middle.ID}, ids(pending))
+
+		none, err := repo.GetPendingMessages(ctx, 0)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+
+	t.Run("ClaimPendingMessagesSkipsClaimed", func(t *testing.T) {
+		repo := newRepo(t)
+		oldest := newMessage(messagestatus.Pending, base)
+		middle := newMessage(messagestatus.Pending, base.Add(time.Minute))
+		newest := newMessage(messagestatus.Pending, base.Add(2*time.Minute))
+		for _, m := range []*domain.Message{newest, oldest, middle} {
+			require.NoError(t, repo.Create(ctx, m))
+		}
+
+		first, err := repo.ClaimPendingMessages(ctx, 2, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{oldest.ID, middle.ID}, ids(first))
+		for _, m := range first {
+			require.NotNil(t, m.ClaimedUntil)
+		}
+
+		second, err := repo.ClaimPendingMessages(ctx, 2, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{newest.ID}, ids(second))
+
+		none, err := repo.ClaimPendingMessages(ctx, 2, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, none)
+	})
+
+	t.Run("ClaimPendingMessagesAfterLeaseExpires", func(t *testing.T) {
+		repo := newRepo(t)
+		msg := newMessage(messagestatus.Pending, base)
+		require.NoError(t, repo.Create(ctx, msg))
+
+		expired, err := repo.ClaimPendingMessages(ctx, 1, -time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{msg.ID}, ids(expired))
+
+		reclaimed, err := repo.ClaimPendingMessages(ctx, 1, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{msg.ID}, ids(reclaimed))
+	})
+
+	t.Run("ReleaseClaim", func(t *testing.T) {
+		repo := newRepo(t)
+		msg := newMessage(messagestatus.Pending, base)
+		sent := newMessage(messagestatus.Sent, base)
+		require.NoError(t, repo.Create(ctx, msg))
+		require.NoError(t, repo.Create(ctx, sent))
+
+		claimed, err := repo.ClaimPendingMessages(ctx, 1, time.Hour)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+
+		require.NoError(t, repo.ReleaseClaim(ctx, msg.ID))
+		require.NoError(t, repo.ReleaseClaim(ctx, sent.ID))
+		require.NoError(t, repo.ReleaseClaim(ctx, uuid.New()))
+
+		got, err := repo.GetByID(ctx, msg.ID)
+		require.NoError(t, err)
+		assert.Equal(t, messagestatus.Pending, got.Status)
+		assert.Nil(t, got.ClaimedUntil)
+
+		again, err := repo.ClaimPendingMessages(ctx, 1, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{msg.ID}, ids(again))
+	})
+
+	t.Run("CountPending", func(t *testing.T) {
+		repo := newRepo(t)
+		require.NoError(t, repo.Create(ctx, newMessage(messagestatus.Pending, base)))
+		require.NoError(t, repo.Create(ctx, newMessage(messagestatus.Pending, base)))
+		require.NoError(t, repo.Create(ctx, newMessage(messagestatus.Sent, base)))
+
+		_, err := repo.ClaimPendingMessages(ctx, 1, time.Hour)
+		require.NoError(t, err)
+
+		count, err := repo.CountPending(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("UpdateStatusReleasesClaim", func(t *testing.T) {
+		repo := newRepo(t)
+		msg := newMessage(messagestatus.Pending, base)
+		require.NoError(t, repo.Create(ctx, msg))
+
+		claimed, err := repo.ClaimPendingMessages(ctx, 1, time.Hour)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+
+		require.NoError(t, repo.UpdateStatus(ctx, msg.ID, messagestatus.Pending, nil, nil, nil))
+
+		got, err := repo.GetByID(ctx, msg.ID)
+		require.NoError(t, err)
+		assert.Nil(t, got.ClaimedUntil)
+
+		again, err := repo.ClaimPendingMessages(ctx, 1, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{msg.ID}, ids(again))
+	})
+
+	t.Run("UpdateStatus", func(t *testing.T) {
+		repo := newRepo(t)
+		sent := newMessage(messagestatus.Pending, base)
+		failed := newMessage(messagestatus.Pending, base)
+		require.NoError(t, repo.Create(ctx, sent))
+		require.NoError(t, repo.Create(ctx, failed))
+
+		providerID := "provider-1"
+		before := time.Now().Add(-time.Second)
+		provider := "primary"
+		require.NoError(t, repo.UpdateStatus(ctx, sent.ID, messagestatus.Sent, &providerID, &provider, nil))
+
+		got, err := repo.GetByID(ctx, sent.ID)
+		require.NoError(t, err)
+		assert.Equal(t, messagestatus.Sent, got.Status)
+		require.NotNil(t, got.MessageID)
+		assert.Equal(t, providerID, *got.MessageID)
+		require.NotNil(t, got.Provider)
+		assert.Equal(t, provider, *got.Provider)
+		require.NotNil(t, got.SentAt)
+		assert.True(t, got.SentAt.After(before))
+
+		reason := "timeout"
+		require.NoError(t, repo.UpdateStatus(ctx, failed.ID, messagestatus.Failed, nil, nil, &domain.MessageFailure{Code: webhookfailures.Transient, Reason: reason}))
+
+		got, err = repo.GetByID(ctx, failed.ID)
+		require.NoError(t, err)
+		assert.Equal(t, messagestatus.Failed, got.Status)
+		require.NotNil(t, got.FailureCode)
+		assert.Equal(t, webhookfailures.Transient, *got.FailureCode)
+		require.NotNil(t, got.FailureReason)
+		assert.Equal(t, reason, *got.FailureReason)
+		assert.Nil(t, got.SentAt)
+		require.NotNil(t, got.FailedAt)
+		assert.True(t, got.FailedAt.After(before))
+
+		assert.Equal(t, errors.ErrMessageNotFound, repo.UpdateStatus(ctx, uuid.New(), messagestatus.Sent, nil, nil, nil))
+	})
+
+	t.Run("RetryLater", func(t *testing.T) {
+		repo := newRepo(t)
+		msg := newMessage(messagestatus.Pending, base)
+		require.NoError(t, repo.Create(ctx, msg))
+
+		claimed, err := repo.ClaimPendingMessages(ctx, 1, time.Minute)
+		require.NoError(t, err)
+		require.Len(t, claimed, 1)
+
+		failure := domain.MessageFailure{Code: webhookfailures.Transient, Reason: "status 503"}
+		require.NoError(t, repo.RetryLater(ctx, msg.ID, 1, time.Now().Add(time.Hour), failure))
+
+		got, err := repo.GetByID(ctx, msg.ID)
+		require.NoError(t, err)
+		assert.Equal(t, messagestatus.Pending, got.Status)
+		assert.Equal(t, 1, got.FailedAttempts)
+		require.NotNil(t, got.FailureCode)
+		assert.Equal(t, webhookfailures.Transient, *got.FailureCode)
+		require.NotNil(t, got.FailureReason)
+		assert.Equal(t, failure.Reason, *got.FailureReason)
+
+		// The message is held back until the retry time.
+		again, err := repo.ClaimPendingMessages(ctx, 1, time.Minute)
+		require.NoError(t, err)
+		assert.Empty(t, again)
+
+		// A later successful send clears the failure.
+		providerID := "provider-1"
+		require.NoError(t, repo.UpdateStatus(ctx, msg.ID, messagestatus.Sent, &providerID, nil, nil))
+		got, err = repo.GetByID(ctx, msg.ID)
+		require.NoError(t, err)
+		assert.Nil(t, got.FailureCode)
+		assert.Nil(t, got.FailureReason)
+	})
+
+	t.Run("GetSentMessagesNewestFirstWithPagination", func(t *testing.T) {
+		repo := newRepo(t)
+		first := sentMessage(base, base.Add(time.Minute))
+		second := sentMessage(base, base.Add(2*time.Minute))
+		third := sentMessage(base, base.Add(3*time.Minute))
+		pending := newMessage(messagestatus.Pending, base)
+		_, err := repo.Restore(ctx, []*domain.Message{second, pending, first, third})
+		require.NoError(t, err)
+
+		page, err := repo.GetSentMessages(ctx, 0, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{third.ID, second.ID}, ids(page))
+
+		page, err = repo.GetSentMessages(ctx, 2, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{first.ID}, ids(page))
+
+		page, err = repo.GetSentMessages(ctx, 10, 2)
+		require.NoError(t, err)
+		assert.Empty(t, page)
+
+		total, err := repo.GetTotalSentCount(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+	})
+
+	t.Run("GetSentMessagesNullSentAtFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		withSentAt := sentMessage(base, base.Add(time.Minute))
+		withoutSentAt := newMessage(messagestatus.Sent, base)
+		_, err := repo.Restore(ctx, []*domain.Message{withSentAt, withoutSentAt})
+		require.NoError(t, err)
+
+		page, err := repo.GetSentMessages(ctx, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{withoutSentAt.ID, withSentAt.ID}, ids(page))
+	})
+
+	t.Run("GetSentMessagesTiesByID", func(t *testing.T) {
+		repo := newRepo(t)
+		a := sentMessage(base, base.Add(time.Minute))
+		b := sentMessage(base, base.Add(time.Minute))
+		_, err := repo.Restore(ctx, []*domain.Message{a, b})
+		require.NoError(t, err)
+
+		expected := []uuid.UUID{a.ID, b.ID}
+		if b.ID.String() < a.ID.String() {
+			expected = []uuid.UUID{b.ID, a.ID}
+		}
+
+		page, err := repo.GetSentMessages(ctx, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, expected, ids(page))
+	})
+
+	t.Run("GetExpiredMessages", func(t *testing.T) {
+		repo := newRepo(t)
+		old := newMessage(messagestatus.Failed, base.Add(-2*time.Hour))
+		older := newMessage(messagestatus.Failed, base.Add(-3*time.Hour))
+		recent := newMessage(messagestatus.Failed, base)
+		otherStatus := newMessage(messagestatus.Pending, base.Add(-4*time.Hour))
+		for _, m := range []*domain.Message{old, older, recent, otherStatus} {
+			require.NoError(t, repo.Create(ctx, m))
+		}
+
+		expired, err := repo.GetExpiredMessages(ctx, messagestatus.Failed, base.Add(-time.Hour), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{older.ID, old.ID}, ids(expired))
+
+		limited, err := repo.GetExpiredMessages(ctx, messagestatus.Failed, base.Add(-time.Hour), 1)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{older.ID}, ids(limited))
+	})
+
+	t.Run("GetExpiredSentMessagesBySentAt", func(t *testing.T) {
+		repo := newRepo(t)
+		// Created long ago but only sent recently, so it has not expired yet.
+		sentLate := sentMessage(base.Add(-48*time.Hour), base)
+		sentEarly := sentMessage(base.Add(-3*time.Hour), base.Add(-2*time.Hour))
+		noSentAt := newMessage(messagestatus.Sent, base.Add(-4*time.Hour))
+		for _, m := range []*domain.Message{sentLate, sentEarly, noSentAt} {
+			require.NoError(t, repo.Create(ctx, m))
+		}
+
+		expired, err := repo.GetExpiredMessages(ctx, messagestatus.Sent, base.Add(-time.Hour), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{noSentAt.ID, sentEarly.ID}, ids(expired))
+	})
+
+	t.Run("DeleteByIDs", func(t *testing.T) {
+		repo := newRepo(t)
+		keep := newMessage(messagestatus.Sent, base)
+		remove := newMessage(messagestatus.Sent, base)
+		require.NoError(t, repo.Create(ctx, keep))
+		require.NoError(t, repo.Create(ctx, remove))
+
+		deleted, err := repo.DeleteByIDs(ctx, []uuid.UUID{remove.ID, uuid.New()})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		deleted, err = repo.DeleteByIDs(ctx, nil)
+		require.NoError(t, err)
+		assert.Zero(t, deleted)
+
+		_, err = repo.GetByID(ctx, remove.ID)
+		assert.Equal(t, errors.ErrMessageNotFound, err)
+		_, err = repo.GetByID(ctx, keep.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("RestoreSkipsExisting", func(t *testing.T) {
+		repo := newRepo(t)
+		existing := newMessage(messagestatus.Pending, base)
+		require.NoError(t, repo.Create(ctx, existing))
+
+		conflicting := *existing
+		conflicting.Content = "overwritten"
+		fresh := sentMessage(base, base.Add(time.Minute))
+
+		restored, err := repo.Restore(ctx, []*domain.Message{&conflicting, fresh})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), restored)
+
+		got, err := repo.GetByID(ctx, existing.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", got.Content)
+
+		got, err = repo.GetByID(ctx, fresh.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got.SentAt)
+		assert.True(t, fresh.SentAt.Equal(*got.SentAt))
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.Restore(ctx, []*domain.Message{
+			sentMessage(base.Add(5*time.Minute), base.Add(5*time.Minute+time.Second)),
+			sentMessage(base.Add(10*time.Minute), base.Add(10*time.Minute+3*time.Second)),
+			newMessage(messagestatus.Failed, base.Add(70*time.Minute)),
+			newMessage(messagestatus.Pending, base.Add(3*time.Hour)),
+			newMessage(messagestatus.Sent, base.Add(-time.Minute)),
+		})
+		require.NoError(t, err)
+
+		from, to := base, base.Add(2*time.Hour)
+
+		counts, err := repo.CountByStatus(ctx, from, to)
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusCounts{messagestatus.Sent: 2, messagestatus.Failed: 1}, counts)
+
+		points, err := repo.GetStatusTimeSeries(ctx, from, to, statsintervals.Hour)
+		require.NoError(t, err)
+		require.Len(t, points, 2)
+		assert.True(t, base.Equal(points[0].Bucket))
+		assert.Equal(t, domain.StatusCounts{messagestatus.Sent: 2}, points[0].Counts)
+		assert.True(t, base.Add(time.Hour).Equal(points[1].Bucket))
+		assert.Equal(t, domain.StatusCounts{messagestatus.Failed: 1}, points[1].Counts)
+
+		latency, err := repo.GetSendLatency(ctx, from, to)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), latency.Count)
+		assert.InDelta(t, 2000, latency.P50Ms, 1)
+		assert.InDelta(t, 2980, latency.P99Ms, 1)
+	})
+
+	t.Run("StatsUseStatusTimeAcrossMidnight", func(t *testing.T) {
+		repo := newRepo(t)
+		day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+		nextDay := day.Add(24 * time.Hour)
+
+		sentAfterMidnight := sentMessage(nextDay.Add(-10*time.Minute), nextDay.Add(10*time.Minute))
+		failedAfterMidnight := newMessage(messagestatus.Failed, nextDay.Add(-5*time.Minute))
+		failedAt := nextDay.Add(5 * time.Minute)
+		failedAfterMidnight.FailedAt = &failedAt
+		pendingBeforeMidnight := newMessage(messagestatus.Pending, nextDay.Add(-time.Minute))
+		_, err := repo.Restore(ctx, []*domain.Message{sentAfterMidnight, failedAfterMidnight, pendingBeforeMidnight})
+		require.NoError(t, err)
+
+		points, err := repo.GetStatusTimeSeries(ctx, day, nextDay.Add(24*time.Hour), statsintervals.Day)
+		require.NoError(t, err)
+		require.Len(t, points, 2)
+		assert.True(t, day.Equal(points[0].Bucket))
+		assert.Equal(t, domain.StatusCounts{messagestatus.Pending: 1}, points[0].Counts)
+		assert.True(t, nextDay.Equal(points[1].Bucket))
+		assert.Equal(t, domain.StatusCounts{messagestatus.Sent: 1, messagestatus.Failed: 1}, points[1].Counts)
+
+		counts, err := repo.CountByStatus(ctx, day, nextDay)
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusCounts{messagestatus.Pending: 1}, counts)
+
+		latency, err := repo.GetSendLatency(ctx, nextDay, nextDay.Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), latency.Count)
+		assert.InDelta(t, float64(20*time.Minute/time.Millisecond), latency.P50Ms, 1)
+	})
+
+	t.Run("ConcurrentCreates", func(t *testing.T) {
+		repo := newRepo(t)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				assert.NoError(t, repo.Create(ctx, newMessage(messagestatus.Pending, base.Add(time.Duration(i)*time.Second))))
+			}(i)
+		}
+		wg.Wait()
+
+		pending, err := repo.GetPendingMessages(ctx, 100)
+		require.NoError(t, err)
+		assert.Len(t, pending, 20)
+	})
+}
+
+func TestMessageConformance_SQL(t *testing.T) {
+	runMessageConformance(t, func(t *testing.T) Message {
+		return NewMessage(setupTestDB(t))
+	})
+}
+
+func TestMessageConformance_Memory(t *testing.T) {
+	runMessageConformance(t, func(t *testing.T) Message {
+		return NewMemoryMessage()
+	})
+}
@@ -0,0 +1,207 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/database"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/statsintervals"
+	"insider-message-system/pkg/errors"
+	"insider-message-system/pkg/logger"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const bucketLayout = "2006-01-02T15:04:05Z"
+
+// statusChangedAtExpression is the SQL counterpart of domain.Message.StatusChangedAt
+// for rows in any status. Stats attribute each message to the time it entered its
+// current status: sent_at for sent, failed_at for failed and created_at for pending.
+var statusChangedAtExpression = fmt.Sprintf("(CASE status WHEN '%s' THEN %s WHEN '%s' THEN %s ELSE created_at END)",
+	messagestatus.Sent, statusChangedAtColumn(messagestatus.Sent),
+	messagestatus.Failed, statusChangedAtColumn(messagestatus.Failed))
+
+type statusCountRow struct {
+	Status messagestatus.MessageStatus
+	Count  int64
+}
+
+type bucketCountRow struct {
+	Bucket string
+	Status messagestatus.MessageStatus
+	Count  int64
+}
+
+// CountByStatus counts the messages that entered each status within [from, to).
+func (r *message) CountByStatus(ctx context.Context, from, to time.Time) (domain.StatusCounts, error) {
+	ctx = database.WithOperation(ctx, "message.CountByStatus")
+
+	var rows []statusCountRow
+
+	result := r.db.Reader(ctx).
+		Model(&domain.Message{}).
+		Select("status, COUNT(*) AS count").
+		Where(statusChangedAtExpression+" >= ? AND "+statusChangedAtExpression+" < ?", from, to).
+		Group("status").
+		Scan(&rows)
+
+	if result.Error != nil {
+		logger.Error("Failed to count messages by status", zap.Error(result.Error))
+		return nil, errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to count messages by status", 500)
+	}
+
+	counts := make(domain.StatusCounts, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+
+	return counts, nil
+}
+
+// GetStatusTimeSeries buckets messages by the time they entered their status, so a
+// message created before midnight and sent after it counts as sent on the second day.
+func (r *message) GetStatusTimeSeries(ctx context.Context, from, to time.Time, interval statsintervals.StatsInterval) ([]domain.TimeSeriesPoint, error) {
+	ctx = database.WithOperation(ctx, "message.GetStatusTimeSeries")
+
+	var rows []bucketCountRow
+
+	bucket := r.bucketExpression(interval)
+	result := r.db.Reader(ctx).
+		Model(&domain.Message{}).
+		Select(bucket+" AS bucket, status, COUNT(*) AS count").
+		Where(statusChangedAtExpression+" >= ? AND "+statusChangedAtExpression+" < ?", from, to).
+		Group(bucket + ", status").
+		Order("bucket ASC").
+		Scan(&rows)
+
+	if result.Error != nil {
+		logger.Error("Failed to get message time series", zap.Error(result.Error))
+		return nil, errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to get message time series", 500)
+	}
+
+	points := make([]domain.TimeSeriesPoint, 0, len(rows))
+	for _, row := range rows {
+		bucketTime, err := time.Parse(bucketLayout, row.Bucket)
+		if err != nil {
+			return nil, errors.WrapError(err, "DATABASE_ERROR", "Failed to parse time series bucket", 500)
+		}
+
+		if n := len(points); n > 0 && points[n-1].Bucket.Equal(bucketTime) {
+			points[n-1].Counts[row.Status] = row.Count
+			continue
+		}
+
+		points = append(points, domain.TimeSeriesPoint{
+			Bucket: bucketTime,
+			Counts: domain.StatusCounts{row.Status: row.Count},
+		})
+	}
+
+	return points, nil
+}
+
+// GetSendLatency measures created_at to sent_at for the messages sent within [from, to).
+func (r *message) GetSendLatency(ctx context.Context, from, to time.Time) (*domain.LatencyStats, error) {
+	ctx = database.WithOperation(ctx, "message.GetSendLatency")
+
+	if r.isPostgres() {
+		return r.getSendLatencyPostgres(ctx, from, to)
+	}
+	return r.getSendLatencyGeneric(ctx, from, to)
+}
+
+func (r *message) getSendLatencyPostgres(ctx context.Context, from, to time.Time) (*domain.LatencyStats, error) {
+	var stats domain.LatencyStats
+
+	result := r.db.Reader(ctx).
+		Model(&domain.Message{}).
+		Select(`COUNT(*) AS count,
+			COALESCE(percentile_cont(0.50) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (sent_at - created_at)) * 1000), 0) AS p50_ms,
+			COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (sent_at - created_at)) * 1000), 0) AS p95_ms,
+			COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (sent_at - created_at)) * 1000), 0) AS p99_ms`).
+		Where("status = ? AND sent_at >= ? AND sent_at < ?", messagestatus.Sent, from, to).
+		Scan(&stats)
+
+	if result.Error != nil {
+		logger.Error("Failed to get send latency percentiles", zap.Error(result.Error))
+		return nil, errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to get send latency", 500)
+	}
+
+	return &stats, nil
+}
+
+// getSendLatencyGeneric computes the percentiles in Go for dialects without
+// ordered-set aggregates, such as the SQLite database used in tests.
+func (r *message) getSendLatencyGeneric(ctx context.Context, from, to time.Time) (*domain.LatencyStats, error) {
+	var rows []struct {
+		CreatedAt time.Time
+		SentAt    time.Time
+	}
+
+	result := r.db.Reader(ctx).
+		Model(&domain.Message{}).
+		Select("created_at, sent_at").
+		Where("status = ? AND sent_at >= ? AND sent_at < ?", messagestatus.Sent, from, to).
+		Scan(&rows)
+
+	if result.Error != nil {
+		logger.Error("Failed to get send latencies", zap.Error(result.Error))
+		return nil, errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to get send latency", 500)
+	}
+
+	latencies := make([]float64, len(rows))
+	for i, row := range rows {
+		latencies[i] = float64(row.SentAt.Sub(row.CreatedAt)) / float64(time.Millisecond)
+	}
+
+	return LatencyStatsFromSamples(latencies), nil
+}
+
+// LatencyStatsFromSamples builds LatencyStats from raw latency samples in milliseconds.
+// Percentiles are interpolated the same way as Postgres percentile_cont.
+func LatencyStatsFromSamples(latencies []float64) *domain.LatencyStats {
+	sorted := append([]float64(nil), latencies...)
+	sort.Float64s(sorted)
+
+	return &domain.LatencyStats{
+		Count: int64(len(sorted)),
+		P50Ms: percentileCont(sorted, 0.50),
+		P95Ms: percentileCont(sorted, 0.95),
+		P99Ms: percentileCont(sorted, 0.99),
+	}
+}
+
+func percentileCont(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	position := p * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	if lower == upper {
+		return sorted[lower]
+	}
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
+
+func (r *message) bucketExpression(interval statsintervals.StatsInterval) string {
+	if r.isPostgres() {
+		return fmt.Sprintf(`to_char(date_trunc('%s', %s AT TIME ZONE 'UTC'), 'YYYY-MM-DD"T"HH24:MI:SS"Z"')`, interval, statusChangedAtExpression)
+	}
+
+	if interval == statsintervals.Day {
+		return "strftime('%Y-%m-%dT00:00:00Z', " + statusChangedAtExpression + ")"
+	}
+	return "strftime('%Y-%m-%dT%H:00:00Z', " + statusChangedAtExpression + ")"
+}
+
+func (r *message) isPostgres() bool {
+	return strings.ToLower(r.db.Dialector.Name()) == "postgres"
+}
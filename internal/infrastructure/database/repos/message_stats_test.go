@@ -0,0 +1,104 @@
+package repos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/statsintervals"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedStatsMessages(t *testing.T, repo Message, base time.Time) {
+	sent := func(createdAt time.Time, latency time.Duration) *domain.Message {
+		sentAt := createdAt.Add(latency)
+		return &domain.Message{ID: uuid.New(), To: "+1", Content: "sent", Status: messagestatus.Sent, CreatedAt: createdAt, SentAt: &sentAt}
+	}
+
+	messages := []*domain.Message{
+		sent(base.Add(10*time.Minute), 1*time.Second),
+		sent(base.Add(20*time.Minute), 2*time.Second),
+		sent(base.Add(70*time.Minute), 3*time.Second),
+		sent(base.Add(80*time.Minute), 4*time.Second),
+		{ID: uuid.New(), To: "+1", Content: "failed", Status: messagestatus.Failed, CreatedAt: base.Add(15 * time.Minute)},
+		{ID: uuid.New(), To: "+1", Content: "pending", Status: messagestatus.Pending, CreatedAt: base.Add(75 * time.Minute)},
+		// Created before the window but sent at its start, so it counts as sent in it.
+		sent(base.Add(-time.Hour), time.Hour),
+		// Sent just before the window.
+		sent(base.Add(-2*time.Hour), time.Hour),
+	}
+
+	for _, msg := range messages {
+		require.NoError(t, repo.Create(context.Background(), msg))
+	}
+}
+
+func TestMessageRepo_CountByStatus(t *testing.T) {
+	repo := NewMessage(setupTestDB(t))
+	base := time.Now().Truncate(time.Hour).Add(-3 * time.Hour)
+	seedStatsMessages(t, repo, base)
+
+	counts, err := repo.CountByStatus(context.Background(), base, base.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusCounts{
+		messagestatus.Sent:    5,
+		messagestatus.Failed:  1,
+		messagestatus.Pending: 1,
+	}, counts)
+}
+
+func TestMessageRepo_GetStatusTimeSeries(t *testing.T) {
+	repo := NewMessage(setupTestDB(t))
+	base := time.Now().UTC().Truncate(time.Hour).Add(-3 * time.Hour)
+	seedStatsMessages(t, repo, base)
+
+	points, err := repo.GetStatusTimeSeries(context.Background(), base, base.Add(2*time.Hour), statsintervals.Hour)
+	require.NoError(t, err)
+	require.Len(t, points, 2)
+	assert.True(t, base.Equal(points[0].Bucket))
+	assert.Equal(t, domain.StatusCounts{messagestatus.Sent: 3, messagestatus.Failed: 1}, points[0].Counts)
+	assert.True(t, base.Add(time.Hour).Equal(points[1].Bucket))
+	assert.Equal(t, domain.StatusCounts{messagestatus.Sent: 2, messagestatus.Pending: 1}, points[1].Counts)
+
+	daily, err := repo.GetStatusTimeSeries(context.Background(), base, base.Add(2*time.Hour), statsintervals.Day)
+	require.NoError(t, err)
+	total := int64(0)
+	for _, point := range daily {
+		assert.Equal(t, 0, point.Bucket.Hour())
+		total += point.Counts[messagestatus.Sent]
+	}
+	assert.Equal(t, int64(5), total)
+}
+
+func TestMessageRepo_GetSendLatency(t *testing.T) {
+	repo := NewMessage(setupTestDB(t))
+	base := time.Now().Truncate(time.Hour).Add(-3 * time.Hour)
+	seedStatsMessages(t, repo, base)
+
+	latency, err := repo.GetSendLatency(context.Background(), base, base.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), latency.Count)
+	assert.InDelta(t, 3000, latency.P50Ms, 1)
+	assert.InDelta(t, 2880800, latency.P95Ms, 1)
+	assert.InDelta(t, 3456160, latency.P99Ms, 1)
+
+	empty, err := repo.GetSendLatency(context.Background(), base.Add(-48*time.Hour), base.Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), empty.Count)
+	assert.Equal(t, float64(0), empty.P99Ms)
+}
+
+func TestLatencyStatsFromSamples(t *testing.T) {
+	stats := LatencyStatsFromSamples([]float64{100})
+	assert.Equal(t, int64(1), stats.Count)
+	assert.Equal(t, float64(100), stats.P50Ms)
+	assert.Equal(t, float64(100), stats.P99Ms)
+
+	stats = LatencyStatsFromSamples([]float64{40, 10, 30, 20})
+	assert.Equal(t, float64(25), stats.P50Ms)
+}
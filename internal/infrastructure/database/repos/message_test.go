@@ -2,12 +2,16 @@ package repos
 
 import (
 	"context"
+	"database/sql"
 	"insider-message-system/internal/domain"
 	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/statsintervals"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
@@ -25,23 +29,63 @@ func (m *mockMessageRepo) GetSentMessages(ctx context.Context, offset, limit int
 func (m *mockMessageRepo) GetPendingMessages(ctx context.Context, limit int) ([]*domain.Message, error) {
 	return nil, nil
 }
+func (m *mockMessageRepo) ClaimPendingMessages(ctx context.Context, limit int, lease time.Duration) ([]*domain.Message, error) {
+	return nil, nil
+}
+func (m *mockMessageRepo) ReleaseClaim(ctx context.Context, id uuid.UUID) error { return nil }
+func (m *mockMessageRepo) RetryLater(ctx context.Context, id uuid.UUID, failedAttempts int, until time.Time, failure domain.MessageFailure) error {
+	return nil
+}
 func (m *mockMessageRepo) GetTotalSentCount(ctx context.Context) (int64, error) { return 0, nil }
-func (m *mockMessageRepo) UpdateStatus(ctx context.Context, id uuid.UUID, status messagestatus.MessageStatus, messageID, failureReason *string) error {
+func (m *mockMessageRepo) CountPending(ctx context.Context) (int64, error)      { return 0, nil }
+func (m *mockMessageRepo) UpdateStatus(ctx context.Context, id uuid.UUID, status messagestatus.MessageStatus, messageID, provider *string, failure *domain.MessageFailure) error {
 	return nil
 }
 func (m *mockMessageRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Message, error) {
 	return nil, nil
 }
+func (m *mockMessageRepo) GetExpiredMessages(ctx context.Context, status messagestatus.MessageStatus, before time.Time, limit int) ([]*domain.Message, error) {
+	return nil, nil
+}
+func (m *mockMessageRepo) DeleteByIDs(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	return 0, nil
+}
+func (m *mockMessageRepo) Restore(ctx context.Context, messages []*domain.Message) (int64, error) {
+	return 0, nil
+}
+func (m *mockMessageRepo) CountByStatus(ctx context.Context, from, to time.Time) (domain.StatusCounts, error) {
+	return nil, nil
+}
+func (m *mockMessageRepo) GetStatusTimeSeries(ctx context.Context, from, to time.Time, interval statsintervals.StatsInterval) ([]domain.TimeSeriesPoint, error) {
+	return nil, nil
+}
+func (m *mockMessageRepo) GetSendLatency(ctx context.Context, from, to time.Time) (*domain.LatencyStats, error) {
+	return nil, nil
+}
 
 func TestMessageRepoInterface(t *testing.T) {
 	var repo Message = &mockMessageRepo{}
 	assert.NotNil(t, repo)
 }
 
+// sqliteWithNowDriver is SQLite with Postgres' NOW() registered, since the
+// repository stamps sent_at with the database clock.
+const sqliteWithNowDriver = "sqlite3_with_now"
+
+func init() {
+	sql.Register(sqliteWithNowDriver, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("NOW", func() string {
+				return time.Now().UTC().Format(sqlite3.SQLiteTimestampFormats[0])
+			}, false)
+		},
+	})
+}
+
 func setupTestDB(t *testing.T) *database.DB {
-	gdb, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	gdb, err := gorm.Open(sqlite.New(sqlite.Config{DriverName: sqliteWithNowDriver, DSN: ":memory:"}), &gorm.Config{})
 	require.NoError(t, err)
-	err = gdb.AutoMigrate(&domain.Message{})
+	err = gdb.AutoMigrate(&domain.Message{}, &domain.OutboxEvent{}, &domain.OutboxDelivery{}, &domain.SchedulerSettings{}, &domain.SchedulerDesiredState{})
 	require.NoError(t, err)
 	return &database.DB{DB: gdb}
 }
@@ -115,15 +159,16 @@ func TestMessageRepo_UpdateStatus(t *testing.T) {
 	require.NoError(t, repo.Create(context.Background(), msg))
 
 	messageID := "external-id"
-	failureReason := "failed to send"
-	err := repo.UpdateStatus(context.Background(), msg.ID, messagestatus.Failed, &messageID, &failureReason)
+	failure := &domain.MessageFailure{Code: webhookfailures.Permanent, Reason: "failed to send"}
+	err := repo.UpdateStatus(context.Background(), msg.ID, messagestatus.Failed, &messageID, nil, failure)
 	require.NoError(t, err)
 
 	updated, err := repo.GetByID(context.Background(), msg.ID)
 	require.NoError(t, err)
 	require.Equal(t, messagestatus.Failed, updated.Status)
 	require.Equal(t, &messageID, updated.MessageID)
-	require.Equal(t, &failureReason, updated.FailureReason)
+	require.Equal(t, &failure.Code, updated.FailureCode)
+	require.Equal(t, &failure.Reason, updated.FailureReason)
 }
 
 func TestMessageRepo_GetTotalSentCount(t *testing.T) {
@@ -144,6 +189,65 @@ func TestMessageRepo_GetTotalSentCount(t *testing.T) {
 	require.Equal(t, int64(1), count)
 }
 
+func TestMessageRepo_GetExpiredMessagesAndDelete(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMessage(db)
+	now := time.Now()
+
+	old := &domain.Message{ID: uuid.New(), To: "+1", Content: "old", Status: messagestatus.Sent, CreatedAt: now.Add(-48 * time.Hour)}
+	older := &domain.Message{ID: uuid.New(), To: "+1", Content: "older", Status: messagestatus.Sent, CreatedAt: now.Add(-72 * time.Hour)}
+	fresh := &domain.Message{ID: uuid.New(), To: "+1", Content: "fresh", Status: messagestatus.Sent, CreatedAt: now}
+	failed := &domain.Message{ID: uuid.New(), To: "+1", Content: "failed", Status: messagestatus.Failed, CreatedAt: now.Add(-72 * time.Hour)}
+	for _, msg := range []*domain.Message{old, older, fresh, failed} {
+		require.NoError(t, repo.Create(context.Background(), msg))
+	}
+
+	expired, err := repo.GetExpiredMessages(context.Background(), messagestatus.Sent, now.Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 2)
+	require.Equal(t, older.ID, expired[0].ID)
+	require.Equal(t, old.ID, expired[1].ID)
+
+	limited, err := repo.GetExpiredMessages(context.Background(), messagestatus.Sent, now.Add(-24*time.Hour), 1)
+	require.NoError(t, err)
+	require.Len(t, limited, 1)
+
+	deleted, err := repo.DeleteByIDs(context.Background(), []uuid.UUID{old.ID, older.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), deleted)
+
+	_, err = repo.GetByID(context.Background(), old.ID)
+	require.Error(t, err)
+
+	deleted, err = repo.DeleteByIDs(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), deleted)
+}
+
+func TestMessageRepo_Restore(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMessage(db)
+
+	existing := &domain.Message{ID: uuid.New(), To: "+1", Content: "existing", Status: messagestatus.Sent, CreatedAt: time.Now()}
+	require.NoError(t, repo.Create(context.Background(), existing))
+
+	archived := &domain.Message{ID: uuid.New(), To: "+1", Content: "archived", Status: messagestatus.Sent, CreatedAt: time.Now()}
+	duplicate := *existing
+	duplicate.Content = "changed"
+
+	restored, err := repo.Restore(context.Background(), []*domain.Message{archived, &duplicate})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), restored)
+
+	got, err := repo.GetByID(context.Background(), existing.ID)
+	require.NoError(t, err)
+	require.Equal(t, "existing", got.Content)
+
+	got, err = repo.GetByID(context.Background(), archived.ID)
+	require.NoError(t, err)
+	require.Equal(t, "archived", got.Content)
+}
+
 func ptrTime(t time.Time) *time.Time {
 	return &t
 }
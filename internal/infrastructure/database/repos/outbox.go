@@ -0,0 +1,110 @@
+package repos
+
+import (
+	"context"
+	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/database"
+	"insider-message-system/pkg/errors"
+	"insider-message-system/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Outbox defines the interface for reading outbox events and tracking their delivery to sinks.
+type Outbox interface {
+	GetUndeliveredEvents(ctx context.Context, sink string, limit int) ([]*domain.OutboxEvent, error)
+	MarkDelivered(ctx context.Context, sink string, eventID int64) error
+	DeleteDeliveredEvents(ctx context.Context, sinks []string) (int64, error)
+}
+
+type outbox struct {
+	db *database.DB
+}
+
+// NewOutbox creates a new outbox repository with the given database connection.
+func NewOutbox(db *database.DB) Outbox {
+	return &outbox{db: db}
+}
+
+// GetUndeliveredEvents returns, in ID order, up to limit events that have not been
+// delivered to the sink. Events are selected by the absence of a delivery record
+// rather than by ID, so an event whose transaction commits after a higher ID has
+// already been relayed is picked up on the next pass instead of being skipped.
+func (r *outbox) GetUndeliveredEvents(ctx context.Context, sink string, limit int) ([]*domain.OutboxEvent, error) {
+	ctx = database.WithOperation(ctx, "outbox.GetUndeliveredEvents")
+
+	var events []*domain.OutboxEvent
+
+	result := r.db.WithContext(ctx).
+		Where("NOT EXISTS (SELECT 1 FROM outbox_deliveries d WHERE d.sink = ? AND d.event_id = outbox_events.id)", sink).
+		Order("id ASC").
+		Limit(limit).
+		Find(&events)
+
+	if result.Error != nil {
+		logger.Error("Failed to get outbox events", zap.Error(result.Error), zap.String("sink", sink))
+		return nil, errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to get outbox events", 500)
+	}
+
+	return events, nil
+}
+
+func (r *outbox) MarkDelivered(ctx context.Context, sink string, eventID int64) error {
+	ctx = database.WithOperation(ctx, "outbox.MarkDelivered")
+
+	delivery := domain.OutboxDelivery{
+		Sink:        sink,
+		EventID:     eventID,
+		DeliveredAt: time.Now(),
+	}
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&delivery)
+
+	if result.Error != nil {
+		logger.Error("Failed to record outbox delivery", zap.Error(result.Error), zap.String("sink", sink), zap.Int64("event_id", eventID))
+		return errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to record outbox delivery", 500)
+	}
+
+	return nil
+}
+
+// DeleteDeliveredEvents removes the events that every one of the given sinks has
+// delivered, together with their delivery records, and returns how many events
+// were deleted.
+func (r *outbox) DeleteDeliveredEvents(ctx context.Context, sinks []string) (int64, error) {
+	ctx = database.WithOperation(ctx, "outbox.DeleteDeliveredEvents")
+
+	if len(sinks) == 0 {
+		return 0, nil
+	}
+
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		delivered := tx.Model(&domain.OutboxDelivery{}).
+			Select("event_id").
+			Where("sink IN ?", sinks).
+			Group("event_id").
+			Having("COUNT(*) = ?", len(sinks))
+
+		result := tx.Where("id IN (?)", delivered).Delete(&domain.OutboxEvent{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+
+		return tx.Where("NOT EXISTS (SELECT 1 FROM outbox_events e WHERE e.id = outbox_deliveries.event_id)").
+			Delete(&domain.OutboxDelivery{}).Error
+	})
+
+	if err != nil {
+		logger.Error("Failed to delete delivered outbox events", zap.Error(err))
+		return 0, errors.WrapError(err, "DATABASE_ERROR", "Failed to delete delivered outbox events", 500)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,167 @@
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/database"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
+	"insider-message-system/pkg/errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageRepo_WithOutbox_RecordsLifecycleEvents(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMessageWithOutbox(db)
+	outboxRepo := NewOutbox(db)
+	ctx := context.Background()
+
+	msg := &domain.Message{
+		ID:        uuid.New(),
+		To:        "+1234567890",
+		Content:   "hello",
+		Status:    messagestatus.Pending,
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, repo.Create(ctx, msg))
+
+	reason := "webhook timeout"
+	require.NoError(t, repo.UpdateStatus(ctx, msg.ID, messagestatus.Failed, nil, nil, &domain.MessageFailure{Code: webhookfailures.Transient, Reason: reason}))
+
+	events, err := outboxRepo.GetUndeliveredEvents(ctx, "audit", 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	assert.Equal(t, domain.EventMessageCreated, events[0].EventType)
+	assert.Equal(t, domain.EventMessageFailed, events[1].EventType)
+	assert.Less(t, events[0].ID, events[1].ID)
+
+	var payload domain.MessageEventPayload
+	require.NoError(t, json.Unmarshal(events[1].Payload, &payload))
+	assert.Equal(t, msg.ID, payload.MessageID)
+	assert.Equal(t, messagestatus.Failed, payload.Status)
+	require.NotNil(t, payload.FailureCode)
+	assert.Equal(t, webhookfailures.Transient, *payload.FailureCode)
+	require.NotNil(t, payload.FailureReason)
+	assert.Equal(t, reason, *payload.FailureReason)
+}
+
+func TestMessageRepo_WithoutOutbox_RecordsNoEvents(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMessage(db)
+	ctx := context.Background()
+
+	msg := &domain.Message{ID: uuid.New(), To: "+1234567890", Content: "hello", Status: messagestatus.Pending, CreatedAt: time.Now()}
+	require.NoError(t, repo.Create(ctx, msg))
+
+	events, err := NewOutbox(db).GetUndeliveredEvents(ctx, "audit", 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestMessageRepo_WithOutbox_UpdateMissingMessageWritesNoEvent(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewMessageWithOutbox(db)
+	ctx := context.Background()
+
+	err := repo.UpdateStatus(ctx, uuid.New(), messagestatus.Failed, nil, nil, nil)
+	assert.Equal(t, errors.ErrMessageNotFound, err)
+
+	events, err := NewOutbox(db).GetUndeliveredEvents(ctx, "audit", 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func createOutboxEvent(t *testing.T, db *database.DB, id int64) {
+	t.Helper()
+	event, err := domain.NewOutboxEvent(domain.EventMessageCreated, domain.MessageEventPayload{MessageID: uuid.New(), Status: messagestatus.Pending})
+	require.NoError(t, err)
+	event.ID = id
+	require.NoError(t, db.Create(event).Error)
+}
+
+func eventIDs(events []*domain.OutboxEvent) []int64 {
+	ids := make([]int64, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
+
+func TestOutboxRepo_GetUndeliveredEvents(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewOutbox(db)
+	ctx := context.Background()
+
+	for id := int64(1); id <= 5; id++ {
+		createOutboxEvent(t, db, id)
+	}
+	require.NoError(t, repo.MarkDelivered(ctx, "audit", 1))
+	require.NoError(t, repo.MarkDelivered(ctx, "audit", 3))
+	// Recording the same delivery twice is harmless.
+	require.NoError(t, repo.MarkDelivered(ctx, "audit", 3))
+
+	events, err := repo.GetUndeliveredEvents(ctx, "audit", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 4}, eventIDs(events))
+
+	events, err = repo.GetUndeliveredEvents(ctx, "other", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, eventIDs(events))
+}
+
+func TestOutboxRepo_EventCommittedOutOfOrderIsNotLost(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewOutbox(db)
+	ctx := context.Background()
+
+	// Event 2 becomes visible first and is delivered to both sinks.
+	createOutboxEvent(t, db, 2)
+	require.NoError(t, repo.MarkDelivered(ctx, "audit", 2))
+	require.NoError(t, repo.MarkDelivered(ctx, "analytics", 2))
+
+	// Event 1, allocated earlier by a slower transaction, commits afterwards.
+	createOutboxEvent(t, db, 1)
+
+	deleted, err := repo.DeleteDeliveredEvents(ctx, []string{"audit", "analytics"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	events, err := repo.GetUndeliveredEvents(ctx, "audit", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, eventIDs(events))
+}
+
+func TestOutboxRepo_DeleteDeliveredEvents(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewOutbox(db)
+	ctx := context.Background()
+
+	for id := int64(1); id <= 4; id++ {
+		createOutboxEvent(t, db, id)
+	}
+	for _, id := range []int64{1, 2, 4} {
+		require.NoError(t, repo.MarkDelivered(ctx, "audit", id))
+	}
+	for _, id := range []int64{1, 3, 4} {
+		require.NoError(t, repo.MarkDelivered(ctx, "analytics", id))
+	}
+
+	deleted, err := repo.DeleteDeliveredEvents(ctx, []string{"audit", "analytics"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	events, err := repo.GetUndeliveredEvents(ctx, "removed-sink", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, eventIDs(events))
+
+	var deliveries int64
+	require.NoError(t, db.Model(&domain.OutboxDelivery{}).Count(&deliveries).Error)
+	assert.Equal(t, int64(2), deliveries, "delivery records of deleted events are removed")
+}
@@ -0,0 +1,76 @@
+package repos
+
+import (
+	"context"
+	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/database"
+	"insider-message-system/pkg/errors"
+	"insider-message-system/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+// schedulerSettingsID is the primary key of the single row holding the settings.
+const schedulerSettingsID = 1
+
+// SchedulerSettings defines the interface for persisting runtime scheduler settings.
+type SchedulerSettings interface {
+	// Get returns the stored settings, or nil when none have been saved yet.
+	Get(ctx context.Context) (*domain.SchedulerSettings, error)
+	Save(ctx context.Context, settings domain.SchedulerSettings) error
+}
+
+type schedulerSettings struct {
+	db *database.DB
+}
+
+// NewSchedulerSettings creates a new scheduler settings repository with the given database connection.
+func NewSchedulerSettings(db *database.DB) SchedulerSettings {
+	return &schedulerSettings{db: db}
+}
+
+func (r *schedulerSettings) Get(ctx context.Context) (*domain.SchedulerSettings, error) {
+	ctx = database.WithOperation(ctx, "scheduler_settings.Get")
+
+	var settings []*domain.SchedulerSettings
+
+	result := r.db.WithContext(ctx).
+		Where("id = ?", schedulerSettingsID).
+		Limit(1).
+		Find(&settings)
+
+	if result.Error != nil {
+		logger.Error("Failed to get scheduler settings", zap.Error(result.Error))
+		return nil, errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to get scheduler settings", 500)
+	}
+
+	if len(settings) == 0 {
+		return nil, nil
+	}
+
+	return settings[0], nil
+}
+
+func (r *schedulerSettings) Save(ctx context.Context, settings domain.SchedulerSettings) error {
+	ctx = database.WithOperation(ctx, "scheduler_settings.Save")
+
+	settings.ID = schedulerSettingsID
+	settings.UpdatedAt = time.Now()
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"interval_ns", "batch_size", "max_retries", "retry_delay_ns", "updated_at"}),
+		}).
+		Create(&settings)
+
+	if result.Error != nil {
+		logger.Error("Failed to save scheduler settings", zap.Error(result.Error))
+		return errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to save scheduler settings", 500)
+	}
+
+	logger.Info("Scheduler settings saved successfully")
+	return nil
+}
@@ -0,0 +1,41 @@
+package repos
+
+import (
+	"context"
+	"insider-message-system/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerSettingsRepo_SaveAndGet(t *testing.T) {
+	repo := NewSchedulerSettings(setupTestDB(t))
+	ctx := context.Background()
+
+	stored, err := repo.Get(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+
+	require.NoError(t, repo.Save(ctx, domain.SchedulerSettings{
+		Interval:   time.Minute,
+		BatchSize:  10,
+		MaxRetries: 3,
+		RetryDelay: 5 * time.Second,
+	}))
+	require.NoError(t, repo.Save(ctx, domain.SchedulerSettings{
+		Interval:   30 * time.Second,
+		BatchSize:  20,
+		MaxRetries: 2,
+		RetryDelay: time.Second,
+	}))
+
+	stored, err = repo.Get(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, 30*time.Second, stored.Interval)
+	assert.Equal(t, 20, stored.BatchSize)
+	assert.Equal(t, 2, stored.MaxRetries)
+	assert.Equal(t, time.Second, stored.RetryDelay)
+}
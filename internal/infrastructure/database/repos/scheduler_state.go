@@ -0,0 +1,108 @@
+package repos
+
+import (
+	"context"
+	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/database"
+	"insider-message-system/pkg/errors"
+	"insider-message-system/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+// schedulerStateID is the primary key of the single row holding the desired state.
+const schedulerStateID = 1
+
+// SchedulerState defines the interface for persisting the scheduler's desired
+// running state.
+type SchedulerState interface {
+	// Get returns the stored state, or nil when none has been saved yet.
+	Get(ctx context.Context) (*domain.SchedulerDesiredState, error)
+	Save(ctx context.Context, state domain.SchedulerDesiredState) error
+	// Initialize stores state unless a state has already been saved, and returns
+	// the stored state either way, so that instances starting together agree on
+	// the initial state.
+	Initialize(ctx context.Context, state domain.SchedulerDesiredState) (*domain.SchedulerDesiredState, error)
+}
+
+type schedulerState struct {
+	db *database.DB
+}
+
+// NewSchedulerState creates a new scheduler state repository with the given database connection.
+func NewSchedulerState(db *database.DB) SchedulerState {
+	return &schedulerState{db: db}
+}
+
+func (r *schedulerState) Get(ctx context.Context) (*domain.SchedulerDesiredState, error) {
+	ctx = database.WithOperation(ctx, "scheduler_state.Get")
+
+	var states []*domain.SchedulerDesiredState
+
+	result := r.db.WithContext(ctx).
+		Where("id = ?", schedulerStateID).
+		Limit(1).
+		Find(&states)
+
+	if result.Error != nil {
+		logger.Error("Failed to get scheduler state", zap.Error(result.Error))
+		return nil, errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to get scheduler state", 500)
+	}
+
+	if len(states) == 0 {
+		return nil, nil
+	}
+
+	return states[0], nil
+}
+
+func (r *schedulerState) Save(ctx context.Context, state domain.SchedulerDesiredState) error {
+	ctx = database.WithOperation(ctx, "scheduler_state.Save")
+
+	state.ID = schedulerStateID
+	if state.ChangedAt.IsZero() {
+		state.ChangedAt = time.Now()
+	}
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"running", "changed_by", "reason", "changed_at"}),
+		}).
+		Create(&state)
+
+	if result.Error != nil {
+		logger.Error("Failed to save scheduler state", zap.Error(result.Error))
+		return errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to save scheduler state", 500)
+	}
+
+	logger.Info("Scheduler state saved successfully",
+		zap.Bool("running", state.Running),
+		zap.String("changed_by", state.ChangedBy))
+	return nil
+}
+
+func (r *schedulerState) Initialize(ctx context.Context, state domain.SchedulerDesiredState) (*domain.SchedulerDesiredState, error) {
+	ctx = database.WithOperation(ctx, "scheduler_state.Initialize")
+
+	state.ID = schedulerStateID
+	if state.ChangedAt.IsZero() {
+		state.ChangedAt = time.Now()
+	}
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoNothing: true,
+		}).
+		Create(&state)
+
+	if result.Error != nil {
+		logger.Error("Failed to initialize scheduler state", zap.Error(result.Error))
+		return nil, errors.WrapError(result.Error, "DATABASE_ERROR", "Failed to initialize scheduler state", 500)
+	}
+
+	return r.Get(ctx)
+}
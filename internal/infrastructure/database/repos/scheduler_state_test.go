@@ -0,0 +1,48 @@
+package repos
+
+import (
+	"context"
+	"insider-message-system/internal/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerStateRepo_SaveAndGet(t *testing.T) {
+	repo := NewSchedulerState(setupTestDB(t))
+	ctx := context.Background()
+
+	stored, err := repo.Get(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+
+	require.NoError(t, repo.Save(ctx, domain.SchedulerDesiredState{Running: true, ChangedBy: "config"}))
+	require.NoError(t, repo.Save(ctx, domain.SchedulerDesiredState{Running: false, ChangedBy: "ops", Reason: "maintenance"}))
+
+	stored, err = repo.Get(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.False(t, stored.Running)
+	assert.Equal(t, "ops", stored.ChangedBy)
+	assert.Equal(t, "maintenance", stored.Reason)
+	assert.False(t, stored.ChangedAt.IsZero())
+}
+
+func TestSchedulerStateRepo_InitializeKeepsSavedState(t *testing.T) {
+	repo := NewSchedulerState(setupTestDB(t))
+	ctx := context.Background()
+
+	stored, err := repo.Initialize(ctx, domain.SchedulerDesiredState{Running: true, ChangedBy: "config"})
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.True(t, stored.Running)
+
+	require.NoError(t, repo.Save(ctx, domain.SchedulerDesiredState{Running: false, ChangedBy: "ops"}))
+
+	stored, err = repo.Initialize(ctx, domain.SchedulerDesiredState{Running: true, ChangedBy: "config"})
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.False(t, stored.Running)
+	assert.Equal(t, "ops", stored.ChangedBy)
+}
@@ -0,0 +1,147 @@
+package election
+
+import (
+	"context"
+	"insider-message-system/pkg/logger"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Lock is a leadership lock shared by every instance. Implementations must
+// expire or drop the lock on their own when its holder dies, so that another
+// instance can take over.
+type Lock interface {
+	// Acquire takes the lock for id, or extends it if id already holds it, and
+	// returns the ID of the instance holding the lock afterwards. The holder is
+	// empty when the lock is free but could not be taken, or is held by an
+	// instance whose ID is unknown.
+	Acquire(ctx context.Context, id string) (string, error)
+	// Release gives up the lock if id holds it.
+	Release(ctx context.Context, id string) error
+	Close() error
+}
+
+// Elector campaigns for leadership on behalf of one instance.
+type Elector interface {
+	// Run campaigns until ctx is cancelled. Whenever this instance becomes the
+	// leader, lead is called with a context that is cancelled as soon as
+	// leadership is lost or ctx is cancelled; Run waits for lead to return before
+	// campaigning again and releases the lock when it returns.
+	Run(ctx context.Context, lead func(ctx context.Context))
+	// ID returns the ID this instance campaigns with.
+	ID() string
+	// Leader returns the ID of the current leader as last observed, or "" if it
+	// is not known.
+	Leader() string
+	IsLeader() bool
+}
+
+type elector struct {
+	lock          Lock
+	id            string
+	renewInterval time.Duration
+
+	mu     sync.RWMutex
+	leader string
+}
+
+// New creates an Elector that tries to take or renew the lock every
+// renewInterval. renewInterval must be well below the lock's TTL: a leader that
+// cannot renew in time steps down at its next attempt, before the lock expires
+// and another instance can take over.
+func New(lock Lock, id string, renewInterval time.Duration) Elector {
+	return &elector{
+		lock:          lock,
+		id:            id,
+		renewInterval: renewInterval,
+	}
+}
+
+func (e *elector) ID() string {
+	return e.id
+}
+
+func (e *elector) Leader() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader
+}
+
+func (e *elector) IsLeader() bool {
+	return e.Leader() == e.id
+}
+
+func (e *elector) setLeader(leader string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.leader = leader
+}
+
+func (e *elector) Run(ctx context.Context, lead func(ctx context.Context)) {
+	// stepDown is set while this instance is leading.
+	var stepDown func()
+
+	for {
+		holder, err := e.lock.Acquire(ctx, e.id)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Warn("Failed to acquire or renew leadership", zap.String("instance_id", e.id), zap.Error(err))
+			}
+			// The lock may already have expired; never keep leading on a failed renewal.
+			holder = ""
+		}
+		e.setLeader(holder)
+
+		switch {
+		case holder == e.id && stepDown == nil:
+			logger.Info("Elected leader", zap.String("instance_id", e.id))
+			stepDown = startLeading(ctx, lead)
+
+		case holder != e.id && stepDown != nil:
+			logger.Warn("Lost leadership", zap.String("instance_id", e.id), zap.String("leader", holder))
+			stepDown()
+			stepDown = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if stepDown != nil {
+				stepDown()
+			}
+			e.release()
+			return
+		case <-time.After(e.renewInterval):
+		}
+	}
+}
+
+// startLeading runs lead in the background and returns a function that cancels
+// it and waits for it to return.
+func startLeading(ctx context.Context, lead func(ctx context.Context)) func() {
+	leaderCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		lead(leaderCtx)
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// release hands the lock over right away instead of letting it expire, so that
+// another instance can take over without waiting for the TTL.
+func (e *elector) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := e.lock.Release(ctx, e.id); err != nil {
+		logger.Warn("Failed to release leadership", zap.String("instance_id", e.id), zap.Error(err))
+	}
+	e.setLeader("")
+}
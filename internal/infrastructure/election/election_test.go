@@ -0,0 +1,141 @@
+package election
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryLock is a Lock shared by electors in the same test.
+type memoryLock struct {
+	mu     sync.Mutex
+	holder string
+	fail   atomic.Bool
+}
+
+func (l *memoryLock) Acquire(ctx context.Context, id string) (string, error) {
+	if l.fail.Load() {
+		return "", errors.New("lock unavailable")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.holder == "" {
+		l.holder = id
+	}
+	return l.holder, nil
+}
+
+func (l *memoryLock) Release(ctx context.Context, id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.holder == id {
+		l.holder = ""
+	}
+	return nil
+}
+
+func (l *memoryLock) Close() error { return nil }
+
+func (l *memoryLock) expire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.holder = ""
+}
+
+// leadTracker records which electors are currently leading.
+type leadTracker struct {
+	leading sync.Map
+}
+
+func (tr *leadTracker) lead(id string) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		tr.leading.Store(id, true)
+		<-ctx.Done()
+		tr.leading.Delete(id)
+	}
+}
+
+func (tr *leadTracker) isLeading(id string) bool {
+	_, ok := tr.leading.Load(id)
+	return ok
+}
+
+func TestElector_OnlyOneLeaderAndFailover(t *testing.T) {
+	lock := &memoryLock{}
+	tracker := &leadTracker{}
+
+	first := New(lock, "first", time.Millisecond)
+	second := New(lock, "second", time.Millisecond)
+
+	firstCtx, stopFirst := context.WithCancel(context.Background())
+	firstDone := make(chan struct{})
+	go func() {
+		defer close(firstDone)
+		first.Run(firstCtx, tracker.lead("first"))
+	}()
+	assert.Eventually(t, func() bool { return tracker.isLeading("first") }, time.Second, time.Millisecond)
+
+	secondCtx, stopSecond := context.WithCancel(context.Background())
+	defer stopSecond()
+	go second.Run(secondCtx, tracker.lead("second"))
+
+	assert.Eventually(t, func() bool { return second.Leader() == "first" }, time.Second, time.Millisecond)
+	assert.True(t, first.IsLeader())
+	assert.False(t, second.IsLeader())
+	assert.False(t, tracker.isLeading("second"))
+
+	// Stopping the leader releases the lock and the other instance takes over.
+	stopFirst()
+	<-firstDone
+	assert.False(t, tracker.isLeading("first"))
+	assert.Empty(t, first.Leader())
+
+	assert.Eventually(t, func() bool { return tracker.isLeading("second") }, time.Second, time.Millisecond)
+	assert.Equal(t, "second", second.Leader())
+}
+
+func TestElector_StepsDownWhenLockIsLost(t *testing.T) {
+	lock := &memoryLock{}
+	tracker := &leadTracker{}
+
+	e := New(lock, "first", time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go e.Run(ctx, tracker.lead("first"))
+
+	assert.Eventually(t, func() bool { return tracker.isLeading("first") }, time.Second, time.Millisecond)
+
+	// Another instance took the lock after ours expired.
+	lock.expire()
+	lock.mu.Lock()
+	lock.holder = "other"
+	lock.mu.Unlock()
+
+	assert.Eventually(t, func() bool { return !tracker.isLeading("first") }, time.Second, time.Millisecond)
+	assert.Eventually(t, func() bool { return e.Leader() == "other" }, time.Second, time.Millisecond)
+}
+
+func TestElector_StepsDownWhenRenewalFails(t *testing.T) {
+	lock := &memoryLock{}
+	tracker := &leadTracker{}
+
+	e := New(lock, "first", time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go e.Run(ctx, tracker.lead("first"))
+
+	assert.Eventually(t, func() bool { return tracker.isLeading("first") }, time.Second, time.Millisecond)
+
+	lock.fail.Store(true)
+	assert.Eventually(t, func() bool { return !tracker.isLeading("first") }, time.Second, time.Millisecond)
+	assert.Empty(t, e.Leader())
+
+	lock.fail.Store(false)
+	assert.Eventually(t, func() bool { return tracker.isLeading("first") }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,161 @@
+package election
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+)
+
+// applicationNamePrefix marks the session holding the advisory lock, so every
+// instance can read the leader's ID from pg_stat_activity.
+const applicationNamePrefix = "insider-leader:"
+
+// MaxPostgresIDLength is the longest instance ID the Postgres lock accepts.
+// Postgres cuts application_name to 63 bytes, so a longer ID could not be read
+// back in full.
+const MaxPostgresIDLength = 63 - len(applicationNamePrefix)
+
+// ErrIDTooLong is returned by the Postgres lock for an ID longer than
+// MaxPostgresIDLength.
+var ErrIDTooLong = fmt.Errorf("instance ID must be at most %d bytes for the postgres leader lock", MaxPostgresIDLength)
+
+type postgresLock struct {
+	db  *sql.DB
+	key int64
+
+	// conn is the session the advisory lock is taken on. The lock lives exactly
+	// as long as the session, so it is dropped by Postgres when the leader dies
+	// or loses its connection. The session is never returned to db's pool, where
+	// it would keep the lock held.
+	mu   sync.Mutex
+	conn *sql.Conn
+	held bool
+}
+
+// NewPostgres creates a Lock backed by a session-level Postgres advisory lock
+// whose key is derived from name. It keeps one connection from db open.
+func NewPostgres(db *sql.DB, name string) Lock {
+	return &postgresLock{db: db, key: advisoryKey(name)}
+}
+
+// advisoryKey hashes name into 32 bits, so the lock shows up in pg_locks with
+// classid 0 and objid equal to the key.
+func advisoryKey(name string) int64 {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return int64(h.Sum32())
+}
+
+func (l *postgresLock) Acquire(ctx context.Context, id string) (string, error) {
+	if len(id) > MaxPostgresIDLength {
+		return "", ErrIDTooLong
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		conn, err := l.db.Conn(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to open leader lock session: %w", err)
+		}
+		if _, err := conn.ExecContext(ctx, "SELECT set_config('application_name', $1, false)", applicationNamePrefix+id); err != nil {
+			discardConn(conn)
+			return "", fmt.Errorf("failed to name leader lock session: %w", err)
+		}
+		l.conn = conn
+	}
+
+	if l.held {
+		// Advisory locks are re-entrant, so a leader only checks that its session,
+		// and with it the lock, is still alive.
+		if err := l.conn.PingContext(ctx); err != nil {
+			l.closeConn()
+			return "", fmt.Errorf("lost leader lock session: %w", err)
+		}
+		return id, nil
+	}
+
+	var acquired bool
+	if err := l.conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil {
+		l.closeConn()
+		return "", fmt.Errorf("failed to acquire leader lock: %w", err)
+	}
+	if acquired {
+		l.held = true
+		return id, nil
+	}
+
+	return l.holder(ctx)
+}
+
+func (l *postgresLock) holder(ctx context.Context) (string, error) {
+	var applicationName string
+	err := l.conn.QueryRowContext(ctx, `
+		SELECT a.application_name
+		FROM pg_locks l
+		JOIN pg_stat_activity a ON a.pid = l.pid
+		WHERE l.locktype = 'advisory' AND l.granted
+			AND l.classid = 0 AND l.objid::bigint = $1 AND l.objsubid = 1`, l.key).Scan(&applicationName)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up leader: %w", err)
+	}
+
+	return strings.TrimPrefix(applicationName, applicationNamePrefix), nil
+}
+
+func (l *postgresLock) Release(ctx context.Context, id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.held {
+		return nil
+	}
+
+	// The lock is released even when ctx is already cancelled, as it is when the
+	// elector steps down on shutdown.
+	l.held = false
+	if _, err := l.conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", l.key); err != nil {
+		// Ending the session drops the lock as well.
+		l.closeConn()
+		return fmt.Errorf("failed to release leader lock: %w", err)
+	}
+	return nil
+}
+
+func (l *postgresLock) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	return l.closeConn()
+}
+
+// closeConn ends the lock session, which drops the lock if it is still held. It
+// must be called with mu held.
+func (l *postgresLock) closeConn() error {
+	err := discardConn(l.conn)
+	l.conn = nil
+	l.held = false
+	return err
+}
+
+// discardConn closes conn's underlying connection instead of returning it to
+// the pool, so that the Postgres session ends and its advisory locks with it.
+func discardConn(conn *sql.Conn) error {
+	err := conn.Raw(func(any) error { return driver.ErrBadConn })
+	if errors.Is(err, driver.ErrBadConn) {
+		return nil
+	}
+	return err
+}
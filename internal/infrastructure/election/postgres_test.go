@@ -0,0 +1,233 @@
+package election
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePostgres models the part of Postgres the lock relies on: session-level
+// advisory locks that are held until they are unlocked or their session ends, and
+// each session's application_name.
+type fakePostgres struct {
+	mu       sync.Mutex
+	holders  map[int64]*fakeSession
+	sessions int
+}
+
+var fakeServers sync.Map
+var fakeServerCount atomic.Int32
+
+func init() {
+	sql.Register("fakepostgres", fakeDriver{})
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	server, _ := fakeServers.Load(name)
+	s := server.(*fakePostgres)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions++
+	return &fakeSession{server: s}, nil
+}
+
+// newFakePostgres returns a pool of connections to a new fake server.
+func newFakePostgres(t *testing.T) (*fakePostgres, *sql.DB) {
+	server := &fakePostgres{holders: map[int64]*fakeSession{}}
+	name := fmt.Sprintf("server-%d", fakeServerCount.Add(1))
+	fakeServers.Store(name, server)
+
+	db, err := sql.Open("fakepostgres", name)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return server, db
+}
+
+func (s *fakePostgres) holder(key int64) *fakeSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.holders[key]
+}
+
+type fakeSession struct {
+	server          *fakePostgres
+	applicationName string
+	// pingErr fails pings without ending the session, as a timeout does.
+	pingErr error
+}
+
+func (c *fakeSession) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *fakeSession) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+// Close ends the session, which releases its advisory locks.
+func (c *fakeSession) Close() error {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	for key, holder := range c.server.holders {
+		if holder == c {
+			delete(c.server.holders, key)
+		}
+	}
+	c.server.sessions--
+	return nil
+}
+
+func (c *fakeSession) Ping(ctx context.Context) error {
+	return c.pingErr
+}
+
+func (c *fakeSession) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+
+	switch {
+	case strings.Contains(query, "set_config('application_name'"):
+		c.applicationName = args[0].Value.(string)
+	case strings.Contains(query, "pg_advisory_unlock"):
+		key := args[0].Value.(int64)
+		if c.server.holders[key] == c {
+			delete(c.server.holders, key)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected query %q", query)
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeSession) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+
+	key := args[0].Value.(int64)
+	switch {
+	case strings.Contains(query, "pg_try_advisory_lock"):
+		holder, held := c.server.holders[key]
+		if !held {
+			c.server.holders[key] = c
+		}
+		return &fakeRows{columns: []string{"pg_try_advisory_lock"}, values: [][]driver.Value{{!held || holder == c}}}, nil
+	case strings.Contains(query, "pg_locks"):
+		holder, held := c.server.holders[key]
+		if !held {
+			return &fakeRows{columns: []string{"application_name"}}, nil
+		}
+		return &fakeRows{columns: []string{"application_name"}, values: [][]driver.Value{{holder.applicationName}}}, nil
+	default:
+		return nil, fmt.Errorf("unexpected query %q", query)
+	}
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func TestPostgresLock_AcquireAndRelease(t *testing.T) {
+	ctx := context.Background()
+	_, db := newFakePostgres(t)
+	first := NewPostgres(db, "insider:scheduler:leader")
+	second := NewPostgres(db, "insider:scheduler:leader")
+	defer first.Close()
+	defer second.Close()
+
+	holder, err := first.Acquire(ctx, "first")
+	require.NoError(t, err)
+	assert.Equal(t, "first", holder)
+
+	holder, err = second.Acquire(ctx, "second")
+	require.NoError(t, err)
+	assert.Equal(t, "first", holder)
+
+	// Release unlocks even when the leader's context is already cancelled.
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	require.NoError(t, first.Release(cancelled, "first"))
+
+	holder, err = second.Acquire(ctx, "second")
+	require.NoError(t, err)
+	assert.Equal(t, "second", holder)
+}
+
+func TestPostgresLock_LostSessionDropsLock(t *testing.T) {
+	ctx := context.Background()
+	server, db := newFakePostgres(t)
+	first := NewPostgres(db, "insider:scheduler:leader").(*postgresLock)
+	second := NewPostgres(db, "insider:scheduler:leader")
+	defer first.Close()
+	defer second.Close()
+
+	_, err := first.Acquire(ctx, "first")
+	require.NoError(t, err)
+
+	// A ping that times out leaves the session itself alive. The leader steps
+	// down, and ending its session must hand the lock over rather than leave it
+	// held by a connection sitting in the pool.
+	session := server.holder(first.key)
+	require.NotNil(t, session)
+	session.pingErr = context.DeadlineExceeded
+
+	_, err = first.Acquire(ctx, "first")
+	assert.Error(t, err)
+	assert.Nil(t, server.holder(first.key))
+
+	holder, err := second.Acquire(ctx, "second")
+	require.NoError(t, err)
+	assert.Equal(t, "second", holder)
+}
+
+func TestPostgresLock_CloseEndsSession(t *testing.T) {
+	ctx := context.Background()
+	server, db := newFakePostgres(t)
+	lock := NewPostgres(db, "insider:scheduler:leader")
+
+	_, err := lock.Acquire(ctx, "first")
+	require.NoError(t, err)
+	require.NoError(t, lock.Close())
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Empty(t, server.holders)
+	assert.Zero(t, server.sessions)
+}
+
+func TestPostgresLock_RejectsLongID(t *testing.T) {
+	_, db := newFakePostgres(t)
+	lock := NewPostgres(db, "insider:scheduler:leader")
+	defer lock.Close()
+
+	_, err := lock.Acquire(context.Background(), strings.Repeat("a", MaxPostgresIDLength+1))
+	assert.Equal(t, ErrIDTooLong, err)
+
+	holder, err := lock.Acquire(context.Background(), strings.Repeat("a", MaxPostgresIDLength))
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("a", MaxPostgresIDLength), holder)
+}
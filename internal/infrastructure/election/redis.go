@@ -0,0 +1,87 @@
+package election
+
+import (
+	"context"
+	"fmt"
+	"insider-message-system/pkg/config"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// acquireScript sets the key to the caller's ID if it is free, extends its TTL if
+// the caller already holds it, and returns the holder either way.
+var acquireScript = redis.NewScript(`
+local holder = redis.call("GET", KEYS[1])
+if not holder then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return ARGV[1]
+end
+if holder == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return holder
+`)
+
+// releaseScript deletes the key only if the caller still holds it.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type redisLocker interface {
+	redis.Scripter
+	Close() error
+}
+
+type redisLock struct {
+	client redisLocker
+	key    string
+	ttl    time.Duration
+}
+
+// NewRedis creates a Lock backed by a Redis key holding the leader's ID. The key
+// expires after ttl unless the leader renews it, so leadership moves to another
+// instance at most ttl after the leader dies.
+func NewRedis(cfg config.RedisConfig, key string, ttl time.Duration) (Lock, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return newRedisLock(client, key, ttl), nil
+}
+
+func newRedisLock(client redisLocker, key string, ttl time.Duration) Lock {
+	return &redisLock{client: client, key: key, ttl: ttl}
+}
+
+func (l *redisLock) Acquire(ctx context.Context, id string) (string, error) {
+	holder, err := acquireScript.Run(ctx, l.client, []string{l.key}, id, l.ttl.Milliseconds()).Text()
+	if err != nil {
+		return "", fmt.Errorf("failed to acquire leader lock %s: %w", l.key, err)
+	}
+	return holder, nil
+}
+
+func (l *redisLock) Release(ctx context.Context, id string) error {
+	if err := releaseScript.Run(ctx, l.client, []string{l.key}, id).Err(); err != nil {
+		return fmt.Errorf("failed to release leader lock %s: %w", l.key, err)
+	}
+	return nil
+}
+
+func (l *redisLock) Close() error {
+	return l.client.Close()
+}
@@ -0,0 +1,76 @@
+package election
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"insider-message-system/pkg/config"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisLock(t *testing.T, server *miniredis.Miniredis, ttl time.Duration) Lock {
+	lock, err := NewRedis(config.RedisConfig{Host: server.Host(), Port: server.Port()}, "insider:scheduler:leader", ttl)
+	require.NoError(t, err)
+	t.Cleanup(func() { lock.Close() })
+	return lock
+}
+
+func TestRedisLock_AcquireRenewAndRelease(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	first := newTestRedisLock(t, server, 10*time.Second)
+	second := newTestRedisLock(t, server, 10*time.Second)
+
+	holder, err := first.Acquire(ctx, "first")
+	require.NoError(t, err)
+	assert.Equal(t, "first", holder)
+
+	holder, err = second.Acquire(ctx, "second")
+	require.NoError(t, err)
+	assert.Equal(t, "first", holder)
+
+	// Renewing extends the TTL.
+	server.FastForward(8 * time.Second)
+	holder, err = first.Acquire(ctx, "first")
+	require.NoError(t, err)
+	assert.Equal(t, "first", holder)
+	assert.Equal(t, 10*time.Second, server.TTL("insider:scheduler:leader"))
+
+	// Only the holder can release the lock.
+	require.NoError(t, second.Release(ctx, "second"))
+	assert.True(t, server.Exists("insider:scheduler:leader"))
+
+	require.NoError(t, first.Release(ctx, "first"))
+	holder, err = second.Acquire(ctx, "second")
+	require.NoError(t, err)
+	assert.Equal(t, "second", holder)
+}
+
+func TestRedisLock_ExpiresWhenNotRenewed(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	first := newTestRedisLock(t, server, 10*time.Second)
+	second := newTestRedisLock(t, server, 10*time.Second)
+
+	_, err := first.Acquire(ctx, "first")
+	require.NoError(t, err)
+
+	server.FastForward(11 * time.Second)
+
+	holder, err := second.Acquire(ctx, "second")
+	require.NoError(t, err)
+	assert.Equal(t, "second", holder)
+}
+
+func TestNewRedis_Unreachable(t *testing.T) {
+	server := miniredis.RunT(t)
+	cfg := config.RedisConfig{Host: server.Host(), Port: server.Port()}
+	server.Close()
+
+	_, err := NewRedis(cfg, "insider:scheduler:leader", time.Second)
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/logger"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// Notifier delivers wake-up signals telling the scheduler that new work is available.
+// Signals carry no payload and are coalesced: any number of Notify calls made
+// before the subscriber reads the channel result in a single wake-up.
+type Notifier interface {
+	Notify(ctx context.Context) error
+	Subscribe() <-chan struct{}
+	Close() error
+}
+
+type local struct {
+	signals chan struct{}
+}
+
+// NewLocal creates an in-process Notifier for a single instance.
+func NewLocal() Notifier {
+	return &local{signals: make(chan struct{}, 1)}
+}
+
+func (n *local) Notify(ctx context.Context) error {
+	signal(n.signals)
+	return nil
+}
+
+func (n *local) Subscribe() <-chan struct{} {
+	return n.signals
+}
+
+func (n *local) Close() error {
+	return nil
+}
+
+// signal performs a non-blocking send so that pending wake-ups are coalesced.
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+type redisPublisher interface {
+	Publish(ctx context.Context, channel string, message any) *redis.IntCmd
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+	Close() error
+}
+
+type redisNotifier struct {
+	client  redisPublisher
+	channel string
+	signals chan struct{}
+	pubsub  *redis.PubSub
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// NewRedis creates a Notifier that broadcasts wake-ups over Redis pub/sub,
+// so that every instance subscribed to channel receives the signal.
+func NewRedis(cfg config.RedisConfig, channel string) (Notifier, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return newRedisNotifier(client, channel)
+}
+
+func newRedisNotifier(client redisPublisher, channel string) (Notifier, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	pubsub := client.Subscribe(ctx, channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		cancel()
+		pubsub.Close()
+		client.Close()
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", channel, err)
+	}
+
+	n := &redisNotifier{
+		client:  client,
+		channel: channel,
+		signals: make(chan struct{}, 1),
+		pubsub:  pubsub,
+		cancel:  cancel,
+	}
+
+	n.wg.Add(1)
+	go n.listen(ctx)
+
+	logger.Info("Redis wake-up notifier subscribed", zap.String("channel", channel))
+	return n, nil
+}
+
+func (n *redisNotifier) listen(ctx context.Context) {
+	defer n.wg.Done()
+
+	messages := n.pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-messages:
+			if !ok {
+				return
+			}
+			signal(n.signals)
+		}
+	}
+}
+
+func (n *redisNotifier) Notify(ctx context.Context) error {
+	if err := n.client.Publish(ctx, n.channel, "wake").Err(); err != nil {
+		// Still wake the local scheduler; other instances will pick the
+		// message up on their next tick.
+		signal(n.signals)
+		return fmt.Errorf("failed to publish wake-up: %w", err)
+	}
+	return nil
+}
+
+func (n *redisNotifier) Subscribe() <-chan struct{} {
+	return n.signals
+}
+
+func (n *redisNotifier) Close() error {
+	var err error
+	n.once.Do(func() {
+		n.cancel()
+		n.pubsub.Close()
+		n.wg.Wait()
+		err = n.client.Close()
+	})
+	return err
+}
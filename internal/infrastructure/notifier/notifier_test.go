@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"context"
+	"insider-message-system/pkg/config"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func received(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	case <-time.After(time.Second):
+		return false
+	}
+}
+
+func drained(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return false
+	case <-time.After(50 * time.Millisecond):
+		return true
+	}
+}
+
+func TestLocal_NotifyCoalesces(t *testing.T) {
+	n := NewLocal()
+
+	assert.NoError(t, n.Notify(context.Background()))
+	assert.NoError(t, n.Notify(context.Background()))
+	assert.NoError(t, n.Notify(context.Background()))
+
+	assert.True(t, received(n.Subscribe()))
+	assert.True(t, drained(n.Subscribe()))
+	assert.NoError(t, n.Close())
+}
+
+func newTestRedisConfig(t *testing.T) config.RedisConfig {
+	server := miniredis.RunT(t)
+	return config.RedisConfig{Host: server.Host(), Port: server.Port()}
+}
+
+func TestRedis_NotifyReachesEverySubscriber(t *testing.T) {
+	cfg := newTestRedisConfig(t)
+
+	first, err := NewRedis(cfg, "wakeup")
+	require.NoError(t, err)
+	defer first.Close()
+
+	second, err := NewRedis(cfg, "wakeup")
+	require.NoError(t, err)
+	defer second.Close()
+
+	require.NoError(t, first.Notify(context.Background()))
+
+	assert.True(t, received(first.Subscribe()))
+	assert.True(t, received(second.Subscribe()))
+}
+
+func TestRedis_ConnectionFailure(t *testing.T) {
+	_, err := NewRedis(config.RedisConfig{Host: "127.0.0.1", Port: "1"}, "wakeup")
+	assert.Error(t, err)
+}
+
+func TestRedis_CloseIsIdempotent(t *testing.T) {
+	n, err := NewRedis(newTestRedisConfig(t), "wakeup")
+	require.NoError(t, err)
+
+	assert.NoError(t, n.Close())
+	assert.NoError(t, n.Close())
+}
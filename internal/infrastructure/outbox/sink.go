@@ -0,0 +1,144 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/constants/enums/sinktypes"
+	httpClient "insider-message-system/pkg/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Sink defines the interface for a destination that outbox events are relayed to.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// NewSinks builds the sinks described by the given configuration.
+func NewSinks(cfgs []config.OutboxSinkConfig) ([]Sink, error) {
+	sinks := make([]Sink, 0, len(cfgs))
+	seen := make(map[string]bool, len(cfgs))
+
+	for _, cfg := range cfgs {
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("outbox sink name is required")
+		}
+		if seen[cfg.Name] {
+			return nil, fmt.Errorf("duplicate outbox sink name %q", cfg.Name)
+		}
+		seen[cfg.Name] = true
+
+		switch cfg.Type {
+		case sinktypes.HTTP:
+			if cfg.URL == "" {
+				return nil, fmt.Errorf("outbox sink %q: url is required", cfg.Name)
+			}
+			sinks = append(sinks, NewHTTPSink(cfg))
+		case sinktypes.File:
+			if cfg.Path == "" {
+				return nil, fmt.Errorf("outbox sink %q: path is required", cfg.Name)
+			}
+			sinks = append(sinks, NewFileSink(cfg.Name, cfg.Path))
+		default:
+			return nil, fmt.Errorf("outbox sink %q: unsupported type %q", cfg.Name, cfg.Type)
+		}
+	}
+
+	return sinks, nil
+}
+
+type httpSink struct {
+	name   string
+	url    string
+	client *resty.Client
+}
+
+// NewHTTPSink creates a Sink that POSTs each event as JSON to the configured URL.
+// Any non-2xx response is treated as a failed delivery.
+func NewHTTPSink(cfg config.OutboxSinkConfig) Sink {
+	clientConfig := httpClient.DefaultConfig()
+	if cfg.Timeout > 0 {
+		clientConfig.Timeout = cfg.Timeout
+	}
+	for key, value := range cfg.Headers {
+		clientConfig.DefaultHeaders[key] = value
+	}
+
+	return &httpSink{
+		name:   cfg.Name,
+		url:    cfg.URL,
+		client: httpClient.NewClient(clientConfig),
+	}
+}
+
+func (s *httpSink) Name() string {
+	return s.name
+}
+
+func (s *httpSink) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	resp, err := s.client.R().
+		SetContext(ctx).
+		SetHeader("X-Event-ID", strconv.FormatInt(event.ID, 10)).
+		SetHeader("X-Event-Type", string(event.EventType)).
+		SetBody(event).
+		Post(s.url)
+
+	if err != nil {
+		return fmt.Errorf("failed to publish event %d: %w", event.ID, err)
+	}
+
+	if !resp.IsSuccess() {
+		return fmt.Errorf("failed to publish event %d: sink responded with status %d", event.ID, resp.StatusCode())
+	}
+
+	return nil
+}
+
+type fileSink struct {
+	name string
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileSink creates a Sink that appends each event as a line of NDJSON to path.
+func NewFileSink(name, path string) Sink {
+	return &fileSink{name: name, path: path}
+}
+
+func (s *fileSink) Name() string {
+	return s.name
+}
+
+func (s *fileSink) Publish(ctx context.Context, event *domain.OutboxEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %d: %w", event.ID, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create sink directory: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open sink file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write event %d: %w", event.ID, err)
+	}
+
+	return file.Sync()
+}
@@ -0,0 +1,124 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/sinktypes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEvent(t *testing.T, id int64) *domain.OutboxEvent {
+	event, err := domain.NewOutboxEvent(domain.EventMessageSent, domain.MessageEventPayload{
+		MessageID: uuid.New(),
+		Status:    messagestatus.Sent,
+	})
+	require.NoError(t, err)
+	event.ID = id
+	return event
+}
+
+func TestNewSinks(t *testing.T) {
+	sinks, err := NewSinks([]config.OutboxSinkConfig{
+		{Name: "webhook", Type: sinktypes.HTTP, URL: "http://localhost/events"},
+		{Name: "audit", Type: sinktypes.File, Path: "/tmp/audit.ndjson"},
+	})
+	require.NoError(t, err)
+	require.Len(t, sinks, 2)
+	assert.Equal(t, "webhook", sinks[0].Name())
+	assert.Equal(t, "audit", sinks[1].Name())
+}
+
+func TestNewSinks_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfgs []config.OutboxSinkConfig
+	}{
+		{"missing name", []config.OutboxSinkConfig{{Type: sinktypes.File, Path: "x"}}},
+		{"duplicate name", []config.OutboxSinkConfig{
+			{Name: "a", Type: sinktypes.File, Path: "x"},
+			{Name: "a", Type: sinktypes.File, Path: "y"},
+		}},
+		{"http without url", []config.OutboxSinkConfig{{Name: "a", Type: sinktypes.HTTP}}},
+		{"file without path", []config.OutboxSinkConfig{{Name: "a", Type: sinktypes.File}}},
+		{"unknown type", []config.OutboxSinkConfig{{Name: "a", Type: "kafka"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSinks(tt.cfgs)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestHTTPSink_Publish(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "42", r.Header.Get("X-Event-ID"))
+		assert.Equal(t, "message.sent", r.Header.Get("X-Event-Type"))
+		assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(config.OutboxSinkConfig{
+		Name:    "webhook",
+		URL:     server.URL,
+		Headers: map[string]string{"X-Api-Key": "secret"},
+		Timeout: time.Second,
+	})
+
+	err := sink.Publish(context.Background(), newTestEvent(t, 42))
+	require.NoError(t, err)
+	assert.Equal(t, float64(42), received["id"])
+	assert.Equal(t, "message.sent", received["type"])
+	assert.NotNil(t, received["data"])
+}
+
+func TestHTTPSink_Publish_NonSuccessStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(config.OutboxSinkConfig{Name: "webhook", URL: server.URL})
+
+	err := sink.Publish(context.Background(), newTestEvent(t, 1))
+	assert.Error(t, err)
+}
+
+func TestFileSink_Publish(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events", "audit.ndjson")
+	sink := NewFileSink("audit", path)
+
+	require.NoError(t, sink.Publish(context.Background(), newTestEvent(t, 1)))
+	require.NoError(t, sink.Publish(context.Background(), newTestEvent(t, 2)))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var ids []int64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event domain.OutboxEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		ids = append(ids, event.ID)
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, []int64{1, 2}, ids)
+}
@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrLimitExceeded is returned by Wait when no permit becomes available within
+// the limiter's maximum wait.
+var ErrLimitExceeded = errors.New("rate limit exceeded")
+
+// Limiter hands out send permits from a token bucket that refills at a fixed rate
+// up to a burst size.
+type Limiter interface {
+	// Wait takes a permit, blocking while the next one becomes available within
+	// the limiter's maximum wait. It returns ErrLimitExceeded without waiting when
+	// that would take longer, and ctx.Err() if ctx is done while waiting.
+	Wait(ctx context.Context) error
+}
+
+// bucket takes a permit if one is available and otherwise returns how long until
+// the next one is.
+type bucket interface {
+	take(ctx context.Context) (time.Duration, error)
+}
+
+// wait polls b until it hands out a permit, giving up when the next permit would
+// come later than maxWait from the first attempt.
+func wait(ctx context.Context, b bucket, maxWait time.Duration) error {
+	deadline := time.Now().Add(maxWait)
+
+	for {
+		retryAfter, err := b.take(ctx)
+		if err != nil {
+			return err
+		}
+		if retryAfter <= 0 {
+			return nil
+		}
+		if time.Now().Add(retryAfter).After(deadline) {
+			return ErrLimitExceeded
+		}
+
+		timer := time.NewTimer(retryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+type localBucket struct {
+	rate  float64
+	burst float64
+
+	mu        sync.Mutex
+	tokens    float64
+	updatedAt time.Time
+	now       func() time.Time
+}
+
+func newLocalBucket(rate float64, burst int) *localBucket {
+	return &localBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+func (b *localBucket) take(ctx context.Context) (time.Duration, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if !b.updatedAt.IsZero() {
+		elapsed := now.Sub(b.updatedAt).Seconds()
+		b.tokens = math.Min(b.burst, b.tokens+math.Max(0, elapsed)*b.rate)
+	}
+	b.updatedAt = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, nil
+	}
+
+	return time.Duration(math.Ceil((1 - b.tokens) / b.rate * float64(time.Second))), nil
+}
+
+type local struct {
+	bucket  *localBucket
+	maxWait time.Duration
+}
+
+// NewLocal creates an in-process Limiter that allows rate permits per second with
+// bursts of up to burst permits. Each instance has its own bucket.
+func NewLocal(rate float64, burst int, maxWait time.Duration) Limiter {
+	return &local{bucket: newLocalBucket(rate, burst), maxWait: maxWait}
+}
+
+func (l *local) Wait(ctx context.Context) error {
+	return wait(ctx, l.bucket, l.maxWait)
+}
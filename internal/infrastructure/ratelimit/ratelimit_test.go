@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBucket_RefillsAtRate(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	b := newLocalBucket(2, 2)
+	b.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		retryAfter, err := b.take(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, retryAfter)
+	}
+
+	retryAfter, err := b.take(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	now = now.Add(500 * time.Millisecond)
+	retryAfter, err = b.take(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, retryAfter)
+
+	// Refilling never exceeds the burst.
+	now = now.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		retryAfter, _ = b.take(context.Background())
+		assert.Zero(t, retryAfter)
+	}
+	retryAfter, _ = b.take(context.Background())
+	assert.Positive(t, retryAfter)
+}
+
+func TestLocal_WaitsForNextPermit(t *testing.T) {
+	limiter := NewLocal(50, 1, time.Second)
+
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	start := time.Now()
+	require.NoError(t, limiter.Wait(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+}
+
+func TestLocal_DefersBeyondMaxWait(t *testing.T) {
+	limiter := NewLocal(1, 1, 100*time.Millisecond)
+
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	start := time.Now()
+	assert.ErrorIs(t, limiter.Wait(context.Background()), ErrLimitExceeded)
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestLocal_WaitCancelled(t *testing.T) {
+	limiter := NewLocal(1, 1, time.Minute)
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, limiter.Wait(ctx), context.DeadlineExceeded)
+}
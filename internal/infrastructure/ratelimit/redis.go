@@ -0,0 +1,142 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/logger"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+// takeScript refills the bucket stored at KEYS[1] from the Redis server clock, so
+// that every instance shares one clock, then takes a token if there is one. It
+// returns 0 when a token was taken and otherwise the milliseconds until the next
+// token is available.
+var takeScript = redis.NewScript(`
+redis.replicate_commands()
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated_at")
+local tokens = tonumber(state[1])
+local updated_at = tonumber(state[2])
+if tokens == nil or updated_at == nil then
+	tokens = burst
+	updated_at = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - updated_at) * rate / 1000)
+
+local retry_after = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+else
+	retry_after = math.ceil((1 - tokens) * 1000 / rate)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated_at", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return retry_after
+`)
+
+type redisScripter interface {
+	redis.Scripter
+	Close() error
+}
+
+type redisBucket struct {
+	client redisScripter
+	key    string
+	rate   float64
+	burst  int
+}
+
+func (b *redisBucket) take(ctx context.Context) (time.Duration, error) {
+	retryAfter, err := takeScript.Run(ctx, b.client, []string{b.key}, b.rate, b.burst).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(retryAfter) * time.Millisecond, nil
+}
+
+type distributed struct {
+	bucket   *redisBucket
+	fallback *localBucket
+	maxWait  time.Duration
+
+	// degraded is set while Redis is unreachable, so the switch to and from the
+	// fallback is logged once rather than on every permit.
+	degraded atomic.Bool
+}
+
+// Redis is a Limiter whose bucket lives in Redis and is shared by every instance
+// using the same key. It must be closed to release its connection.
+type Redis interface {
+	Limiter
+	Close() error
+}
+
+// NewRedis creates a Limiter that allows rate permits per second across every
+// instance sharing key. While Redis is unreachable, each instance falls back to a
+// local bucket of fallbackRate permits per second.
+func NewRedis(cfg config.RedisConfig, key string, rate float64, burst int, fallbackRate float64, maxWait time.Duration) (Redis, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return newDistributed(client, key, rate, burst, fallbackRate, maxWait), nil
+}
+
+func newDistributed(client redisScripter, key string, rate float64, burst int, fallbackRate float64, maxWait time.Duration) *distributed {
+	return &distributed{
+		bucket:   &redisBucket{client: client, key: key, rate: rate, burst: burst},
+		fallback: newLocalBucket(fallbackRate, burst),
+		maxWait:  maxWait,
+	}
+}
+
+func (l *distributed) Wait(ctx context.Context) error {
+	return wait(ctx, l, l.maxWait)
+}
+
+func (l *distributed) take(ctx context.Context) (time.Duration, error) {
+	retryAfter, err := l.bucket.take(ctx)
+	if err == nil {
+		if l.degraded.CompareAndSwap(true, false) {
+			logger.Info("Redis rate limiter recovered", zap.String("key", l.bucket.key))
+		}
+		return retryAfter, nil
+	}
+
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	if l.degraded.CompareAndSwap(false, true) {
+		logger.Warn("Redis rate limiter unavailable, using local fallback",
+			zap.String("key", l.bucket.key),
+			zap.Float64("fallback_rate", l.fallback.rate),
+			zap.Error(err))
+	}
+	return l.fallback.take(ctx)
+}
+
+func (l *distributed) Close() error {
+	return l.bucket.client.Close()
+}
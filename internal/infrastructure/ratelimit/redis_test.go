@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"insider-message-system/pkg/config"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedisLimiter(t *testing.T, server *miniredis.Miniredis, rate float64, burst int, fallbackRate float64) Redis {
+	cfg := config.RedisConfig{Host: server.Host(), Port: server.Port()}
+	limiter, err := NewRedis(cfg, "insider:webhook:rate", rate, burst, fallbackRate, 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { limiter.Close() })
+	return limiter
+}
+
+func TestRedis_BucketIsSharedAcrossInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	server.SetTime(now)
+
+	first := newTestRedisLimiter(t, server, 1, 2, 1)
+	second := newTestRedisLimiter(t, server, 1, 2, 1)
+
+	require.NoError(t, first.Wait(context.Background()))
+	require.NoError(t, second.Wait(context.Background()))
+	assert.ErrorIs(t, first.Wait(context.Background()), ErrLimitExceeded)
+	assert.ErrorIs(t, second.Wait(context.Background()), ErrLimitExceeded)
+
+	server.SetTime(now.Add(time.Second))
+	require.NoError(t, second.Wait(context.Background()))
+	assert.ErrorIs(t, first.Wait(context.Background()), ErrLimitExceeded)
+}
+
+func TestRedis_ReportsTimeUntilNextPermit(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.SetTime(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+
+	limiter := newTestRedisLimiter(t, server, 4, 1, 4).(*distributed)
+
+	retryAfter, err := limiter.take(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, retryAfter)
+
+	retryAfter, err = limiter.take(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 250*time.Millisecond, retryAfter)
+}
+
+func TestRedis_FallsBackToLocalBucket(t *testing.T) {
+	server := miniredis.RunT(t)
+	limiter := newTestRedisLimiter(t, server, 100, 1, 1)
+
+	server.Close()
+
+	// The local fallback allows its own burst, then throttles at fallbackRate.
+	require.NoError(t, limiter.Wait(context.Background()))
+	assert.ErrorIs(t, limiter.Wait(context.Background()), ErrLimitExceeded)
+	assert.True(t, limiter.(*distributed).degraded.Load())
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"fmt"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/constants/enums/authstrategies"
+	"insider-message-system/pkg/errors"
+	httppkg "insider-message-system/pkg/http"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultAuthHeader        = "x-ins-auth-key"
+	defaultTokenRefreshAhead = time.Minute
+)
+
+// newAuthStrategy builds the strategy that authenticates requests to the provider
+// at cfg.URL. Without a type, cfg.AuthKey is sent in the x-ins-auth-key header. An
+// OAuth2 token endpoint is reached with the provider's transport settings.
+func newAuthStrategy(cfg config.WebhookConfig, transport transportConfig) (httppkg.AuthStrategy, error) {
+	auth := cfg.Auth
+	if auth.Type == "" {
+		auth.Type = authstrategies.Header
+	}
+
+	var problems []string
+	switch auth.Type {
+	case authstrategies.Header:
+		header := auth.Header
+		if header == "" {
+			header = defaultAuthHeader
+		}
+		return httppkg.NewHeaderAuth(header, cfg.AuthKey), nil
+	case authstrategies.Basic:
+		if auth.Username == "" {
+			problems = append(problems, "auth.username is required for basic auth")
+		}
+	case authstrategies.OAuth2:
+		if auth.TokenURL == "" {
+			problems = append(problems, "auth.token_url is required for oauth2 auth")
+		}
+		if auth.ClientID == "" {
+			problems = append(problems, "auth.client_id is required for oauth2 auth")
+		}
+		if auth.RefreshBefore < 0 {
+			problems = append(problems, "auth.refresh_before must not be negative")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("auth.type must be %q, %q or %q", authstrategies.Header, authstrategies.Basic, authstrategies.OAuth2))
+	}
+	if len(problems) > 0 {
+		return nil, errors.NewErrorWithDetails("INVALID_WEBHOOK_CONFIG", "Invalid webhook configuration", strings.Join(problems, "; "), http.StatusInternalServerError)
+	}
+
+	if auth.Type == authstrategies.Basic {
+		return httppkg.NewBasicAuth(auth.Username, auth.Password), nil
+	}
+
+	refreshBefore := auth.RefreshBefore
+	if refreshBefore == 0 {
+		refreshBefore = defaultTokenRefreshAhead
+	}
+	tokenClientConfig := httppkg.DefaultConfig()
+	tokenClientConfig.Timeout = cfg.Timeout
+	transport.apply(tokenClientConfig)
+	return httppkg.NewOAuth2ClientCredentials(httppkg.OAuth2Config{
+		TokenURL:      auth.TokenURL,
+		ClientID:      auth.ClientID,
+		ClientSecret:  auth.ClientSecret,
+		Scopes:        auth.Scopes,
+		RefreshBefore: refreshBefore,
+	}, tokenClientConfig), nil
+}
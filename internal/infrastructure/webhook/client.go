@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/ratelimit"
 	"insider-message-system/pkg/circuitbreaker"
 	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
 	"insider-message-system/pkg/errors"
 	httppkg "insider-message-system/pkg/http"
 	"insider-message-system/pkg/logger"
+	"insider-message-system/pkg/signature"
 	"net/http"
 	"time"
 
@@ -29,7 +32,7 @@ type restyRequester interface {
 type restyRequest interface {
 	SetContext(ctx context.Context) restyRequest
 	SetBody(body any) restyRequest
-	SetResult(result any) restyRequest
+	SetHeaders(headers map[string]string) restyRequest
 	Post(url string) (*resty.Response, error)
 	Get(url string) (*resty.Response, error)
 }
@@ -56,8 +59,8 @@ func (r *realRestyRequest) SetBody(body any) restyRequest {
 	return r
 }
 
-func (r *realRestyRequest) SetResult(result any) restyRequest {
-	r.req = r.req.SetResult(result)
+func (r *realRestyRequest) SetHeaders(headers map[string]string) restyRequest {
+	r.req = r.req.SetHeaders(headers)
 	return r
 }
 
@@ -79,9 +82,45 @@ type client struct {
 	client         restyRequester
 	config         config.WebhookConfig
 	circuitBreaker CircuitBreaker
+	rateLimiter    ratelimit.Limiter
+	signer         *signature.Signer
+	mapping        *mapping
+	auth           httppkg.AuthStrategy
 }
 
-func NewClient(cfg config.WebhookConfig, cbConfig config.CircuitBreakerConfig) Client {
+func NewClient(cfg config.WebhookConfig, cbConfig config.CircuitBreakerConfig) (Client, error) {
+	return NewClientWithRateLimiter(cfg, cbConfig, nil)
+}
+
+// NewClientWithRateLimiter creates a Client that takes a permit from limiter
+// before every send. A nil limiter sends without limit. It fails when signing is
+// enabled without a secret, or the payload mapping, authentication, TLS files or
+// proxy URL are invalid.
+func NewClientWithRateLimiter(cfg config.WebhookConfig, cbConfig config.CircuitBreakerConfig, limiter ratelimit.Limiter) (Client, error) {
+	payloadMapping, err := newMapping(cfg.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := newTransportConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := newAuthStrategy(cfg, transport)
+	if err != nil {
+		return nil, err
+	}
+
+	var signer *signature.Signer
+	if cfg.Signing.Enabled {
+		signer, err = signature.NewSigner(cfg.Signing.Secrets)
+		if err != nil {
+			return nil, errors.NewErrorWithDetails("INVALID_WEBHOOK_CONFIG", "Invalid webhook configuration", "webhook.signing.secrets must contain at least one secret", http.StatusInternalServerError)
+		}
+		logger.Info("Webhook request signing enabled", zap.Int("secrets", len(cfg.Signing.Secrets)))
+	}
+
 	clientConfig := &httppkg.ClientConfig{
 		Timeout:          cfg.Timeout,
 		RetryCount:       3,
@@ -90,12 +129,15 @@ func NewClient(cfg config.WebhookConfig, cbConfig config.CircuitBreakerConfig) C
 		DefaultHeaders: map[string]string{
 			"Content-Type": "application/json",
 		},
+		// Only transient failures are retried in place. Throttled sends are retried
+		// by a later cycle once the provider's Retry-After has passed.
 		RetryCondition: func(r *resty.Response, err error) bool {
-			return err != nil || r.StatusCode() >= 500
+			return err != nil || (r.StatusCode() >= 300 && categoryForStatus(r.StatusCode()) == webhookfailures.Transient)
 		},
 	}
+	transport.apply(clientConfig)
 
-	retryClient := httppkg.NewClientWithCustomAuth("x-ins-auth-key", cfg.AuthKey, clientConfig)
+	retryClient := httppkg.NewClient(clientConfig)
 
 	var cb CircuitBreaker
 	if cbConfig.Enabled {
@@ -113,7 +155,11 @@ func NewClient(cfg config.WebhookConfig, cbConfig config.CircuitBreakerConfig) C
 		client:         &realRestyRequester{client: retryClient},
 		config:         cfg,
 		circuitBreaker: cb,
-	}
+		rateLimiter:    limiter,
+		signer:         signer,
+		mapping:        payloadMapping,
+		auth:           auth,
+	}, nil
 }
 
 func (w *client) SendMessage(ctx context.Context, request domain.WebhookRequest) (*domain.MessageResponse, error) {
@@ -122,16 +168,39 @@ func (w *client) SendMessage(ctx context.Context, request domain.WebhookRequest)
 		zap.String("to", request.To),
 		zap.String("content", request.Content))
 
+	// An open breaker rejects the request anyway, so it is turned away before it
+	// takes a send token that another request could have used.
+	if w.circuitBreaker != nil && w.circuitBreaker.GetState() == circuitbreaker.StateOpen {
+		logger.Warn("Circuit breaker is open, webhook request rejected",
+			zap.String("url", w.config.URL),
+			zap.String("to", request.To))
+		return nil, errors.ErrWebhookCircuitOpen
+	}
+
+	if w.rateLimiter != nil {
+		if err := w.rateLimiter.Wait(ctx); err != nil {
+			logger.Debug("Webhook send rate limit reached, deferring message",
+				zap.String("to", request.To),
+				zap.Error(err))
+			return nil, errors.ErrWebhookThrottled
+		}
+	}
+
 	if w.circuitBreaker == nil {
 		return w.sendMessageDirect(ctx, request)
 	}
 
 	var response *domain.MessageResponse
-	var err error
-
-	err = w.circuitBreaker.Execute(ctx, func() error {
-		response, err = w.sendMessageDirect(ctx, request)
-		return err
+	var sendErr error
+
+	// Only transient failures count against the breaker. A permanent or throttled
+	// failure is an answer from a provider that is up.
+	err := w.circuitBreaker.Execute(ctx, func() error {
+		response, sendErr = w.sendMessageDirect(ctx, request)
+		if sendErr != nil && Category(sendErr) == webhookfailures.Transient {
+			return sendErr
+		}
+		return nil
 	})
 
 	if err != nil {
@@ -139,53 +208,85 @@ func (w *client) SendMessage(ctx context.Context, request domain.WebhookRequest)
 			logger.Warn("Circuit breaker is open, webhook request rejected",
 				zap.String("url", w.config.URL),
 				zap.String("to", request.To))
-			return nil, errors.NewErrorWithDetails(
-				"WEBHOOK_CIRCUIT_OPEN",
-				"Webhook service is temporarily unavailable",
-				"Circuit breaker is open due to repeated failures",
-				http.StatusServiceUnavailable,
-			)
+			return nil, errors.ErrWebhookCircuitOpen
 		}
 		if err == circuitbreaker.ErrCircuitHalfOpen {
 			logger.Warn("Circuit breaker is half-open, webhook request rejected",
 				zap.String("url", w.config.URL),
 				zap.String("to", request.To))
-			return nil, errors.NewErrorWithDetails(
-				"WEBHOOK_CIRCUIT_HALF_OPEN",
-				"Webhook service is testing recovery",
-				"Circuit breaker is in half-open state",
-				http.StatusServiceUnavailable,
-			)
+			return nil, errors.ErrWebhookCircuitHalfOpen
 		}
 		return nil, err
 	}
+	if sendErr != nil {
+		return nil, sendErr
+	}
 
 	return response, nil
 }
 
 func (w *client) sendMessageDirect(ctx context.Context, request domain.WebhookRequest) (*domain.MessageResponse, error) {
-	var response domain.MessageResponse
-	resp, err := w.client.R().
-		SetContext(ctx).
-		SetBody(request).
-		SetResult(&response).
-		Post(w.config.URL)
+	// The body is encoded here rather than by resty, in the provider's format, and
+	// the signature covers the exact bytes sent.
+	body, err := w.mapping.encode(request)
+	if err != nil {
+		return nil, &Error{
+			Category: webhookfailures.Permanent,
+			Err:      errors.WrapError(err, "WEBHOOK_ERROR", "Failed to encode request", http.StatusInternalServerError),
+		}
+	}
+
+	headers := map[string]string{"Content-Type": w.mapping.contentType}
+	if w.signer != nil {
+		for key, value := range w.signer.Headers(body) {
+			headers[key] = value
+		}
+	}
+
+	if w.auth != nil {
+		if err := w.auth.Authorize(ctx, headers); err != nil {
+			logger.Error("Failed to authorize webhook request", zap.Error(err))
+			return nil, newAuthError(err)
+		}
+	}
 
+	resp, err := w.post(ctx, body, headers)
 	if err != nil {
 		logger.Error("Failed to send webhook request", zap.Error(err))
-		return nil, errors.WrapError(err, "WEBHOOK_ERROR", "Failed to send request", http.StatusInternalServerError)
+		return nil, newRequestError(err)
+	}
+
+	// A rejected token may have been revoked before it expired: renew it and
+	// send once more.
+	if refreshable, ok := w.auth.(httppkg.RefreshableAuth); ok && resp.StatusCode() == http.StatusUnauthorized {
+		logger.Warn("Webhook provider rejected credentials, refreshing", zap.String("url", w.config.URL))
+		if err := refreshable.Refresh(ctx, headers); err != nil {
+			logger.Error("Failed to refresh webhook credentials", zap.Error(err))
+			return nil, newAuthError(err)
+		}
+		resp, err = w.post(ctx, body, headers)
+		if err != nil {
+			logger.Error("Failed to send webhook request", zap.Error(err))
+			return nil, newRequestError(err)
+		}
 	}
 
 	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		webhookErr := newResponseError(resp.StatusCode(), resp.Header(), resp.String(), time.Now())
 		logger.Error("Webhook request failed",
+			zap.Int("status_code", resp.StatusCode()),
+			zap.String("category", webhookErr.Category.String()),
+			zap.Duration("retry_after", webhookErr.RetryAfter),
+			zap.String("response", resp.String()))
+		return nil, webhookErr
+	}
+
+	response, err := w.mapping.decode(resp.Header(), resp.Body())
+	if err != nil {
+		logger.Error("Webhook response rejected the message",
 			zap.Int("status_code", resp.StatusCode()),
 			zap.String("response", resp.String()))
-		return nil, errors.NewErrorWithDetails(
-			"WEBHOOK_ERROR",
-			fmt.Sprintf("Webhook request failed with status %d", resp.StatusCode()),
-			resp.String(),
-			http.StatusInternalServerError,
-		)
+		return nil, err
 	}
 
 	logger.Info("Webhook request successful",
@@ -193,7 +294,15 @@ func (w *client) sendMessageDirect(ctx context.Context, request domain.WebhookRe
 		zap.String("message_id", response.MessageID),
 		zap.Int("status_code", resp.StatusCode()))
 
-	return &response, nil
+	return response, nil
+}
+
+func (w *client) post(ctx context.Context, body []byte, headers map[string]string) (*resty.Response, error) {
+	return w.client.R().
+		SetContext(ctx).
+		SetBody(body).
+		SetHeaders(headers).
+		Post(w.config.URL)
 }
 
 func (w *client) GetCircuitBreakerMetrics() map[string]any {
@@ -214,9 +323,16 @@ func (w *client) GetCircuitBreakerState() circuitbreaker.State {
 }
 
 func (w *client) HealthCheck(ctx context.Context) error {
-	resp, err := w.client.R().
-		SetContext(ctx).
-		Get(w.config.URL)
+	req := w.client.R().SetContext(ctx)
+	if w.auth != nil {
+		headers := map[string]string{}
+		if err := w.auth.Authorize(ctx, headers); err != nil {
+			return err
+		}
+		req = req.SetHeaders(headers)
+	}
+
+	resp, err := req.Get(w.config.URL)
 
 	if err != nil {
 		return err
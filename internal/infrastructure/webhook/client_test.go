@@ -2,11 +2,18 @@ package webhook
 
 import (
 	"context"
+	"crypto/tls"
 	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/ratelimit"
 	"insider-message-system/pkg/circuitbreaker"
 	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
+	"insider-message-system/pkg/errors"
+	"insider-message-system/pkg/signature"
 	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
 	"fmt"
 
@@ -38,8 +45,8 @@ func (m *mockRestyRequest) SetBody(body any) restyRequest {
 	return args.Get(0).(restyRequest)
 }
 
-func (m *mockRestyRequest) SetResult(result any) restyRequest {
-	args := m.Called(result)
+func (m *mockRestyRequest) SetHeaders(headers map[string]string) restyRequest {
+	args := m.Called(headers)
 	return args.Get(0).(restyRequest)
 }
 
@@ -74,7 +81,7 @@ func TestSendMessage_Success(t *testing.T) {
 	mockClient.On("R").Return(mockReq)
 	mockReq.On("SetContext", mock.Anything).Return(mockReq)
 	mockReq.On("SetBody", mock.Anything).Return(mockReq)
-	mockReq.On("SetResult", mock.Anything).Return(mockReq)
+	mockReq.On("SetHeaders", mock.Anything).Return(mockReq)
 
 	resp := &resty.Response{}
 	resp.RawResponse = &http.Response{StatusCode: 200, Status: "200 OK"}
@@ -82,8 +89,9 @@ func TestSendMessage_Success(t *testing.T) {
 	mockReq.On("Post", "http://webhook").Return(resp, nil)
 
 	c := &client{
-		client: mockClient,
-		config: config.WebhookConfig{URL: "http://webhook"},
+		client:  mockClient,
+		config:  config.WebhookConfig{URL: "http://webhook"},
+		mapping: defaultMapping(),
 	}
 
 	response, err := c.sendMessageDirect(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
@@ -98,14 +106,15 @@ func TestSendMessage_HTTPError(t *testing.T) {
 	mockClient.On("R").Return(mockReq)
 	mockReq.On("SetContext", mock.Anything).Return(mockReq)
 	mockReq.On("SetBody", mock.Anything).Return(mockReq)
-	mockReq.On("SetResult", mock.Anything).Return(mockReq)
+	mockReq.On("SetHeaders", mock.Anything).Return(mockReq)
 	resp := &resty.Response{}
 	resp.RawResponse = &http.Response{StatusCode: 200, Status: "200 OK"}
 	mockReq.On("Post", "http://webhook").Return(resp, fmt.Errorf("http fail"))
 
 	c := &client{
-		client: mockClient,
-		config: config.WebhookConfig{URL: "http://webhook"},
+		client:  mockClient,
+		config:  config.WebhookConfig{URL: "http://webhook"},
+		mapping: defaultMapping(),
 	}
 
 	response, err := c.sendMessageDirect(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
@@ -120,15 +129,16 @@ func TestSendMessage_Non2xx(t *testing.T) {
 	mockClient.On("R").Return(mockReq)
 	mockReq.On("SetContext", mock.Anything).Return(mockReq)
 	mockReq.On("SetBody", mock.Anything).Return(mockReq)
-	mockReq.On("SetResult", mock.Anything).Return(mockReq)
+	mockReq.On("SetHeaders", mock.Anything).Return(mockReq)
 	resp := &resty.Response{}
 	resp.RawResponse = &http.Response{StatusCode: 500, Status: "500 Internal Server Error"}
 	resp.SetBody([]byte("fail"))
 	mockReq.On("Post", "http://webhook").Return(resp, nil)
 
 	c := &client{
-		client: mockClient,
-		config: config.WebhookConfig{URL: "http://webhook"},
+		client:  mockClient,
+		config:  config.WebhookConfig{URL: "http://webhook"},
+		mapping: defaultMapping(),
 	}
 
 	response, err := c.sendMessageDirect(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
@@ -136,6 +146,154 @@ func TestSendMessage_Non2xx(t *testing.T) {
 	assert.Nil(t, response)
 }
 
+func TestSendMessage_SignsBody(t *testing.T) {
+	mockClient := new(mockRestyClient)
+	mockReq := new(mockRestyRequest)
+
+	var body []byte
+	var headers map[string]string
+	mockClient.On("R").Return(mockReq)
+	mockReq.On("SetContext", mock.Anything).Return(mockReq)
+	mockReq.On("SetBody", mock.Anything).Run(func(args mock.Arguments) { body = args.Get(0).([]byte) }).Return(mockReq)
+	mockReq.On("SetHeaders", mock.Anything).Run(func(args mock.Arguments) { headers = args.Get(0).(map[string]string) }).Return(mockReq)
+
+	resp := &resty.Response{}
+	resp.RawResponse = &http.Response{StatusCode: 202, Status: "202 Accepted"}
+	mockReq.On("Post", "http://webhook").Return(resp, nil)
+
+	c, err := NewClient(config.WebhookConfig{
+		URL:     "http://webhook",
+		Signing: config.SigningConfig{Enabled: true, Secrets: []string{"new-secret", "old-secret"}},
+	}, config.CircuitBreakerConfig{})
+	assert.NoError(t, err)
+	c.(*client).client = mockClient
+
+	_, err = c.SendMessage(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"to":"+123","content":"hi"}`, string(body))
+
+	// A receiver that only knows either secret accepts the request.
+	for _, secret := range []string{"old-secret", "new-secret"} {
+		verifier, err := signature.NewVerifier([]string{secret}, 0)
+		assert.NoError(t, err)
+		assert.NoError(t, verifier.Verify(headers[signature.TimestampHeader], headers[signature.SignatureHeader], body))
+	}
+}
+
+func TestSendMessage_UsesProviderMapping(t *testing.T) {
+	mockClient := new(mockRestyClient)
+	mockReq := new(mockRestyRequest)
+
+	var body []byte
+	var headers map[string]string
+	mockClient.On("R").Return(mockReq)
+	mockReq.On("SetContext", mock.Anything).Return(mockReq)
+	mockReq.On("SetBody", mock.Anything).Run(func(args mock.Arguments) { body = args.Get(0).([]byte) }).Return(mockReq)
+	mockReq.On("SetHeaders", mock.Anything).Run(func(args mock.Arguments) { headers = args.Get(0).(map[string]string) }).Return(mockReq)
+
+	resp := &resty.Response{}
+	resp.RawResponse = &http.Response{StatusCode: 201, Header: http.Header{"X-Message-Id": []string{"SM123"}}}
+	resp.SetBody([]byte(`{"status":"queued"}`))
+	mockReq.On("Post", "http://webhook").Return(resp, nil)
+
+	c, err := NewClient(config.WebhookConfig{
+		URL: "http://webhook",
+		Mapping: config.MappingConfig{
+			Request: config.RequestMappingConfig{
+				ContentType: "application/x-www-form-urlencoded",
+				Fields: []config.FieldMappingConfig{
+					{Path: "To", Value: "{{.To}}"},
+					{Path: "Body", Value: "{{.Content}}"},
+				},
+			},
+			Response: config.ResponseMappingConfig{MessageIDHeader: "X-Message-Id", Status: "status"},
+		},
+	}, config.CircuitBreakerConfig{})
+	assert.NoError(t, err)
+	c.(*client).client = mockClient
+
+	response, err := c.SendMessage(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Body=hi&To=%2B123", string(body))
+	assert.Equal(t, "application/x-www-form-urlencoded", headers["Content-Type"])
+	assert.Equal(t, &domain.MessageResponse{Message: "queued", MessageID: "SM123"}, response)
+}
+
+func TestNewClient_RejectsInvalidMapping(t *testing.T) {
+	_, err := NewClient(config.WebhookConfig{
+		Mapping: config.MappingConfig{Request: config.RequestMappingConfig{ContentType: "text/xml"}},
+	}, config.CircuitBreakerConfig{})
+
+	var customErr *errors.Error
+	assert.ErrorAs(t, err, &customErr)
+	assert.Equal(t, "INVALID_WEBHOOK_CONFIG", customErr.Code)
+}
+
+func TestNewClient_SigningRequiresSecret(t *testing.T) {
+	_, err := NewClient(config.WebhookConfig{Signing: config.SigningConfig{Enabled: true}}, config.CircuitBreakerConfig{})
+
+	var customErr *errors.Error
+	assert.ErrorAs(t, err, &customErr)
+	assert.Equal(t, "INVALID_WEBHOOK_CONFIG", customErr.Code)
+}
+
+func newStatusRequester(statusCode int) *mockRestyClient {
+	mockClient := new(mockRestyClient)
+	mockReq := new(mockRestyRequest)
+
+	mockClient.On("R").Return(mockReq)
+	mockReq.On("SetContext", mock.Anything).Return(mockReq)
+	mockReq.On("SetBody", mock.Anything).Return(mockReq)
+	mockReq.On("SetHeaders", mock.Anything).Return(mockReq)
+	resp := &resty.Response{}
+	resp.RawResponse = &http.Response{StatusCode: statusCode, Header: http.Header{"Retry-After": []string{"5"}}}
+	mockReq.On("Post", "http://webhook").Return(resp, nil)
+	return mockClient
+}
+
+func TestSendMessage_OnlyTransientFailuresTripCircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		category   webhookfailures.WebhookFailure
+		state      circuitbreaker.State
+	}{
+		{name: "permanent", statusCode: 400, category: webhookfailures.Permanent, state: circuitbreaker.StateClosed},
+		{name: "throttled", statusCode: 429, category: webhookfailures.Throttled, state: circuitbreaker.StateClosed},
+		{name: "transient", statusCode: 503, category: webhookfailures.Transient, state: circuitbreaker.StateOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &client{
+				client:         newStatusRequester(tt.statusCode),
+				config:         config.WebhookConfig{URL: "http://webhook"},
+				mapping:        defaultMapping(),
+				circuitBreaker: circuitbreaker.New(circuitbreaker.Config{FailureThreshold: 1}),
+			}
+
+			_, err := c.SendMessage(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
+			assert.Equal(t, tt.category, Category(err))
+			assert.Equal(t, tt.state, c.GetCircuitBreakerState())
+		})
+	}
+}
+
+func TestSendMessage_ThrottledCarriesRetryAfter(t *testing.T) {
+	c := &client{
+		client:  newStatusRequester(http.StatusTooManyRequests),
+		config:  config.WebhookConfig{URL: "http://webhook"},
+		mapping: defaultMapping(),
+	}
+
+	_, err := c.SendMessage(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
+
+	var webhookErr *Error
+	assert.ErrorAs(t, err, &webhookErr)
+	assert.Equal(t, webhookfailures.Throttled, webhookErr.Category)
+	assert.Equal(t, 5*time.Second, webhookErr.RetryAfter)
+}
+
 // Circuit breaker test double
 
 type cbTestDouble struct {
@@ -153,6 +311,7 @@ func TestSendMessage_CBOpen(t *testing.T) {
 	c := &client{
 		client:         new(mockRestyClient),
 		config:         config.WebhookConfig{URL: "http://webhook"},
+		mapping:        defaultMapping(),
 		circuitBreaker: &cbTestDouble{execErr: circuitbreaker.ErrCircuitOpen, state: circuitbreaker.StateOpen},
 	}
 	resp, err := c.SendMessage(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
@@ -165,6 +324,7 @@ func TestSendMessage_CBHalfOpen(t *testing.T) {
 	c := &client{
 		client:         new(mockRestyClient),
 		config:         config.WebhookConfig{URL: "http://webhook"},
+		mapping:        defaultMapping(),
 		circuitBreaker: &cbTestDouble{execErr: circuitbreaker.ErrCircuitHalfOpen, state: circuitbreaker.StateHalfOpen},
 	}
 	resp, err := c.SendMessage(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
@@ -177,7 +337,8 @@ func TestSendMessage_CBGenericError(t *testing.T) {
 	c := &client{
 		client:         new(mockRestyClient),
 		config:         config.WebhookConfig{URL: "http://webhook"},
-		circuitBreaker: &cbTestDouble{execErr: fmt.Errorf("cb fail"), state: circuitbreaker.StateOpen},
+		mapping:        defaultMapping(),
+		circuitBreaker: &cbTestDouble{execErr: fmt.Errorf("cb fail"), state: circuitbreaker.StateClosed},
 	}
 	resp, err := c.SendMessage(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
 	assert.Error(t, err)
@@ -185,6 +346,61 @@ func TestSendMessage_CBGenericError(t *testing.T) {
 	assert.Contains(t, err.Error(), "cb fail")
 }
 
+type limiterTestDouble struct {
+	err   error
+	calls int
+}
+
+func (l *limiterTestDouble) Wait(ctx context.Context) error {
+	l.calls++
+	return l.err
+}
+
+func TestSendMessage_RateLimited(t *testing.T) {
+	limiter := &limiterTestDouble{err: ratelimit.ErrLimitExceeded}
+	cb := &cbTestDouble{execErr: fmt.Errorf("should not be called")}
+	c := &client{
+		client:         new(mockRestyClient),
+		config:         config.WebhookConfig{URL: "http://webhook"},
+		mapping:        defaultMapping(),
+		circuitBreaker: cb,
+		rateLimiter:    limiter,
+	}
+	resp, err := c.SendMessage(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
+	assert.Equal(t, errors.ErrWebhookThrottled, err)
+	assert.Nil(t, resp)
+	assert.Equal(t, 1, limiter.calls)
+}
+
+func TestSendMessage_RateLimiterPermits(t *testing.T) {
+	limiter := &limiterTestDouble{}
+	c := &client{
+		client:         new(mockRestyClient),
+		config:         config.WebhookConfig{URL: "http://webhook"},
+		mapping:        defaultMapping(),
+		circuitBreaker: &cbTestDouble{execErr: circuitbreaker.ErrCircuitOpen},
+		rateLimiter:    limiter,
+	}
+	_, err := c.SendMessage(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
+	assert.Contains(t, err.Error(), "WEBHOOK_CIRCUIT_OPEN")
+	assert.Equal(t, 1, limiter.calls)
+}
+
+func TestSendMessage_OpenCircuitTakesNoToken(t *testing.T) {
+	limiter := &limiterTestDouble{}
+	c := &client{
+		client:         new(mockRestyClient),
+		config:         config.WebhookConfig{URL: "http://webhook"},
+		mapping:        defaultMapping(),
+		circuitBreaker: &cbTestDouble{execErr: fmt.Errorf("should not be called"), state: circuitbreaker.StateOpen},
+		rateLimiter:    limiter,
+	}
+	resp, err := c.SendMessage(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
+	assert.Equal(t, errors.ErrWebhookCircuitOpen, err)
+	assert.Nil(t, resp)
+	assert.Zero(t, limiter.calls)
+}
+
 func TestGetCircuitBreakerMetrics_And_State(t *testing.T) {
 	c := &client{circuitBreaker: nil}
 	metrics := c.GetCircuitBreakerMetrics()
@@ -244,3 +460,192 @@ func TestHealthCheck_Non2xx(t *testing.T) {
 	}
 	assert.Error(t, c.HealthCheck(context.Background()))
 }
+
+func defaultMapping() *mapping {
+	m, err := newMapping(config.MappingConfig{})
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// fakeAuth sets a numbered bearer token and renews it on every refresh.
+type fakeAuth struct {
+	token     int
+	refreshes int
+	err       error
+}
+
+func (a *fakeAuth) Authorize(ctx context.Context, headers map[string]string) error {
+	if a.err != nil {
+		return a.err
+	}
+	headers["Authorization"] = fmt.Sprintf("Bearer token-%d", a.token)
+	return nil
+}
+
+func (a *fakeAuth) Refresh(ctx context.Context, headers map[string]string) error {
+	a.refreshes++
+	a.token++
+	return a.Authorize(ctx, headers)
+}
+
+// newUnauthorizedRequester answers the first unauthorized sends with 401 and the
+// rest with 200, recording the Authorization header of every send.
+func newUnauthorizedRequester(unauthorized int, tokens *[]string) *mockRestyClient {
+	mockClient := new(mockRestyClient)
+	mockReq := new(mockRestyRequest)
+
+	mockClient.On("R").Return(mockReq)
+	mockReq.On("SetContext", mock.Anything).Return(mockReq)
+	mockReq.On("SetBody", mock.Anything).Return(mockReq)
+	mockReq.On("SetHeaders", mock.Anything).Run(func(args mock.Arguments) {
+		*tokens = append(*tokens, args.Get(0).(map[string]string)["Authorization"])
+	}).Return(mockReq)
+
+	rejected := &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusUnauthorized}}
+	accepted := &resty.Response{RawResponse: &http.Response{StatusCode: http.StatusOK}}
+	mockReq.On("Post", "http://webhook").Return(rejected, nil).Times(unauthorized)
+	mockReq.On("Post", "http://webhook").Return(accepted, nil)
+	return mockClient
+}
+
+func TestSendMessage_RefreshesRejectedCredentials(t *testing.T) {
+	var tokens []string
+	auth := &fakeAuth{}
+	c := &client{
+		client:  newUnauthorizedRequester(1, &tokens),
+		config:  config.WebhookConfig{URL: "http://webhook"},
+		mapping: defaultMapping(),
+		auth:    auth,
+	}
+
+	_, err := c.sendMessageDirect(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Bearer token-0", "Bearer token-1"}, tokens)
+	assert.Equal(t, 1, auth.refreshes)
+}
+
+func TestSendMessage_RefreshesCredentialsOnce(t *testing.T) {
+	var tokens []string
+	auth := &fakeAuth{}
+	c := &client{
+		client:  newUnauthorizedRequester(2, &tokens),
+		config:  config.WebhookConfig{URL: "http://webhook"},
+		mapping: defaultMapping(),
+		auth:    auth,
+	}
+
+	_, err := c.sendMessageDirect(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
+	var webhookErr *Error
+	assert.ErrorAs(t, err, &webhookErr)
+	assert.Equal(t, http.StatusUnauthorized, webhookErr.StatusCode)
+	assert.Equal(t, webhookfailures.Permanent, webhookErr.Category)
+	assert.Len(t, tokens, 2)
+	assert.Equal(t, 1, auth.refreshes)
+}
+
+func TestSendMessage_AuthorizationFails(t *testing.T) {
+	mockClient := new(mockRestyClient)
+	c := &client{
+		client:  mockClient,
+		config:  config.WebhookConfig{URL: "http://webhook"},
+		mapping: defaultMapping(),
+		auth:    &fakeAuth{err: fmt.Errorf("token endpoint unreachable")},
+	}
+
+	_, err := c.sendMessageDirect(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
+	var webhookErr *Error
+	assert.ErrorAs(t, err, &webhookErr)
+	assert.Equal(t, webhookfailures.Transient, webhookErr.Category)
+	mockClient.AssertNotCalled(t, "R")
+}
+
+func TestSendMessage_StaticHeaderAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		auth   config.AuthConfig
+		header string
+		value  string
+	}{
+		{name: "default header", header: "x-ins-auth-key", value: "key"},
+		{name: "custom header", auth: config.AuthConfig{Type: "header", Header: "X-Api-Key"}, header: "X-Api-Key", value: "key"},
+		{name: "basic", auth: config.AuthConfig{Type: "basic", Username: "user", Password: "pass"}, header: "Authorization", value: "Basic dXNlcjpwYXNz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(mockRestyClient)
+			mockReq := new(mockRestyRequest)
+
+			var headers map[string]string
+			mockClient.On("R").Return(mockReq)
+			mockReq.On("SetContext", mock.Anything).Return(mockReq)
+			mockReq.On("SetBody", mock.Anything).Return(mockReq)
+			mockReq.On("SetHeaders", mock.Anything).Run(func(args mock.Arguments) { headers = args.Get(0).(map[string]string) }).Return(mockReq)
+			mockReq.On("Post", "http://webhook").Return(&resty.Response{RawResponse: &http.Response{StatusCode: http.StatusUnauthorized}}, nil).Once()
+
+			c, err := NewClient(config.WebhookConfig{URL: "http://webhook", AuthKey: "key", Auth: tt.auth}, config.CircuitBreakerConfig{})
+			assert.NoError(t, err)
+			c.(*client).client = mockClient
+
+			// Static credentials cannot be renewed, so a 401 is not retried.
+			_, err = c.SendMessage(context.Background(), domain.WebhookRequest{To: "+123", Content: "hi"})
+			assert.Equal(t, webhookfailures.Permanent, Category(err))
+			assert.Equal(t, tt.value, headers[tt.header])
+			mockReq.AssertNumberOfCalls(t, "Post", 1)
+		})
+	}
+}
+
+func TestNewClient_RejectsInvalidAuth(t *testing.T) {
+	for name, auth := range map[string]config.AuthConfig{
+		"unknown type":       {Type: "digest"},
+		"basic without user": {Type: "basic"},
+		"oauth2 without url": {Type: "oauth2", ClientID: "client"},
+		"oauth2 without id":  {Type: "oauth2", TokenURL: "https://auth.example.com/token"},
+		"negative refresh":   {Type: "oauth2", TokenURL: "https://auth.example.com/token", ClientID: "client", RefreshBefore: -time.Second},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewClient(config.WebhookConfig{Auth: auth}, config.CircuitBreakerConfig{})
+
+			var customErr *errors.Error
+			assert.ErrorAs(t, err, &customErr)
+			assert.Equal(t, "INVALID_WEBHOOK_CONFIG", customErr.Code)
+		})
+	}
+}
+
+func TestNewClient_RejectsInvalidTransport(t *testing.T) {
+	for name, cfg := range map[string]config.WebhookConfig{
+		"unknown TLS version": {TLS: config.TLSConfig{MinVersion: "1.4"}},
+		"missing certificate": {TLS: config.TLSConfig{CertFile: "/nonexistent/client.crt", KeyFile: "/nonexistent/client.key"}},
+		"missing CA bundle":   {TLS: config.TLSConfig{CAFile: "/nonexistent/ca.pem"}},
+		"proxy without host":  {ProxyURL: "http://"},
+		"socks proxy":         {ProxyURL: "socks5://egress.internal:1080"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewClient(cfg, config.CircuitBreakerConfig{})
+
+			var customErr *errors.Error
+			assert.ErrorAs(t, err, &customErr)
+			assert.Equal(t, "INVALID_WEBHOOK_CONFIG", customErr.Code)
+		})
+	}
+}
+
+func TestNewClient_TransportSettings(t *testing.T) {
+	c, err := NewClient(config.WebhookConfig{
+		URL:      "https://carrier.example.com",
+		TLS:      config.TLSConfig{MinVersion: "1.3"},
+		ProxyURL: "http://egress.internal:3128",
+	}, config.CircuitBreakerConfig{})
+	assert.NoError(t, err)
+
+	transport, err := c.(*client).client.(*realRestyRequester).client.Transport()
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), transport.TLSClientConfig.MinVersion)
+	proxy, err := transport.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "carrier.example.com"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "egress.internal:3128", proxy.Host)
+}
@@ -0,0 +1,104 @@
+package webhook
+
+import (
+	stderrors "errors"
+	"fmt"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
+	"insider-message-system/pkg/errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error is a webhook send that reached the provider, or tried to, and did not
+// succeed. StatusCode is zero when no response was received, and RetryAfter is
+// only set for throttled sends whose response carried a Retry-After header.
+// Provider is set by the router to the provider that was tried last.
+type Error struct {
+	Provider   string
+	Category   webhookfailures.WebhookFailure
+	StatusCode int
+	RetryAfter time.Duration
+	Err        *errors.Error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Category returns the failure category of err. Errors that did not come from a
+// webhook response, such as a failure to connect, are transient.
+func Category(err error) webhookfailures.WebhookFailure {
+	var webhookErr *Error
+	if stderrors.As(err, &webhookErr) {
+		return webhookErr.Category
+	}
+	return webhookfailures.Transient
+}
+
+// categoryForStatus classifies a non-2xx response. A request timeout is worth
+// retrying like a server error.
+func categoryForStatus(statusCode int) webhookfailures.WebhookFailure {
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return webhookfailures.Throttled
+	case statusCode == http.StatusRequestTimeout || statusCode >= 500:
+		return webhookfailures.Transient
+	default:
+		return webhookfailures.Permanent
+	}
+}
+
+func newRequestError(err error) *Error {
+	return &Error{
+		Category: webhookfailures.Transient,
+		Err:      errors.WrapError(err, "WEBHOOK_ERROR", "Failed to send request", http.StatusInternalServerError),
+	}
+}
+
+// newAuthError reports that a request could not be authorized, such as when the
+// token endpoint is unreachable. It is transient, like a provider that is down.
+func newAuthError(err error) *Error {
+	return &Error{
+		Category: webhookfailures.Transient,
+		Err:      errors.WrapError(err, "WEBHOOK_AUTH_ERROR", "Failed to authorize request", http.StatusInternalServerError),
+	}
+}
+
+func newResponseError(statusCode int, header http.Header, body string, now time.Time) *Error {
+	webhookErr := &Error{
+		Category:   categoryForStatus(statusCode),
+		StatusCode: statusCode,
+		Err: errors.NewErrorWithDetails(
+			"WEBHOOK_ERROR",
+			fmt.Sprintf("Webhook request failed with status %d", statusCode),
+			body,
+			http.StatusInternalServerError,
+		),
+	}
+	if webhookErr.Category == webhookfailures.Throttled {
+		webhookErr.RetryAfter = parseRetryAfter(header.Get("Retry-After"), now)
+	}
+	return webhookErr
+}
+
+// parseRetryAfter reads a Retry-After value given either in seconds or as an
+// HTTP date. It returns zero when the header is missing, invalid or in the past.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
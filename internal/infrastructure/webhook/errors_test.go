@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"insider-message-system/pkg/constants/enums/webhookfailures"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewResponseError_Categories(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		statusCode int
+		retryAfter string
+		category   webhookfailures.WebhookFailure
+		wait       time.Duration
+	}{
+		{name: "bad request", statusCode: 400, category: webhookfailures.Permanent},
+		{name: "unprocessable", statusCode: 422, category: webhookfailures.Permanent},
+		{name: "request timeout", statusCode: 408, category: webhookfailures.Transient},
+		{name: "server error", statusCode: 500, category: webhookfailures.Transient},
+		{name: "unavailable", statusCode: 503, retryAfter: "30", category: webhookfailures.Transient},
+		{name: "throttled in seconds", statusCode: 429, retryAfter: "30", category: webhookfailures.Throttled, wait: 30 * time.Second},
+		{name: "throttled until a date", statusCode: 429, retryAfter: "Mon, 15 Jan 2024 10:02:00 GMT", category: webhookfailures.Throttled, wait: 2 * time.Minute},
+		{name: "throttled until a past date", statusCode: 429, retryAfter: "Mon, 15 Jan 2024 09:00:00 GMT", category: webhookfailures.Throttled},
+		{name: "throttled without retry after", statusCode: 429, category: webhookfailures.Throttled},
+		{name: "throttled with invalid retry after", statusCode: 429, retryAfter: "soon", category: webhookfailures.Throttled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.retryAfter != "" {
+				header.Set("Retry-After", tt.retryAfter)
+			}
+
+			err := newResponseError(tt.statusCode, header, "body", now)
+			assert.Equal(t, tt.category, err.Category)
+			assert.Equal(t, tt.statusCode, err.StatusCode)
+			assert.Equal(t, tt.wait, err.RetryAfter)
+			assert.Equal(t, tt.category, Category(err))
+			assert.Equal(t, fmt.Sprintf("[WEBHOOK_ERROR] Webhook request failed with status %d", tt.statusCode), err.Error())
+		})
+	}
+}
+
+func TestCategory_DefaultsToTransient(t *testing.T) {
+	assert.Equal(t, webhookfailures.Transient, Category(newRequestError(fmt.Errorf("connection refused"))))
+	assert.Equal(t, webhookfailures.Transient, Category(fmt.Errorf("unknown")))
+	assert.Equal(t, webhookfailures.Permanent, Category(fmt.Errorf("wrapped: %w", newResponseError(400, nil, "", time.Now()))))
+}
@@ -0,0 +1,237 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
+	"insider-message-system/pkg/errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+)
+
+const (
+	contentTypeJSON = "application/json"
+	contentTypeForm = "application/x-www-form-urlencoded"
+)
+
+// defaultMappingConfig is the built-in format: a JSON {"to","content"} body, with
+// the provider message ID read from messageId and the message from message.
+var defaultMappingConfig = config.MappingConfig{
+	Request: config.RequestMappingConfig{
+		ContentType: contentTypeJSON,
+		Fields: []config.FieldMappingConfig{
+			{Path: "to", Value: "{{.To}}"},
+			{Path: "content", Value: "{{.Content}}"},
+		},
+	},
+	Response: config.ResponseMappingConfig{
+		MessageID: "messageId",
+		Status:    "message",
+	},
+}
+
+var templateFuncs = template.FuncMap{
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+}
+
+// mapping encodes requests in a provider's format and reads its responses.
+type mapping struct {
+	contentType      string
+	fields           []mappedField
+	messageIDPath    []string
+	messageIDHeader  string
+	statusPath       []string
+	acceptedStatuses map[string]bool
+}
+
+type mappedField struct {
+	path  string
+	value *template.Template
+}
+
+// newMapping compiles cfg. A request without fields sends the built-in to and
+// content fields, and a response that reads nothing falls back to the built-in
+// format.
+func newMapping(cfg config.MappingConfig) (*mapping, error) {
+	if len(cfg.Request.Fields) == 0 {
+		cfg.Request.Fields = defaultMappingConfig.Request.Fields
+	}
+	if cfg.Request.ContentType == "" {
+		cfg.Request.ContentType = contentTypeJSON
+	}
+	if cfg.Response.MessageID == "" && cfg.Response.MessageIDHeader == "" && cfg.Response.Status == "" {
+		cfg.Response = defaultMappingConfig.Response
+	}
+
+	m := &mapping{
+		contentType:     cfg.Request.ContentType,
+		messageIDPath:   splitPath(cfg.Response.MessageID),
+		messageIDHeader: cfg.Response.MessageIDHeader,
+		statusPath:      splitPath(cfg.Response.Status),
+	}
+
+	var problems []string
+	if m.contentType != contentTypeJSON && m.contentType != contentTypeForm {
+		problems = append(problems, fmt.Sprintf("mapping.request.content_type must be %q or %q", contentTypeJSON, contentTypeForm))
+	}
+	for i, field := range cfg.Request.Fields {
+		if field.Path == "" {
+			problems = append(problems, fmt.Sprintf("mapping.request.fields[%d].path is required", i))
+			continue
+		}
+		value, err := template.New(field.Path).Funcs(templateFuncs).Option("missingkey=error").Parse(field.Value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("mapping.request.fields[%d].value: %v", i, err))
+			continue
+		}
+		m.fields = append(m.fields, mappedField{path: field.Path, value: value})
+	}
+	if len(cfg.Response.AcceptedStatuses) > 0 {
+		if len(m.statusPath) == 0 {
+			problems = append(problems, "mapping.response.accepted_statuses requires mapping.response.status")
+		}
+		m.acceptedStatuses = make(map[string]bool, len(cfg.Response.AcceptedStatuses))
+		for _, status := range cfg.Response.AcceptedStatuses {
+			m.acceptedStatuses[status] = true
+		}
+	}
+
+	if len(problems) == 0 {
+		// Encoding a sample request catches templates that fail to execute and
+		// JSON paths that collide, such as "a" next to "a.b".
+		if _, err := m.encode(domain.WebhookRequest{To: "+905551111111", Content: "sample"}); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return nil, errors.NewErrorWithDetails("INVALID_WEBHOOK_CONFIG", "Invalid webhook configuration", strings.Join(problems, "; "), http.StatusInternalServerError)
+	}
+	return m, nil
+}
+
+// encode renders request as a request body in the provider's content type. A
+// JSON field path nests with dots; a form field path is used as the key as is.
+func (m *mapping) encode(request domain.WebhookRequest) ([]byte, error) {
+	form := url.Values{}
+	body := map[string]any{}
+
+	for _, field := range m.fields {
+		var value bytes.Buffer
+		if err := field.value.Execute(&value, request); err != nil {
+			return nil, fmt.Errorf("mapping field %q: %w", field.path, err)
+		}
+
+		if m.contentType == contentTypeForm {
+			form.Add(field.path, value.String())
+			continue
+		}
+		if err := setPath(body, splitPath(field.path), value.String()); err != nil {
+			return nil, fmt.Errorf("mapping field %q: %w", field.path, err)
+		}
+	}
+
+	if m.contentType == contentTypeForm {
+		return []byte(form.Encode()), nil
+	}
+	return json.Marshal(body)
+}
+
+// decode reads the provider message ID and status from a 2xx response. A status
+// outside the accepted statuses is a permanent failure: the provider answered but
+// did not take the message. A missing message ID is not an error.
+func (m *mapping) decode(header http.Header, body []byte) (*domain.MessageResponse, error) {
+	var document any
+	if len(m.messageIDPath) > 0 || len(m.statusPath) > 0 {
+		// A body that is not JSON simply has no values to read. Numbers are kept as
+		// written, so that a numeric message ID is not rounded.
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		_ = decoder.Decode(&document)
+	}
+
+	response := &domain.MessageResponse{
+		MessageID: lookupPath(document, m.messageIDPath),
+		Message:   lookupPath(document, m.statusPath),
+	}
+	if m.messageIDHeader != "" {
+		response.MessageID = header.Get(m.messageIDHeader)
+	}
+
+	if m.acceptedStatuses != nil && !m.acceptedStatuses[response.Message] {
+		return nil, &Error{
+			Category: webhookfailures.Permanent,
+			Err: errors.NewErrorWithDetails(
+				"WEBHOOK_ERROR",
+				fmt.Sprintf("Webhook response status %q is not accepted", response.Message),
+				string(body),
+				http.StatusInternalServerError,
+			),
+		}
+	}
+	return response, nil
+}
+
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+func setPath(document map[string]any, path []string, value string) error {
+	for _, key := range path[:len(path)-1] {
+		next, ok := document[key]
+		if !ok {
+			child := map[string]any{}
+			document[key] = child
+			document = child
+			continue
+		}
+		child, ok := next.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%q is already set to a value", key)
+		}
+		document = child
+	}
+
+	last := path[len(path)-1]
+	if _, ok := document[last]; ok {
+		return fmt.Errorf("%q is set twice", last)
+	}
+	document[last] = value
+	return nil
+}
+
+// lookupPath returns the value at path in a decoded JSON document as a string, or
+// an empty string when there is none.
+func lookupPath(document any, path []string) string {
+	if len(path) == 0 {
+		return ""
+	}
+	for _, key := range path {
+		object, ok := document.(map[string]any)
+		if !ok {
+			return ""
+		}
+		document = object[key]
+	}
+
+	switch value := document.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		return fmt.Sprint(value)
+	default:
+		encoded, _ := json.Marshal(value)
+		return string(encoded)
+	}
+}
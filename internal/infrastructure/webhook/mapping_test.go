@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
+	"insider-message-system/pkg/errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var mappedRequest = domain.WebhookRequest{To: "+905551111111", Content: "Hello & welcome"}
+
+func TestMapping_DefaultFormat(t *testing.T) {
+	m, err := newMapping(config.MappingConfig{})
+	require.NoError(t, err)
+
+	body, err := m.encode(mappedRequest)
+	require.NoError(t, err)
+	assert.Equal(t, contentTypeJSON, m.contentType)
+	assert.JSONEq(t, `{"to":"+905551111111","content":"Hello & welcome"}`, string(body))
+
+	response, err := m.decode(http.Header{}, []byte(`{"message":"Accepted","messageId":"67f2f8a8"}`))
+	require.NoError(t, err)
+	assert.Equal(t, &domain.MessageResponse{Message: "Accepted", MessageID: "67f2f8a8"}, response)
+
+	// A 2xx response without an ID still counts as delivered.
+	response, err = m.decode(http.Header{}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, response.MessageID)
+}
+
+func TestMapping_NestedJSON(t *testing.T) {
+	m, err := newMapping(config.MappingConfig{
+		Request: config.RequestMappingConfig{
+			Fields: []config.FieldMappingConfig{
+				{Path: "sender", Value: "INSIDER"},
+				{Path: "recipient.msisdn", Value: `{{trimPrefix "+" .To}}`},
+				{Path: "message.text", Value: "{{.Content}}"},
+			},
+		},
+		Response: config.ResponseMappingConfig{MessageID: "data.id", Status: "data.status"},
+	})
+	require.NoError(t, err)
+
+	body, err := m.encode(mappedRequest)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"sender":"INSIDER","recipient":{"msisdn":"905551111111"},"message":{"text":"Hello & welcome"}}`, string(body))
+
+	response, err := m.decode(http.Header{}, []byte(`{"data":{"id":12345678901234567,"status":"queued"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "12345678901234567", response.MessageID)
+	assert.Equal(t, "queued", response.Message)
+}
+
+func TestMapping_FormEncoded(t *testing.T) {
+	m, err := newMapping(config.MappingConfig{
+		Request: config.RequestMappingConfig{
+			ContentType: contentTypeForm,
+			Fields: []config.FieldMappingConfig{
+				{Path: "To", Value: "{{.To}}"},
+				{Path: "Body", Value: "{{.Content}}"},
+			},
+		},
+		Response: config.ResponseMappingConfig{MessageIDHeader: "X-Message-Id"},
+	})
+	require.NoError(t, err)
+
+	body, err := m.encode(mappedRequest)
+	require.NoError(t, err)
+	assert.Equal(t, contentTypeForm, m.contentType)
+	assert.Equal(t, "Body=Hello+%26+welcome&To=%2B905551111111", string(body))
+
+	response, err := m.decode(http.Header{"X-Message-Id": []string{"SM123"}}, []byte("OK"))
+	require.NoError(t, err)
+	assert.Equal(t, "SM123", response.MessageID)
+}
+
+func TestMapping_AcceptedStatuses(t *testing.T) {
+	m, err := newMapping(config.MappingConfig{
+		Response: config.ResponseMappingConfig{
+			MessageID:        "id",
+			Status:           "status",
+			AcceptedStatuses: []string{"queued", "sent"},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = m.decode(http.Header{}, []byte(`{"id":"1","status":"sent"}`))
+	assert.NoError(t, err)
+
+	_, err = m.decode(http.Header{}, []byte(`{"id":"1","status":"rejected"}`))
+	var webhookErr *Error
+	require.ErrorAs(t, err, &webhookErr)
+	assert.Equal(t, webhookfailures.Permanent, webhookErr.Category)
+}
+
+func TestNewMapping_RejectsInvalidConfig(t *testing.T) {
+	field := func(path, value string) config.RequestMappingConfig {
+		return config.RequestMappingConfig{Fields: []config.FieldMappingConfig{{Path: path, Value: value}}}
+	}
+
+	tests := []struct {
+		name string
+		cfg  config.MappingConfig
+	}{
+		{name: "unknown content type", cfg: config.MappingConfig{Request: config.RequestMappingConfig{ContentType: "text/xml", Fields: field("to", "{{.To}}").Fields}}},
+		{name: "empty path", cfg: config.MappingConfig{Request: field("", "{{.To}}")}},
+		{name: "unparsable template", cfg: config.MappingConfig{Request: field("to", "{{.To")}},
+		{name: "unknown template field", cfg: config.MappingConfig{Request: field("to", "{{.Recipient}}")}},
+		{name: "colliding paths", cfg: config.MappingConfig{Request: config.RequestMappingConfig{Fields: []config.FieldMappingConfig{
+			{Path: "to", Value: "{{.To}}"},
+			{Path: "to.number", Value: "{{.To}}"},
+		}}}},
+		{name: "accepted statuses without status", cfg: config.MappingConfig{Response: config.ResponseMappingConfig{MessageID: "id", AcceptedStatuses: []string{"sent"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newMapping(tt.cfg)
+			var customErr *errors.Error
+			require.ErrorAs(t, err, &customErr)
+			assert.Equal(t, "INVALID_WEBHOOK_CONFIG", customErr.Code)
+		})
+	}
+}
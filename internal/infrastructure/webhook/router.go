@@ -0,0 +1,221 @@
+package webhook
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/circuitbreaker"
+	"insider-message-system/pkg/constants/enums/routingstrategies"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
+	"insider-message-system/pkg/errors"
+	"insider-message-system/pkg/logger"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Provider is a named webhook client together with the routing rules that decide
+// which messages it may deliver. A provider with Prefixes only delivers to
+// recipients starting with one of them.
+type Provider struct {
+	Name     string
+	Client   Client
+	Priority int
+	Weight   int
+	Prefixes []string
+}
+
+type router struct {
+	providers []Provider
+	strategy  routingstrategies.RoutingStrategy
+	intN      func(n int) int
+}
+
+// NewRouter creates a Client that sends every message through one of providers.
+// When the chosen provider's circuit breaker rejects the send or the send fails
+// with a transient error, the message fails over to the next provider in the
+// route. The response names the provider that delivered the message.
+func NewRouter(providers []Provider, strategy routingstrategies.RoutingStrategy) (Client, error) {
+	if err := validateProviders(providers, strategy); err != nil {
+		return nil, err
+	}
+
+	return &router{
+		providers: append([]Provider(nil), providers...),
+		strategy:  strategy,
+		intN:      rand.IntN,
+	}, nil
+}
+
+func validateProviders(providers []Provider, strategy routingstrategies.RoutingStrategy) error {
+	var problems []string
+	if len(providers) == 0 {
+		problems = append(problems, "at least one provider is required")
+	}
+	if !strategy.IsValid() {
+		problems = append(problems, fmt.Sprintf("routing must be %q or %q", routingstrategies.Priority, routingstrategies.Weighted))
+	}
+
+	names := make(map[string]bool, len(providers))
+	for _, provider := range providers {
+		switch {
+		case provider.Name == "":
+			problems = append(problems, "every provider needs a name")
+		case names[provider.Name]:
+			problems = append(problems, fmt.Sprintf("provider %q is configured twice", provider.Name))
+		}
+		names[provider.Name] = true
+
+		if provider.Client == nil {
+			problems = append(problems, fmt.Sprintf("provider %q has no client", provider.Name))
+		}
+		if provider.Weight < 0 {
+			problems = append(problems, fmt.Sprintf("provider %q weight must not be negative", provider.Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.NewErrorWithDetails("INVALID_WEBHOOK_CONFIG", "Invalid webhook configuration", strings.Join(problems, "; "), http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func (r *router) SendMessage(ctx context.Context, request domain.WebhookRequest) (*domain.MessageResponse, error) {
+	route := r.route(request.To)
+	if len(route) == 0 {
+		logger.Warn("No webhook provider routes to recipient", zap.String("to", request.To))
+		return nil, &Error{
+			Category: webhookfailures.Permanent,
+			Err:      errors.NewError("WEBHOOK_NO_ROUTE", "No webhook provider routes to the recipient", http.StatusInternalServerError),
+		}
+	}
+
+	var rejection, failure error
+	for i, provider := range route {
+		response, err := provider.Client.SendMessage(ctx, request)
+		if err == nil {
+			response.Provider = provider.Name
+			return response, nil
+		}
+
+		var webhookErr *Error
+		if stderrors.As(err, &webhookErr) {
+			webhookErr.Provider = provider.Name
+			failure = err
+		} else {
+			rejection = err
+		}
+
+		if !canFailOver(err) || ctx.Err() != nil {
+			return nil, err
+		}
+		if i < len(route)-1 {
+			logger.Warn("Webhook provider unavailable, failing over",
+				zap.String("provider", provider.Name),
+				zap.String("next_provider", route[i+1].Name),
+				zap.String("to", request.To),
+				zap.Error(err))
+		}
+	}
+
+	// A send that reached a provider counts as a failed attempt; one that every
+	// breaker rejected is only deferred.
+	if failure != nil {
+		return nil, failure
+	}
+	return nil, rejection
+}
+
+// canFailOver reports whether another provider should be tried after err: the
+// provider's breaker rejected the send, or the send failed in a way that may not
+// happen elsewhere.
+func canFailOver(err error) bool {
+	if err == errors.ErrWebhookCircuitOpen || err == errors.ErrWebhookCircuitHalfOpen {
+		return true
+	}
+	var webhookErr *Error
+	return stderrors.As(err, &webhookErr) && webhookErr.Category == webhookfailures.Transient
+}
+
+// route returns the providers that may deliver to recipient in the order they are
+// tried: those with a matching prefix first, then those without prefixes, each
+// group ordered by the routing strategy.
+func (r *router) route(recipient string) []Provider {
+	var matched, fallback []Provider
+	for _, provider := range r.providers {
+		switch {
+		case len(provider.Prefixes) == 0:
+			fallback = append(fallback, provider)
+		case hasPrefix(recipient, provider.Prefixes):
+			matched = append(matched, provider)
+		}
+	}
+	return append(r.order(matched), r.order(fallback)...)
+}
+
+// order sorts providers by priority. With the weighted strategy, one provider with
+// a positive weight is drawn in proportion to its weight and moved to the front.
+func (r *router) order(providers []Provider) []Provider {
+	sort.SliceStable(providers, func(i, j int) bool {
+		return providers[i].Priority < providers[j].Priority
+	})
+	if r.strategy != routingstrategies.Weighted {
+		return providers
+	}
+
+	total := 0
+	for _, provider := range providers {
+		total += provider.Weight
+	}
+	if total == 0 {
+		return providers
+	}
+
+	draw := r.intN(total)
+	for i, provider := range providers {
+		if draw < provider.Weight {
+			copy(providers[1:i+1], providers[:i])
+			providers[0] = provider
+			break
+		}
+		draw -= provider.Weight
+	}
+	return providers
+}
+
+func hasPrefix(recipient string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(recipient, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCircuitBreakerMetrics returns each provider's breaker metrics by name.
+func (r *router) GetCircuitBreakerMetrics() map[string]any {
+	metrics := make(map[string]any, len(r.providers))
+	for _, provider := range r.providers {
+		metrics[provider.Name] = provider.Client.GetCircuitBreakerMetrics()
+	}
+	return metrics
+}
+
+// GetCircuitBreakerState reports the most available provider's state: closed while
+// any provider's breaker is closed, and open only once every breaker is open.
+func (r *router) GetCircuitBreakerState() circuitbreaker.State {
+	state := circuitbreaker.StateOpen
+	for _, provider := range r.providers {
+		switch provider.Client.GetCircuitBreakerState() {
+		case circuitbreaker.StateClosed:
+			return circuitbreaker.StateClosed
+		case circuitbreaker.StateHalfOpen:
+			state = circuitbreaker.StateHalfOpen
+		}
+	}
+	return state
+}
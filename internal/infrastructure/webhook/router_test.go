@@ -0,0 +1,238 @@
+package webhook
+
+import (
+	"context"
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/circuitbreaker"
+	"insider-message-system/pkg/constants/enums/routingstrategies"
+	"insider-message-system/pkg/constants/enums/webhookfailures"
+	"insider-message-system/pkg/errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient answers every send with err, or accepts it when err is nil.
+type fakeClient struct {
+	err   error
+	state circuitbreaker.State
+	sends int
+}
+
+func (f *fakeClient) SendMessage(ctx context.Context, request domain.WebhookRequest) (*domain.MessageResponse, error) {
+	f.sends++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &domain.MessageResponse{Message: "Accepted", MessageID: "provider-id"}, nil
+}
+
+func (f *fakeClient) GetCircuitBreakerMetrics() map[string]any {
+	return map[string]any{"state": f.state.String()}
+}
+
+func (f *fakeClient) GetCircuitBreakerState() circuitbreaker.State {
+	return f.state
+}
+
+func failure(category webhookfailures.WebhookFailure) error {
+	return &Error{Category: category, Err: errors.NewError("WEBHOOK_ERROR", "Webhook request failed", 500)}
+}
+
+func newTestRouter(t *testing.T, strategy routingstrategies.RoutingStrategy, providers ...Provider) *router {
+	client, err := NewRouter(providers, strategy)
+	require.NoError(t, err)
+	return client.(*router)
+}
+
+func names(providers []Provider) []string {
+	result := make([]string, len(providers))
+	for i, provider := range providers {
+		result[i] = provider.Name
+	}
+	return result
+}
+
+func TestNewRouter_RejectsInvalidProviders(t *testing.T) {
+	client := &fakeClient{}
+
+	tests := []struct {
+		name      string
+		providers []Provider
+		strategy  routingstrategies.RoutingStrategy
+	}{
+		{name: "no providers", strategy: routingstrategies.Priority},
+		{name: "unknown strategy", providers: []Provider{{Name: "a", Client: client}}, strategy: "round_robin"},
+		{name: "unnamed provider", providers: []Provider{{Client: client}}, strategy: routingstrategies.Priority},
+		{name: "duplicate name", providers: []Provider{{Name: "a", Client: client}, {Name: "a", Client: client}}, strategy: routingstrategies.Priority},
+		{name: "no client", providers: []Provider{{Name: "a"}}, strategy: routingstrategies.Priority},
+		{name: "negative weight", providers: []Provider{{Name: "a", Client: client, Weight: -1}}, strategy: routingstrategies.Weighted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRouter(tt.providers, tt.strategy)
+			var customErr *errors.Error
+			require.ErrorAs(t, err, &customErr)
+			assert.Equal(t, "INVALID_WEBHOOK_CONFIG", customErr.Code)
+		})
+	}
+}
+
+func TestRouter_Route(t *testing.T) {
+	client := &fakeClient{}
+	r := newTestRouter(t, routingstrategies.Priority,
+		Provider{Name: "global-backup", Client: client, Priority: 2},
+		Provider{Name: "uk", Client: client, Prefixes: []string{"+44"}},
+		Provider{Name: "global", Client: client, Priority: 1},
+		Provider{Name: "tr-backup", Client: client, Priority: 1, Prefixes: []string{"+90"}},
+		Provider{Name: "tr", Client: client, Prefixes: []string{"+90", "0090"}},
+	)
+
+	assert.Equal(t, []string{"tr", "tr-backup", "global", "global-backup"}, names(r.route("+905551111111")))
+	assert.Equal(t, []string{"tr", "global", "global-backup"}, names(r.route("00905551111111")))
+	assert.Equal(t, []string{"uk", "global", "global-backup"}, names(r.route("+447911123456")))
+	assert.Equal(t, []string{"global", "global-backup"}, names(r.route("+15551234567")))
+}
+
+func TestRouter_WeightedRoute(t *testing.T) {
+	client := &fakeClient{}
+	r := newTestRouter(t, routingstrategies.Weighted,
+		Provider{Name: "a", Client: client, Weight: 3},
+		Provider{Name: "b", Client: client, Priority: 1, Weight: 1},
+		Provider{Name: "standby", Client: client, Priority: 2},
+	)
+
+	// Draws 0-2 fall to a and 3 to b; the others follow in priority order.
+	for draw, want := range map[int][]string{
+		0: {"a", "b", "standby"},
+		2: {"a", "b", "standby"},
+		3: {"b", "a", "standby"},
+	} {
+		r.intN = func(n int) int {
+			assert.Equal(t, 4, n)
+			return draw
+		}
+		assert.Equal(t, want, names(r.route("+905551111111")))
+	}
+}
+
+func TestRouter_SendMessage_FailsOver(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "circuit open", err: errors.ErrWebhookCircuitOpen},
+		{name: "circuit half-open", err: errors.ErrWebhookCircuitHalfOpen},
+		{name: "transient error", err: failure(webhookfailures.Transient)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &fakeClient{err: tt.err}
+			backup := &fakeClient{}
+			r := newTestRouter(t, routingstrategies.Priority,
+				Provider{Name: "primary", Client: primary},
+				Provider{Name: "backup", Client: backup, Priority: 1},
+			)
+
+			response, err := r.SendMessage(context.Background(), domain.WebhookRequest{To: "+905551111111", Content: "hi"})
+			require.NoError(t, err)
+			assert.Equal(t, "backup", response.Provider)
+			assert.Equal(t, "provider-id", response.MessageID)
+			assert.Equal(t, 1, primary.sends)
+			assert.Equal(t, 1, backup.sends)
+		})
+	}
+}
+
+func TestRouter_SendMessage_DoesNotFailOver(t *testing.T) {
+	for _, category := range []webhookfailures.WebhookFailure{webhookfailures.Permanent, webhookfailures.Throttled} {
+		t.Run(category.String(), func(t *testing.T) {
+			primary := &fakeClient{err: failure(category)}
+			backup := &fakeClient{}
+			r := newTestRouter(t, routingstrategies.Priority,
+				Provider{Name: "primary", Client: primary},
+				Provider{Name: "backup", Client: backup, Priority: 1},
+			)
+
+			_, err := r.SendMessage(context.Background(), domain.WebhookRequest{To: "+905551111111"})
+			var webhookErr *Error
+			require.ErrorAs(t, err, &webhookErr)
+			assert.Equal(t, category, webhookErr.Category)
+			assert.Equal(t, "primary", webhookErr.Provider)
+			assert.Zero(t, backup.sends)
+		})
+	}
+
+	t.Run("local rate limit", func(t *testing.T) {
+		backup := &fakeClient{}
+		r := newTestRouter(t, routingstrategies.Priority,
+			Provider{Name: "primary", Client: &fakeClient{err: errors.ErrWebhookThrottled}},
+			Provider{Name: "backup", Client: backup, Priority: 1},
+		)
+
+		_, err := r.SendMessage(context.Background(), domain.WebhookRequest{To: "+905551111111"})
+		assert.Equal(t, errors.ErrWebhookThrottled, err)
+		assert.Zero(t, backup.sends)
+	})
+}
+
+func TestRouter_SendMessage_AllProvidersUnavailable(t *testing.T) {
+	t.Run("every breaker open", func(t *testing.T) {
+		r := newTestRouter(t, routingstrategies.Priority,
+			Provider{Name: "primary", Client: &fakeClient{err: errors.ErrWebhookCircuitOpen}},
+			Provider{Name: "backup", Client: &fakeClient{err: errors.ErrWebhookCircuitOpen}},
+		)
+
+		_, err := r.SendMessage(context.Background(), domain.WebhookRequest{To: "+905551111111"})
+		assert.Equal(t, errors.ErrWebhookCircuitOpen, err)
+	})
+
+	t.Run("a provider was reached", func(t *testing.T) {
+		sendErr := failure(webhookfailures.Transient)
+		r := newTestRouter(t, routingstrategies.Priority,
+			Provider{Name: "primary", Client: &fakeClient{err: sendErr}},
+			Provider{Name: "backup", Client: &fakeClient{err: errors.ErrWebhookCircuitOpen}, Priority: 1},
+		)
+
+		_, err := r.SendMessage(context.Background(), domain.WebhookRequest{To: "+905551111111"})
+		assert.Equal(t, sendErr, err)
+		assert.Equal(t, "primary", err.(*Error).Provider)
+	})
+
+	t.Run("no provider routes to the recipient", func(t *testing.T) {
+		r := newTestRouter(t, routingstrategies.Priority,
+			Provider{Name: "tr", Client: &fakeClient{}, Prefixes: []string{"+90"}},
+		)
+
+		_, err := r.SendMessage(context.Background(), domain.WebhookRequest{To: "+447911123456"})
+		assert.Equal(t, webhookfailures.Permanent, Category(err))
+	})
+}
+
+func TestRouter_CircuitBreakerState(t *testing.T) {
+	tests := []struct {
+		name   string
+		states []circuitbreaker.State
+		want   circuitbreaker.State
+	}{
+		{name: "any closed", states: []circuitbreaker.State{circuitbreaker.StateOpen, circuitbreaker.StateClosed}, want: circuitbreaker.StateClosed},
+		{name: "half-open and open", states: []circuitbreaker.State{circuitbreaker.StateOpen, circuitbreaker.StateHalfOpen}, want: circuitbreaker.StateHalfOpen},
+		{name: "all open", states: []circuitbreaker.State{circuitbreaker.StateOpen, circuitbreaker.StateOpen}, want: circuitbreaker.StateOpen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providers := make([]Provider, len(tt.states))
+			for i, state := range tt.states {
+				providers[i] = Provider{Name: string(rune('a' + i)), Client: &fakeClient{state: state}}
+			}
+			r := newTestRouter(t, routingstrategies.Priority, providers...)
+
+			assert.Equal(t, tt.want, r.GetCircuitBreakerState())
+			assert.Len(t, r.GetCircuitBreakerMetrics(), len(tt.states))
+		})
+	}
+}
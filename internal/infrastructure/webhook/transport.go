@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"crypto/tls"
+	"fmt"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/errors"
+	httppkg "insider-message-system/pkg/http"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// transportConfig holds the connection settings shared by requests to the
+// provider and to its OAuth2 token endpoint.
+type transportConfig struct {
+	tls      *tls.Config
+	proxyURL string
+}
+
+// newTransportConfig loads the TLS files and checks the proxy URL of cfg. Without
+// TLS settings the default TLS configuration is used, and without a proxy URL the
+// proxy comes from the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+func newTransportConfig(cfg config.WebhookConfig) (transportConfig, error) {
+	var problems []string
+
+	var tlsConfig *tls.Config
+	if cfg.TLS != (config.TLSConfig{}) {
+		var err error
+		tlsConfig, err = httppkg.NewTLSConfig(httppkg.TLSOptions{
+			CertFile:   cfg.TLS.CertFile,
+			KeyFile:    cfg.TLS.KeyFile,
+			CAFile:     cfg.TLS.CAFile,
+			MinVersion: cfg.TLS.MinVersion,
+		})
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("tls: %v", err))
+		}
+	}
+
+	if cfg.ProxyURL != "" {
+		proxy, err := url.Parse(cfg.ProxyURL)
+		if err != nil || (proxy.Scheme != "http" && proxy.Scheme != "https") || proxy.Host == "" {
+			problems = append(problems, "proxy_url must be an http:// or https:// URL")
+		}
+	}
+
+	if len(problems) > 0 {
+		return transportConfig{}, errors.NewErrorWithDetails("INVALID_WEBHOOK_CONFIG", "Invalid webhook configuration", strings.Join(problems, "; "), http.StatusInternalServerError)
+	}
+	return transportConfig{tls: tlsConfig, proxyURL: cfg.ProxyURL}, nil
+}
+
+func (t transportConfig) apply(clientConfig *httppkg.ClientConfig) {
+	clientConfig.TLS = t.tls
+	clientConfig.ProxyURL = t.proxyURL
+}
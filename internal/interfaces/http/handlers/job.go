@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"insider-message-system/internal/application/usecases"
+	_ "insider-message-system/pkg/apidocs"
+	"insider-message-system/pkg/errors"
+	"insider-message-system/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+)
+
+// JobHandler handles HTTP requests related to background jobs.
+type JobHandler struct {
+	controlJobsUC *usecases.ControlJobsUseCase
+}
+
+// NewJobHandler creates a new JobHandler with the provided use case.
+func NewJobHandler(controlJobsUC *usecases.ControlJobsUseCase) *JobHandler {
+	return &JobHandler{
+		controlJobsUC: controlJobsUC,
+	}
+}
+
+// ListJobs handles GET /v1/jobs requests to list the background jobs.
+// @Summary List background jobs
+// @Description Status, run policy and recent activity of every background job on this instance, ordered by name
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Success 200 {object} apidocs.JobListResponse
+// @Router /v1/jobs [get]
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	resp := response.Success(h.controlJobsUC.List(c.Request.Context()))
+	response.SendSuccess(c, resp)
+}
+
+// GetJobStatus handles GET /v1/jobs/{name} requests to retrieve a job's status.
+// @Summary Get background job status
+// @Description Status, run policy, next due run, last run and cumulative counters of a background job on this instance
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param name path string true "Job name" example(retention)
+// @Success 200 {object} apidocs.JobStatusResponse
+// @Failure 404 {object} apidocs.ErrorResponse
+// @Router /v1/jobs/{name} [get]
+func (h *JobHandler) GetJobStatus(c *gin.Context) {
+	status, err := h.controlJobsUC.GetStatus(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		sendJobError(c, err)
+		return
+	}
+
+	resp := response.Success(status)
+	response.SendSuccess(c, resp)
+}
+
+// StartJob handles POST /v1/jobs/{name}/start requests to start a job.
+// @Summary Start a background job
+// @Description Start running a background job periodically on this instance. The first run is due one interval, plus jitter, from now.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param name path string true "Job name" example(retention)
+// @Success 200 {object} apidocs.JobStatusResponse
+// @Failure 400 {object} apidocs.ErrorResponse
+// @Failure 404 {object} apidocs.ErrorResponse
+// @Router /v1/jobs/{name}/start [post]
+func (h *JobHandler) StartJob(c *gin.Context) {
+	status, err := h.controlJobsUC.Start(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		sendJobError(c, err)
+		return
+	}
+
+	resp := response.Success(status)
+	response.SendSuccess(c, resp)
+}
+
+// StopJob handles POST /v1/jobs/{name}/stop requests to stop a job.
+// @Summary Stop a background job
+// @Description Stop running a background job on this instance. A run in progress is cancelled and waited for before responding.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param name path string true "Job name" example(retention)
+// @Success 200 {object} apidocs.JobStatusResponse
+// @Failure 400 {object} apidocs.ErrorResponse
+// @Failure 404 {object} apidocs.ErrorResponse
+// @Router /v1/jobs/{name}/stop [post]
+func (h *JobHandler) StopJob(c *gin.Context) {
+	status, err := h.controlJobsUC.Stop(c.Request.Context(), c.Param("name"))
+	if err != nil {
+		sendJobError(c, err)
+		return
+	}
+
+	resp := response.Success(status)
+	response.SendSuccess(c, resp)
+}
+
+func sendJobError(c *gin.Context, err error) {
+	if customErr, ok := err.(*errors.Error); ok {
+		resp := response.New(customErr.Status, &response.Body{
+			Status: false,
+			Msg:    customErr.Message,
+			Data: map[string]string{
+				"code": customErr.Code,
+			},
+		}, &response.Log{
+			Level: zapcore.InfoLevel,
+			Msg:   customErr.Message,
+			Type:  response.API,
+		})
+		response.SendError(c, resp)
+		return
+	}
+
+	resp := response.InternalServerError(err.Error())
+	response.SendError(c, resp)
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"insider-message-system/internal/application/services"
+	"insider-message-system/internal/application/usecases"
+	"insider-message-system/pkg/constants/enums/joboverlaps"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupJobRouter(t *testing.T) *gin.Engine {
+	runner := services.NewJobRunner()
+	require.NoError(t, runner.Register(services.Job{
+		Name:        "retention",
+		Run:         func(ctx context.Context) error { return nil },
+		Interval:    time.Hour,
+		Overlap:     joboverlaps.Skip,
+		MaxAttempts: 1,
+	}))
+	t.Cleanup(func() { runner.Stop() })
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	handler := NewJobHandler(usecases.NewControlJobsUseCase(runner))
+	r.GET("/jobs", handler.ListJobs)
+	r.GET("/jobs/:name", handler.GetJobStatus)
+	r.POST("/jobs/:name/start", handler.StartJob)
+	r.POST("/jobs/:name/stop", handler.StopJob)
+	return r
+}
+
+func serveJobRequest(r *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, nil)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestListJobs(t *testing.T) {
+	w := serveJobRequest(setupJobRouter(t), "GET", "/jobs")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Status bool                         `json:"status"`
+		Data   []usecases.JobStatusResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.True(t, body.Status)
+	require.Len(t, body.Data, 1)
+	assert.Equal(t, "retention", body.Data[0].Name)
+	assert.Equal(t, "stopped", body.Data[0].Status)
+	assert.Equal(t, "1h0m0s", body.Data[0].Interval)
+}
+
+func TestStartStopJob(t *testing.T) {
+	r := setupJobRouter(t)
+
+	w := serveJobRequest(r, "POST", "/jobs/retention/start")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"running"`)
+
+	w = serveJobRequest(r, "POST", "/jobs/retention/start")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "JOB_ALREADY_RUNNING")
+
+	w = serveJobRequest(r, "GET", "/jobs/retention")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"next_run_at"`)
+
+	w = serveJobRequest(r, "POST", "/jobs/retention/stop")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"stopped"`)
+
+	w = serveJobRequest(r, "POST", "/jobs/retention/stop")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "JOB_NOT_RUNNING")
+}
+
+func TestGetJobStatus_NotFound(t *testing.T) {
+	w := serveJobRequest(setupJobRouter(t), "GET", "/jobs/missing")
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "JOB_NOT_FOUND")
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"insider-message-system/internal/application/usecases"
 	"insider-message-system/internal/domain"
@@ -36,7 +37,15 @@ func (m *mockMessageService) CreateMessage(ctx context.Context, req domain.Messa
 	return args.Get(0).(*domain.Message), args.Error(1)
 }
 
-func (m *mockMessageService) SendPendingMessages(ctx context.Context, limit int) error {
+func (m *mockMessageService) ClaimPendingMessages(ctx context.Context, limit int, lease time.Duration) ([]*domain.Message, error) {
+	return nil, nil
+}
+
+func (m *mockMessageService) ReleaseClaim(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *mockMessageService) SendMessage(ctx context.Context, message *domain.Message) error {
 	return nil
 }
 
@@ -71,7 +80,15 @@ func (n *nilMessageService) GetSentMessages(ctx context.Context, page, pageSize
 	return nil, 0, nil
 }
 
-func (n *nilMessageService) SendPendingMessages(ctx context.Context, limit int) error {
+func (n *nilMessageService) ClaimPendingMessages(ctx context.Context, limit int, lease time.Duration) ([]*domain.Message, error) {
+	return nil, nil
+}
+
+func (n *nilMessageService) ReleaseClaim(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (n *nilMessageService) SendMessage(ctx context.Context, message *domain.Message) error {
 	return nil
 }
 
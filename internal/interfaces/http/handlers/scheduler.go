@@ -24,17 +24,23 @@ func NewSchedulerHandler(controlSchedulerUC *usecases.ControlSchedulerUseCase) *
 }
 
 // @Summary Start the message scheduler
-// @Description Start automatic message sending process
+// @Description Start automatic message sending process. The new state is persisted and every instance follows it; changed_by defaults to the caller's IP address.
 // @Tags scheduler
 // @Accept json
 // @Produce json
+// @Param change body usecases.ChangeSchedulerStateRequest false "Who changes the state and why"
 // @Success 200 {object} apidocs.SchedulerResponse
 // @Failure 400 {object} apidocs.ErrorResponse
 // @Failure 500 {object} apidocs.ErrorResponse
 // @Router /v1/scheduler/start [post]
 // StartScheduler handles POST /v1/scheduler/start to start the message scheduler.
 func (h *SchedulerHandler) StartScheduler(c *gin.Context) {
-	_, err := h.controlSchedulerUC.Start(context.Background())
+	request, ok := bindChangeSchedulerStateRequest(c)
+	if !ok {
+		return
+	}
+
+	_, err := h.controlSchedulerUC.Start(c.Request.Context(), request)
 	if err != nil {
 		if err, ok := err.(*errors.Error); ok {
 			var resp *response.Response
@@ -67,17 +73,23 @@ func (h *SchedulerHandler) StartScheduler(c *gin.Context) {
 }
 
 // @Summary Stop the message scheduler
-// @Description Stop automatic message sending process
+// @Description Stop automatic message sending process. The new state is persisted and every instance follows it; changed_by defaults to the caller's IP address.
 // @Tags scheduler
 // @Accept json
 // @Produce json
+// @Param change body usecases.ChangeSchedulerStateRequest false "Who changes the state and why"
 // @Success 200 {object} apidocs.SchedulerResponse
 // @Failure 400 {object} apidocs.ErrorResponse
 // @Failure 500 {object} apidocs.ErrorResponse
 // @Router /v1/scheduler/stop [post]
 // StopScheduler handles POST /v1/scheduler/stop to stop the message scheduler.
 func (h *SchedulerHandler) StopScheduler(c *gin.Context) {
-	_, err := h.controlSchedulerUC.Stop(context.Background())
+	request, ok := bindChangeSchedulerStateRequest(c)
+	if !ok {
+		return
+	}
+
+	_, err := h.controlSchedulerUC.Stop(c.Request.Context(), request)
 	if err != nil {
 		if err, ok := err.(*errors.Error); ok {
 			var resp *response.Response
@@ -110,11 +122,11 @@ func (h *SchedulerHandler) StopScheduler(c *gin.Context) {
 }
 
 // @Summary Get scheduler status
-// @Description Get current status of the message scheduler
+// @Description Get current status of the message scheduler, its next scheduled tick, uptime, recent cycles (newest first) and cumulative counters
 // @Tags scheduler
 // @Accept json
 // @Produce json
-// @Success 200 {object} apidocs.SchedulerResponse
+// @Success 200 {object} apidocs.SchedulerStatusResponse
 // @Router /v1/scheduler/status [get]
 // GetSchedulerStatus handles GET /v1/scheduler/status to get the scheduler's current status.
 func (h *SchedulerHandler) GetSchedulerStatus(c *gin.Context) {
@@ -122,3 +134,147 @@ func (h *SchedulerHandler) GetSchedulerStatus(c *gin.Context) {
 	resp := response.Success(statusResponse)
 	response.SendSuccess(c, resp)
 }
+
+// @Summary Get scheduler configuration
+// @Description Get the interval, batch size and retry settings the scheduler is currently using
+// @Tags scheduler
+// @Accept json
+// @Produce json
+// @Success 200 {object} apidocs.SchedulerConfigResponse
+// @Router /v1/scheduler/config [get]
+// GetSchedulerConfig handles GET /v1/scheduler/config to get the scheduler's runtime settings.
+func (h *SchedulerHandler) GetSchedulerConfig(c *gin.Context) {
+	configResponse := h.controlSchedulerUC.GetConfig(c.Request.Context())
+	resp := response.Success(configResponse)
+	response.SendSuccess(c, resp)
+}
+
+// @Summary Update scheduler configuration
+// @Description Change the interval, batch size or retry settings of the running scheduler. Omitted fields keep their current value. A new interval resets the ticker, and the other settings apply from the next cycle.
+// @Tags scheduler
+// @Accept json
+// @Produce json
+// @Param config body usecases.UpdateSchedulerConfigRequest true "Settings to change"
+// @Success 200 {object} apidocs.SchedulerConfigResponse
+// @Failure 400 {object} apidocs.ErrorResponse
+// @Failure 500 {object} apidocs.ErrorResponse
+// @Router /v1/scheduler/config [patch]
+// UpdateSchedulerConfig handles PATCH /v1/scheduler/config to change the scheduler's runtime settings.
+func (h *SchedulerHandler) UpdateSchedulerConfig(c *gin.Context) {
+	var request usecases.UpdateSchedulerConfigRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		resp := response.ValidationError(err.Error())
+		response.SendError(c, resp)
+		return
+	}
+
+	configResponse, err := h.controlSchedulerUC.UpdateConfig(c.Request.Context(), request)
+	if err != nil {
+		if err, ok := err.(*errors.Error); ok {
+			var resp *response.Response
+			switch err.Code {
+			case "INVALID_SCHEDULER_SETTINGS":
+				resp = response.ValidationError(err.Details)
+			default:
+				resp = response.New(err.Status, &response.Body{
+					Status: false,
+					Msg:    err.Message,
+					Data: map[string]string{
+						"code": err.Code,
+					},
+				}, &response.Log{
+					Level: zapcore.InfoLevel,
+					Msg:   err.Message,
+					Type:  response.API,
+				})
+			}
+			response.SendError(c, resp)
+		} else {
+			resp := response.InternalServerError(err.Error())
+			response.SendError(c, resp)
+		}
+		return
+	}
+
+	resp := response.Success(configResponse)
+	response.SendSuccess(c, resp)
+}
+
+// @Summary Run one processing cycle
+// @Description Send pending messages right now, without starting the periodic scheduler. Synchronous runs return what was processed; async runs return 202 as soon as the cycle has started. Refused with 409 while another cycle is in progress.
+// @Tags scheduler
+// @Accept json
+// @Produce json
+// @Param run body usecases.RunSchedulerRequest false "Batch size override and mode"
+// @Success 200 {object} apidocs.SchedulerRunResponse
+// @Success 202 {object} apidocs.SchedulerRunResponse
+// @Failure 400 {object} apidocs.ErrorResponse
+// @Failure 409 {object} apidocs.ErrorResponse
+// @Failure 500 {object} apidocs.ErrorResponse
+// @Router /v1/scheduler/run [post]
+// RunScheduler handles POST /v1/scheduler/run to run a single processing cycle.
+func (h *SchedulerHandler) RunScheduler(c *gin.Context) {
+	var request usecases.RunSchedulerRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			resp := response.ValidationError(err.Error())
+			response.SendError(c, resp)
+			return
+		}
+	}
+
+	runResponse, err := h.controlSchedulerUC.RunOnce(c.Request.Context(), request)
+	if err != nil {
+		if err, ok := err.(*errors.Error); ok {
+			var resp *response.Response
+			switch err.Code {
+			case "INVALID_SCHEDULER_SETTINGS":
+				resp = response.ValidationError(err.Details)
+			default:
+				resp = response.New(err.Status, &response.Body{
+					Status: false,
+					Msg:    err.Message,
+					Data: map[string]string{
+						"code": err.Code,
+					},
+				}, &response.Log{
+					Level: zapcore.InfoLevel,
+					Msg:   err.Message,
+					Type:  response.API,
+				})
+			}
+			response.SendError(c, resp)
+		} else {
+			resp := response.InternalServerError(err.Error())
+			response.SendError(c, resp)
+		}
+		return
+	}
+
+	if request.Async {
+		resp := response.SchedulerRunStarted(runResponse)
+		response.SendSuccess(c, resp)
+		return
+	}
+
+	resp := response.Success(runResponse)
+	response.SendSuccess(c, resp)
+}
+
+// bindChangeSchedulerStateRequest reads the optional body of a start or stop
+// request. It sends a validation error and returns false when the body is invalid.
+func bindChangeSchedulerStateRequest(c *gin.Context) (usecases.ChangeSchedulerStateRequest, bool) {
+	var request usecases.ChangeSchedulerStateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			resp := response.ValidationError(err.Error())
+			response.SendError(c, resp)
+			return request, false
+		}
+	}
+
+	if request.ChangedBy == "" {
+		request.ChangedBy = c.ClientIP()
+	}
+	return request, true
+}
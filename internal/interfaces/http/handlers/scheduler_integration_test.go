@@ -26,8 +26,8 @@ import (
 
 type mockProcessor struct{}
 
-func (m *mockProcessor) ProcessMessages(ctx context.Context) error {
-	return nil
+func (m *mockProcessor) ProcessMessages(ctx context.Context, batchSize int) (*domain.BatchResult, error) {
+	return &domain.BatchResult{}, nil
 }
 
 func setupSchedulerTestServer(t *testing.T) *httptest.Server {
@@ -2,12 +2,21 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"insider-message-system/internal/application/services"
 	"insider-message-system/internal/application/usecases"
+	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/database/repos"
+	"insider-message-system/internal/infrastructure/election"
+	"insider-message-system/internal/infrastructure/notifier"
+	"insider-message-system/pkg/config"
+	"insider-message-system/pkg/constants/enums/cycletriggers"
 	"insider-message-system/pkg/errors"
 	"insider-message-system/pkg/response"
 
@@ -19,8 +28,8 @@ import (
 
 // ControlSchedulerUseCaseInterface defines the interface for the use case
 type ControlSchedulerUseCaseInterface interface {
-	Start(ctx context.Context) (*usecases.SchedulerStatusResponse, error)
-	Stop(ctx context.Context) (*usecases.SchedulerStatusResponse, error)
+	Start(ctx context.Context, request usecases.ChangeSchedulerStateRequest) (*usecases.SchedulerStatusResponse, error)
+	Stop(ctx context.Context, request usecases.ChangeSchedulerStateRequest) (*usecases.SchedulerStatusResponse, error)
 	GetStatus(ctx context.Context) *usecases.SchedulerStatusResponse
 }
 
@@ -28,16 +37,16 @@ type mockControlSchedulerUseCase struct {
 	mock.Mock
 }
 
-func (m *mockControlSchedulerUseCase) Start(ctx context.Context) (*usecases.SchedulerStatusResponse, error) {
-	args := m.Called(ctx)
+func (m *mockControlSchedulerUseCase) Start(ctx context.Context, request usecases.ChangeSchedulerStateRequest) (*usecases.SchedulerStatusResponse, error) {
+	args := m.Called(ctx, request)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*usecases.SchedulerStatusResponse), args.Error(1)
 }
 
-func (m *mockControlSchedulerUseCase) Stop(ctx context.Context) (*usecases.SchedulerStatusResponse, error) {
-	args := m.Called(ctx)
+func (m *mockControlSchedulerUseCase) Stop(ctx context.Context, request usecases.ChangeSchedulerStateRequest) (*usecases.SchedulerStatusResponse, error) {
+	args := m.Called(ctx, request)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -59,7 +68,7 @@ type testSchedulerHandler struct {
 }
 
 func (h *testSchedulerHandler) StartScheduler(c *gin.Context) {
-	_, err := h.mockUC.Start(context.Background())
+	_, err := h.mockUC.Start(context.Background(), usecases.ChangeSchedulerStateRequest{})
 	if err != nil {
 		if err, ok := err.(*errors.Error); ok {
 			var resp *response.Response
@@ -92,7 +101,7 @@ func (h *testSchedulerHandler) StartScheduler(c *gin.Context) {
 }
 
 func (h *testSchedulerHandler) StopScheduler(c *gin.Context) {
-	_, err := h.mockUC.Stop(context.Background())
+	_, err := h.mockUC.Stop(context.Background(), usecases.ChangeSchedulerStateRequest{})
 	if err != nil {
 		if err, ok := err.(*errors.Error); ok {
 			var resp *response.Response
@@ -148,7 +157,7 @@ func TestSchedulerHandler_StartScheduler_Success(t *testing.T) {
 		Message: "Scheduler started successfully",
 	}
 
-	mockUC.On("Start", mock.Anything).Return(statusResponse, nil)
+	mockUC.On("Start", mock.Anything, mock.Anything).Return(statusResponse, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -167,7 +176,7 @@ func TestSchedulerHandler_StartScheduler_AlreadyRunning(t *testing.T) {
 	handler := newTestSchedulerHandler(mockUC)
 
 	err := errors.NewError("SCHEDULER_ALREADY_RUNNING", "Scheduler is already running", 409)
-	mockUC.On("Start", mock.Anything).Return(nil, err)
+	mockUC.On("Start", mock.Anything, mock.Anything).Return(nil, err)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -186,7 +195,7 @@ func TestSchedulerHandler_StartScheduler_GenericError(t *testing.T) {
 	handler := newTestSchedulerHandler(mockUC)
 
 	err := errors.NewError("UNKNOWN_ERROR", "Some unknown error", 500)
-	mockUC.On("Start", mock.Anything).Return(nil, err)
+	mockUC.On("Start", mock.Anything, mock.Anything).Return(nil, err)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -209,7 +218,7 @@ func TestSchedulerHandler_StopScheduler_Success(t *testing.T) {
 		Message: "Scheduler stopped successfully",
 	}
 
-	mockUC.On("Stop", mock.Anything).Return(statusResponse, nil)
+	mockUC.On("Stop", mock.Anything, mock.Anything).Return(statusResponse, nil)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -228,7 +237,7 @@ func TestSchedulerHandler_StopScheduler_GenericError(t *testing.T) {
 	handler := newTestSchedulerHandler(mockUC)
 
 	err := errors.NewError("UNKNOWN_ERROR", "Some unknown error", 500)
-	mockUC.On("Stop", mock.Anything).Return(nil, err)
+	mockUC.On("Stop", mock.Anything, mock.Anything).Return(nil, err)
 
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
@@ -263,6 +272,48 @@ func TestSchedulerHandler_GetSchedulerStatus(t *testing.T) {
 	mockUC.AssertExpectations(t)
 }
 
+func TestSchedulerHandler_GetSchedulerStatus_WithHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUC := new(mockControlSchedulerUseCase)
+	handler := newTestSchedulerHandler(mockUC)
+
+	nextRunAt := time.Date(2024, 1, 15, 10, 2, 0, 0, time.UTC)
+	cycle := domain.SchedulerCycle{Trigger: cycletriggers.Tick, Attempts: 2, Fetched: 2, Sent: 2, Errors: []string{"db down"}}
+	statusResponse := &usecases.SchedulerStatusResponse{
+		Status:        "running",
+		Message:       "Scheduler is currently running",
+		InstanceID:    "api-1",
+		UptimeSeconds: 120,
+		NextRunAt:     &nextRunAt,
+		LastCycle:     &cycle,
+		Cycles:        []domain.SchedulerCycle{cycle},
+		Totals:        &domain.SchedulerTotals{Cycles: 1, Fetched: 2, Sent: 2},
+	}
+
+	mockUC.On("GetStatus", mock.Anything).Return(statusResponse)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	handler.GetSchedulerStatus(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data usecases.SchedulerStatusResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "api-1", body.Data.InstanceID)
+	assert.Equal(t, int64(120), body.Data.UptimeSeconds)
+	assert.True(t, nextRunAt.Equal(*body.Data.NextRunAt))
+	assert.Equal(t, cycletriggers.Tick, body.Data.LastCycle.Trigger)
+	assert.Equal(t, []string{"db down"}, body.Data.LastCycle.Errors)
+	assert.Len(t, body.Data.Cycles, 1)
+	assert.Equal(t, int64(1), body.Data.Totals.Cycles)
+	mockUC.AssertExpectations(t)
+}
+
 func TestSchedulerHandler_GetSchedulerStatus_NilResponse(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -294,6 +345,27 @@ func (m *mockSchedulerService) Start(ctx context.Context) error
 func (m *mockSchedulerService) Stop() error                                             { return nil }
 func (m *mockSchedulerService) IsRunning() bool                                         { return true }
 func (m *mockSchedulerService) SetMessageProcessor(processor services.MessageProcessor) {}
+func (m *mockSchedulerService) SetNotifier(notifier notifier.Notifier)                  {}
+func (m *mockSchedulerService) SetElector(elector election.Elector)                     {}
+func (m *mockSchedulerService) SetBatchController(controller services.BatchController)  {}
+func (m *mockSchedulerService) SetSettingsRepository(repo repos.SchedulerSettings)      {}
+func (m *mockSchedulerService) SetStateRepository(repo repos.SchedulerState)            {}
+func (m *mockSchedulerService) SetDesiredState(ctx context.Context, state domain.SchedulerDesiredState) error {
+	return nil
+}
+func (m *mockSchedulerService) Reconcile(ctx context.Context) error    { return nil }
+func (m *mockSchedulerService) LoadSettings(ctx context.Context) error { return nil }
+func (m *mockSchedulerService) Settings() domain.SchedulerSettings     { return domain.SchedulerSettings{} }
+func (m *mockSchedulerService) UpdateSettings(ctx context.Context, settings domain.SchedulerSettings) error {
+	return nil
+}
+func (m *mockSchedulerService) RunOnce(ctx context.Context, batchSize int) (*domain.BatchResult, error) {
+	return &domain.BatchResult{}, nil
+}
+func (m *mockSchedulerService) RunOnceAsync(ctx context.Context, batchSize int) error { return nil }
+func (m *mockSchedulerService) Status() domain.SchedulerStatus {
+	return domain.SchedulerStatus{Running: true}
+}
 
 func TestNewSchedulerHandler_Coverage(t *testing.T) {
 	mockService := &mockSchedulerService{}
@@ -328,3 +400,150 @@ func TestSchedulerHandler_Methods_Coverage(t *testing.T) {
 	r.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+func TestSchedulerHandler_Config(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scheduler := services.NewScheduler(config.SchedulerConfig{
+		Interval:   2 * time.Minute,
+		BatchSize:  2,
+		MaxRetries: 3,
+		RetryDelay: 5 * time.Second,
+	})
+	h := NewSchedulerHandler(usecases.NewControlSchedulerUseCase(scheduler))
+	r := gin.New()
+	r.GET("/config", h.GetSchedulerConfig)
+	r.PATCH("/config", h.UpdateSchedulerConfig)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/config", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"interval":"2m0s"`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PATCH", "/config", strings.NewReader(`{"interval":"30s","batch_size":10}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"interval":"30s"`)
+	assert.Contains(t, w.Body.String(), `"batch_size":10`)
+	assert.Equal(t, 10, scheduler.Settings().BatchSize)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PATCH", "/config", strings.NewReader(`{"batch_size":5000}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "batch_size must be between")
+	assert.Equal(t, 10, scheduler.Settings().BatchSize)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PATCH", "/config", strings.NewReader(`{"batch_size":"many"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+type stubBatchProcessor struct {
+	batchSizes chan int
+}
+
+func (p *stubBatchProcessor) ProcessMessages(ctx context.Context, batchSize int) (*domain.BatchResult, error) {
+	p.batchSizes <- batchSize
+	return &domain.BatchResult{Fetched: 3, Sent: 2, Failed: 1}, nil
+}
+
+func TestSchedulerHandler_RunScheduler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	processor := &stubBatchProcessor{batchSizes: make(chan int, 10)}
+	scheduler := services.NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1})
+	scheduler.SetMessageProcessor(processor)
+	h := NewSchedulerHandler(usecases.NewControlSchedulerUseCase(scheduler))
+	r := gin.New()
+	r.POST("/run", h.RunScheduler)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/run", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"mode":"sync"`)
+	assert.Contains(t, w.Body.String(), `"result":{"fetched":3,"sent":2,"failed":1,"skipped":0,"deferred":0,"unsent":0}`)
+	assert.Equal(t, 2, <-processor.batchSizes)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/run", strings.NewReader(`{"batch_size":40,"async":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Contains(t, w.Body.String(), `"mode":"async"`)
+	assert.NotContains(t, w.Body.String(), `"result"`)
+	select {
+	case size := <-processor.batchSizes:
+		assert.Equal(t, 40, size)
+	case <-time.After(time.Second):
+		t.Fatal("async run did not process messages")
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/run", strings.NewReader(`{"batch_size":0}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/run", strings.NewReader(`{"batch_size":5000}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSchedulerHandler_StartStop_RecordsDesiredState(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scheduler := services.NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1})
+	scheduler.SetMessageProcessor(&stubBatchProcessor{batchSizes: make(chan int, 10)})
+	defer scheduler.Stop()
+	h := NewSchedulerHandler(usecases.NewControlSchedulerUseCase(scheduler))
+	r := gin.New()
+	r.POST("/start", h.StartScheduler)
+	r.POST("/stop", h.StopScheduler)
+	r.GET("/status", h.GetSchedulerStatus)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/start", nil)
+	req.RemoteAddr = "10.0.0.7:51234"
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "10.0.0.7", scheduler.Status().DesiredState.ChangedBy)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/stop", strings.NewReader(`{"changed_by":"ops@example.com","reason":"provider maintenance"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, scheduler.IsRunning())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/status", nil)
+	r.ServeHTTP(w, req)
+	assert.Contains(t, w.Body.String(), `"desired_state":{"running":false,"changed_by":"ops@example.com","reason":"provider maintenance"`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/stop", strings.NewReader(`{"reason":`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSchedulerHandler_RunScheduler_ProcessorNotSet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scheduler := services.NewScheduler(config.SchedulerConfig{Interval: time.Hour, BatchSize: 2, MaxRetries: 1})
+	h := NewSchedulerHandler(usecases.NewControlSchedulerUseCase(scheduler))
+	r := gin.New()
+	r.POST("/run", h.RunScheduler)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/run", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "PROCESSOR_NOT_SET")
+}
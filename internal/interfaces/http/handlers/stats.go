@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"insider-message-system/internal/application/usecases"
+	_ "insider-message-system/pkg/apidocs"
+	"insider-message-system/pkg/errors"
+	"insider-message-system/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+)
+
+// StatsHandler handles HTTP requests related to delivery statistics.
+type StatsHandler struct {
+	getStatsUC *usecases.GetStatsUseCase
+}
+
+// NewStatsHandler creates a new StatsHandler with the provided use case.
+func NewStatsHandler(getStatsUC *usecases.GetStatsUseCase) *StatsHandler {
+	return &StatsHandler{
+		getStatsUC: getStatsUC,
+	}
+}
+
+// GetStats handles GET /v1/stats requests to retrieve delivery statistics.
+// @Summary Get delivery statistics
+// @Description Message counts per status, a per-hour or per-day time series, and queue-to-send latency percentiles for a date range. Each message is counted when it entered its status: sent_at for sent, failed_at for failed and created_at for pending.
+// @Tags stats
+// @Accept json
+// @Produce json
+// @Param from query string false "Range start (RFC3339), defaults to one day or one week before 'to'"
+// @Param to query string false "Range end (RFC3339), defaults to now"
+// @Param interval query string false "Bucket size" Enums(hour, day) default(hour)
+// @Success 200 {object} apidocs.StatsResponse
+// @Failure 400 {object} apidocs.ErrorResponse
+// @Failure 500 {object} apidocs.ErrorResponse
+// @Router /v1/stats [get]
+func (h *StatsHandler) GetStats(c *gin.Context) {
+	var request usecases.GetStatsRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		resp := response.ValidationError(err.Error())
+		response.SendError(c, resp)
+		return
+	}
+
+	stats, err := h.getStatsUC.Execute(c.Request.Context(), request)
+	if err != nil {
+		if customErr, ok := err.(*errors.Error); ok {
+			resp := response.New(customErr.Status, &response.Body{
+				Status: false,
+				Msg:    customErr.Message,
+				Data: map[string]string{
+					"code": customErr.Code,
+				},
+			}, &response.Log{
+				Level: zapcore.InfoLevel,
+				Msg:   customErr.Message,
+				Type:  response.API,
+			})
+			response.SendError(c, resp)
+		} else {
+			resp := response.InternalServerError(err.Error())
+			response.SendError(c, resp)
+		}
+		return
+	}
+
+	resp := response.Success(stats)
+	response.SendSuccess(c, resp)
+}
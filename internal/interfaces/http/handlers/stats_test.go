@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"insider-message-system/internal/application/usecases"
+	"insider-message-system/internal/domain"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/statsintervals"
+	customerrors "insider-message-system/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockStatsService struct {
+	mock.Mock
+}
+
+func (m *mockStatsService) GetStats(ctx context.Context, from, to time.Time, interval statsintervals.StatsInterval) (*domain.MessageStats, error) {
+	args := m.Called(ctx, from, to, interval)
+	stats, _ := args.Get(0).(*domain.MessageStats)
+	return stats, args.Error(1)
+}
+
+func setupStatsRouter(svc *mockStatsService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	handler := NewStatsHandler(usecases.NewGetStatsUseCase(svc))
+	r.GET("/stats", handler.GetStats)
+	return r
+}
+
+func TestGetStats_Success(t *testing.T) {
+	svc := new(mockStatsService)
+	from := time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	svc.On("GetStats", mock.Anything, from, to, statsintervals.Day).Return(&domain.MessageStats{
+		From:     from,
+		To:       to,
+		Interval: statsintervals.Day,
+		Totals:   domain.StatusCounts{messagestatus.Sent: 5},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/stats?from=2024-01-14T00:00:00Z&to=2024-01-15T00:00:00Z&interval=day", nil)
+	setupStatsRouter(svc).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Status bool `json:"status"`
+		Data   struct {
+			Interval string           `json:"interval"`
+			Totals   map[string]int64 `json:"totals"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.True(t, body.Status)
+	assert.Equal(t, "day", body.Data.Interval)
+	assert.Equal(t, int64(5), body.Data.Totals["sent"])
+}
+
+func TestGetStats_InvalidInterval(t *testing.T) {
+	svc := new(mockStatsService)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/stats?interval=week", nil)
+	setupStatsRouter(svc).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	svc.AssertNotCalled(t, "GetStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetStats_ServiceError(t *testing.T) {
+	svc := new(mockStatsService)
+	svc.On("GetStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, customerrors.ErrInvalidStatsRange)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/stats?from=2024-01-15T00:00:00Z&to=2024-01-14T00:00:00Z", nil)
+	setupStatsRouter(svc).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_STATS_RANGE")
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"insider-message-system/internal/infrastructure/database"
 	"insider-message-system/internal/infrastructure/webhook"
 	"insider-message-system/internal/interfaces/http/handlers"
 	"insider-message-system/internal/interfaces/http/middleware"
@@ -15,7 +16,10 @@ import (
 type RouteConfig struct {
 	MessageHandler   *handlers.MessageHandler
 	SchedulerHandler *handlers.SchedulerHandler
+	StatsHandler     *handlers.StatsHandler
+	JobHandler       *handlers.JobHandler
 	WebhookClient    webhook.Client
+	Database         *database.DB
 	AuthKey          string
 }
 
@@ -47,6 +51,19 @@ func SetupRoutes(router *gin.Engine, config RouteConfig) {
 		schedulerRoutes.POST("/start", config.SchedulerHandler.StartScheduler)
 		schedulerRoutes.POST("/stop", config.SchedulerHandler.StopScheduler)
 		schedulerRoutes.GET("/status", config.SchedulerHandler.GetSchedulerStatus)
+		schedulerRoutes.GET("/config", config.SchedulerHandler.GetSchedulerConfig)
+		schedulerRoutes.PATCH("/config", config.SchedulerHandler.UpdateSchedulerConfig)
+		schedulerRoutes.POST("/run", config.SchedulerHandler.RunScheduler)
+	}
+
+	api.GET("/stats", config.StatsHandler.GetStats)
+
+	jobRoutes := api.Group("/jobs")
+	{
+		jobRoutes.GET("", config.JobHandler.ListJobs)
+		jobRoutes.GET("/:name", config.JobHandler.GetJobStatus)
+		jobRoutes.POST("/:name/start", config.JobHandler.StartJob)
+		jobRoutes.POST("/:name/stop", config.JobHandler.StopJob)
 	}
 
 	api.GET("/circuit-breaker/status", func(c *gin.Context) {
@@ -68,4 +85,21 @@ func SetupRoutes(router *gin.Engine, config RouteConfig) {
 		})
 		response.SendSuccess(c, resp)
 	})
+
+	api.GET("/database/stats", func(c *gin.Context) {
+		if config.Database == nil {
+			resp := response.Success(map[string]any{
+				"enabled": false,
+				"message": "Database statistics not available",
+			})
+			response.SendSuccess(c, resp)
+			return
+		}
+
+		resp := response.Success(map[string]any{
+			"queries": config.Database.QueryStats(),
+			"pools":   config.Database.PoolStats(),
+		})
+		response.SendSuccess(c, resp)
+	})
 }
@@ -9,11 +9,17 @@ import (
 	"insider-message-system/internal/application/services"
 	"insider-message-system/internal/application/usecases"
 	"insider-message-system/internal/domain"
+	"insider-message-system/internal/infrastructure/database"
+	"insider-message-system/internal/infrastructure/database/repos"
+	"insider-message-system/internal/infrastructure/election"
+	"insider-message-system/internal/infrastructure/notifier"
 	"insider-message-system/internal/interfaces/http/handlers"
 	"insider-message-system/pkg/circuitbreaker"
+	"insider-message-system/pkg/config"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
 )
 
 // Mock SchedulerService for safe ControlSchedulerUseCase
@@ -23,6 +29,27 @@ func (m *mockSchedulerService) Start(ctx context.Context) error
 func (m *mockSchedulerService) Stop() error                                             { return nil }
 func (m *mockSchedulerService) IsRunning() bool                                         { return true }
 func (m *mockSchedulerService) SetMessageProcessor(processor services.MessageProcessor) {}
+func (m *mockSchedulerService) SetNotifier(notifier notifier.Notifier)                  {}
+func (m *mockSchedulerService) SetElector(elector election.Elector)                     {}
+func (m *mockSchedulerService) SetBatchController(controller services.BatchController)  {}
+func (m *mockSchedulerService) SetSettingsRepository(repo repos.SchedulerSettings)      {}
+func (m *mockSchedulerService) SetStateRepository(repo repos.SchedulerState)            {}
+func (m *mockSchedulerService) SetDesiredState(ctx context.Context, state domain.SchedulerDesiredState) error {
+	return nil
+}
+func (m *mockSchedulerService) Reconcile(ctx context.Context) error    { return nil }
+func (m *mockSchedulerService) LoadSettings(ctx context.Context) error { return nil }
+func (m *mockSchedulerService) Settings() domain.SchedulerSettings     { return domain.SchedulerSettings{} }
+func (m *mockSchedulerService) UpdateSettings(ctx context.Context, settings domain.SchedulerSettings) error {
+	return nil
+}
+func (m *mockSchedulerService) RunOnce(ctx context.Context, batchSize int) (*domain.BatchResult, error) {
+	return &domain.BatchResult{}, nil
+}
+func (m *mockSchedulerService) RunOnceAsync(ctx context.Context, batchSize int) error { return nil }
+func (m *mockSchedulerService) Status() domain.SchedulerStatus {
+	return domain.SchedulerStatus{Running: true}
+}
 
 // Mock webhook client for testing
 type mockWebhookClient struct{}
@@ -160,3 +187,49 @@ func TestSetupRoutes_SchedulerRoutes(t *testing.T) {
 	// Should not be 404 (route exists)
 	assert.NotEqual(t, http.StatusNotFound, w.Code)
 }
+
+func TestSetupRoutes_DatabaseStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	db, err := database.NewConnectionWithDialector(config.DatabaseConfig{MaxOpenConnections: 1}, sqlite.Open(":memory:"))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	routeConfig := RouteConfig{
+		MessageHandler:   &handlers.MessageHandler{},
+		SchedulerHandler: newMockSchedulerHandler(),
+		Database:         db,
+		AuthKey:          "test-key",
+	}
+
+	SetupRoutes(router, routeConfig)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/database/stats", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "primary")
+	assert.Contains(t, w.Body.String(), "max_open_connections")
+}
+
+func TestSetupRoutes_WithoutDatabase(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	routeConfig := RouteConfig{
+		MessageHandler:   &handlers.MessageHandler{},
+		SchedulerHandler: newMockSchedulerHandler(),
+		AuthKey:          "test-key",
+	}
+
+	SetupRoutes(router, routeConfig)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/database/stats", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "not available")
+}
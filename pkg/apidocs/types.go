@@ -18,14 +18,18 @@ type MessageCreatedResponse struct {
 }
 
 type MessageData struct {
-	ID            uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	To            string     `json:"to" example:"+905551111111"`
-	Content       string     `json:"content" example:"Insider - Project"`
-	Status        string     `json:"status" example:"pending"`
-	CreatedAt     time.Time  `json:"created_at" example:"2024-01-15T10:30:00Z"`
-	SentAt        *time.Time `json:"sent_at,omitempty" example:"2024-01-15T10:35:00Z"`
-	MessageID     *string    `json:"message_id,omitempty" example:"msg_123456"`
-	FailureReason *string    `json:"failure_reason,omitempty"`
+	ID             uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	To             string     `json:"to" example:"+905551111111"`
+	Content        string     `json:"content" example:"Insider - Project"`
+	Status         string     `json:"status" example:"pending"`
+	CreatedAt      time.Time  `json:"created_at" example:"2024-01-15T10:30:00Z"`
+	SentAt         *time.Time `json:"sent_at,omitempty" example:"2024-01-15T10:35:00Z"`
+	FailedAt       *time.Time `json:"failed_at,omitempty"`
+	MessageID      *string    `json:"message_id,omitempty" example:"msg_123456"`
+	Provider       *string    `json:"provider,omitempty" example:"primary"`
+	FailureCode    *string    `json:"failure_code,omitempty" example:"transient"`
+	FailureReason  *string    `json:"failure_reason,omitempty"`
+	FailedAttempts int        `json:"failed_attempts,omitempty" example:"1"`
 }
 
 type MessagesListResponse struct {
@@ -50,6 +54,173 @@ type SchedulerData struct {
 	Message string `json:"message" example:"Scheduler started successfully"`
 }
 
+type SchedulerStatusResponse struct {
+	Status bool                `json:"status" example:"true"`
+	Msg    string              `json:"msg" example:"Request processed successfully"`
+	Data   SchedulerStatusData `json:"data"`
+}
+
+type SchedulerStatusData struct {
+	Status        string                     `json:"status" example:"running"`
+	Message       string                     `json:"message" example:"Scheduler is currently running"`
+	InstanceID    string                     `json:"instance_id" example:"api-7f9c-1"`
+	LeaderID      string                     `json:"leader_id,omitempty" example:"api-7f9c-1"`
+	DesiredState  *SchedulerDesiredStateData `json:"desired_state,omitempty"`
+	StartedAt     *time.Time                 `json:"started_at,omitempty" example:"2024-01-15T09:00:00Z"`
+	UptimeSeconds int64                      `json:"uptime_seconds,omitempty" example:"3600"`
+	NextRunAt     *time.Time                 `json:"next_run_at,omitempty" example:"2024-01-15T10:02:00Z"`
+	LastCycle     *SchedulerCycleData        `json:"last_cycle,omitempty"`
+	Cycles        []SchedulerCycleData       `json:"cycles,omitempty"`
+	Totals        SchedulerTotalsData        `json:"totals"`
+	BatchSizing   *BatchSizingData           `json:"batch_sizing,omitempty"`
+}
+
+type BatchSizingData struct {
+	BatchSize    int       `json:"batch_size" example:"40"`
+	MinBatchSize int       `json:"min_batch_size" example:"1"`
+	MaxBatchSize int       `json:"max_batch_size" example:"100"`
+	Decision     string    `json:"decision" example:"increase"`
+	Reason       string    `json:"reason" example:"backlog of 340 exceeds batch size 20"`
+	Backlog      int64     `json:"backlog" example:"340"`
+	LatencyMs    int64     `json:"latency_ms" example:"180"`
+	ErrorRate    float64   `json:"error_rate" example:"0"`
+	DecidedAt    time.Time `json:"decided_at" example:"2024-01-15T10:00:00Z"`
+}
+
+type SchedulerDesiredStateData struct {
+	Running   bool      `json:"running" example:"true"`
+	ChangedBy string    `json:"changed_by" example:"ops@example.com"`
+	Reason    string    `json:"reason,omitempty" example:"maintenance over"`
+	ChangedAt time.Time `json:"changed_at" example:"2024-01-15T09:00:00Z"`
+}
+
+type SchedulerCycleData struct {
+	Trigger    string    `json:"trigger" example:"tick"`
+	StartedAt  time.Time `json:"started_at" example:"2024-01-15T10:00:00Z"`
+	FinishedAt time.Time `json:"finished_at" example:"2024-01-15T10:00:01Z"`
+	DurationMs int64     `json:"duration_ms" example:"840"`
+	BatchSize  int       `json:"batch_size" example:"2"`
+	Attempts   int       `json:"attempts" example:"1"`
+	Fetched    int       `json:"fetched" example:"2"`
+	Sent       int       `json:"sent" example:"2"`
+	Failed     int       `json:"failed" example:"0"`
+	Skipped    int       `json:"skipped" example:"0"`
+	Deferred   int       `json:"deferred" example:"0"`
+	Unsent     int       `json:"unsent" example:"0"`
+	Errors     []string  `json:"errors,omitempty"`
+}
+
+type SchedulerTotalsData struct {
+	Cycles       int64 `json:"cycles" example:"42"`
+	FailedCycles int64 `json:"failed_cycles" example:"1"`
+	Fetched      int64 `json:"fetched" example:"80"`
+	Sent         int64 `json:"sent" example:"77"`
+	Failed       int64 `json:"failed" example:"2"`
+	Skipped      int64 `json:"skipped" example:"1"`
+	Deferred     int64 `json:"deferred" example:"0"`
+	Unsent       int64 `json:"unsent" example:"0"`
+}
+
+type SchedulerConfigResponse struct {
+	Status bool                `json:"status" example:"true"`
+	Msg    string              `json:"msg" example:"Request processed successfully"`
+	Data   SchedulerConfigData `json:"data"`
+}
+
+type SchedulerConfigData struct {
+	Interval   string `json:"interval" example:"2m0s"`
+	BatchSize  int    `json:"batch_size" example:"2"`
+	MaxRetries int    `json:"max_retries" example:"3"`
+	RetryDelay string `json:"retry_delay" example:"5s"`
+}
+
+type SchedulerRunResponse struct {
+	Status bool             `json:"status" example:"true"`
+	Msg    string           `json:"msg" example:"Request processed successfully"`
+	Data   SchedulerRunData `json:"data"`
+}
+
+type SchedulerRunData struct {
+	Mode       string          `json:"mode" example:"sync"`
+	BatchSize  int             `json:"batch_size" example:"100"`
+	Result     BatchResultData `json:"result"`
+	DurationMs int64           `json:"duration_ms" example:"840"`
+}
+
+type BatchResultData struct {
+	Fetched  int `json:"fetched" example:"10"`
+	Sent     int `json:"sent" example:"8"`
+	Failed   int `json:"failed" example:"1"`
+	Skipped  int `json:"skipped" example:"1"`
+	Deferred int `json:"deferred" example:"0"`
+	Unsent   int `json:"unsent" example:"0"`
+}
+
+type StatsResponse struct {
+	Status bool      `json:"status" example:"true"`
+	Msg    string    `json:"msg" example:"Request processed successfully"`
+	Data   StatsData `json:"data"`
+}
+
+type StatsData struct {
+	From       time.Time         `json:"from" example:"2024-01-14T00:00:00Z"`
+	To         time.Time         `json:"to" example:"2024-01-15T00:00:00Z"`
+	Interval   string            `json:"interval" example:"hour"`
+	Totals     map[string]int64  `json:"totals"`
+	TimeSeries []TimeSeriesPoint `json:"time_series"`
+	Latency    LatencyData       `json:"latency"`
+}
+
+type TimeSeriesPoint struct {
+	Bucket time.Time        `json:"bucket" example:"2024-01-14T10:00:00Z"`
+	Counts map[string]int64 `json:"counts"`
+}
+
+type LatencyData struct {
+	Count int64   `json:"count" example:"120"`
+	P50Ms float64 `json:"p50_ms" example:"61000"`
+	P95Ms float64 `json:"p95_ms" example:"118500"`
+	P99Ms float64 `json:"p99_ms" example:"120000"`
+}
+
+type JobListResponse struct {
+	Status bool            `json:"status" example:"true"`
+	Msg    string          `json:"msg" example:"Request processed successfully"`
+	Data   []JobStatusData `json:"data"`
+}
+
+type JobStatusResponse struct {
+	Status bool          `json:"status" example:"true"`
+	Msg    string        `json:"msg" example:"Request processed successfully"`
+	Data   JobStatusData `json:"data"`
+}
+
+type JobStatusData struct {
+	Name        string      `json:"name" example:"retention"`
+	Status      string      `json:"status" example:"running"`
+	LeaderOnly  bool        `json:"leader_only" example:"true"`
+	Interval    string      `json:"interval" example:"1h0m0s"`
+	Jitter      string      `json:"jitter" example:"5m0s"`
+	Overlap     string      `json:"overlap" example:"skip"`
+	Timeout     string      `json:"timeout" example:"30m0s"`
+	MaxAttempts int         `json:"max_attempts" example:"2"`
+	RetryDelay  string      `json:"retry_delay" example:"1m0s"`
+	InFlight    int         `json:"in_flight" example:"0"`
+	NextRunAt   *time.Time  `json:"next_run_at,omitempty" example:"2024-01-15T11:00:00Z"`
+	LastRun     *JobRunData `json:"last_run,omitempty"`
+	Runs        int64       `json:"runs" example:"12"`
+	Failures    int64       `json:"failures" example:"1"`
+	Skipped     int64       `json:"skipped" example:"0"`
+}
+
+type JobRunData struct {
+	StartedAt  time.Time `json:"started_at" example:"2024-01-15T10:00:00Z"`
+	FinishedAt time.Time `json:"finished_at" example:"2024-01-15T10:00:01Z"`
+	DurationMs int64     `json:"duration_ms" example:"840"`
+	Attempts   int       `json:"attempts" example:"1"`
+	Error      string    `json:"error,omitempty" example:"context deadline exceeded"`
+}
+
 type ErrorResponse struct {
 	Status bool      `json:"status" example:"false"`
 	Msg    string    `json:"msg" example:"Validation failed"`
@@ -52,6 +52,7 @@ type CircuitBreaker struct {
 
 	failureCount    int
 	lastFailureTime time.Time
+	openedAt        time.Time
 
 	successCount int
 
@@ -174,25 +175,42 @@ func (cb *CircuitBreaker) executeHalfOpen(ctx context.Context, fn func() error)
 
 func (cb *CircuitBreaker) executeOpen(ctx context.Context, fn func() error) error {
 	cb.mu.Lock()
-	defer cb.mu.Unlock()
 
-	if time.Since(cb.lastFailureTime) >= cb.config.Timeout {
+	if cb.state == StateOpen && cb.readyToProbe() {
 		cb.transitionToHalfOpen()
 		logger.Info("Circuit breaker transitioning to half-open state after timeout")
+	}
+
+	// The other states take the lock themselves, so it is released before handing
+	// over. Another caller may have moved the breaker on in the meantime.
+	switch cb.state {
+	case StateHalfOpen:
+		cb.mu.Unlock()
 		return cb.executeHalfOpen(ctx, fn)
+	case StateClosed:
+		cb.mu.Unlock()
+		return cb.executeClosed(ctx, fn)
 	}
+	defer cb.mu.Unlock()
 
 	cb.totalTimeouts++
 	logger.Warn("Circuit breaker is open, request rejected",
-		zap.Duration("time_since_last_failure", time.Since(cb.lastFailureTime)),
+		zap.Duration("time_since_opened", time.Since(cb.openedAt)),
 		zap.Duration("timeout", cb.config.Timeout))
 
 	return ErrCircuitOpen
 }
 
+// readyToProbe reports whether an open breaker has waited out its timeout, so the
+// next call is let through as a trial. It must be called with mu held.
+func (cb *CircuitBreaker) readyToProbe() bool {
+	return time.Since(cb.openedAt) >= cb.config.Timeout
+}
+
 func (cb *CircuitBreaker) transitionToOpen() {
 	if cb.state != StateOpen {
 		cb.state = StateOpen
+		cb.openedAt = time.Now()
 		cb.successCount = 0
 		cb.concurrentCalls = 0
 		logger.Warn("Circuit breaker transitioning to OPEN state",
@@ -220,9 +238,15 @@ func (cb *CircuitBreaker) transitionToClosed() {
 	}
 }
 
+// GetState returns the current state. An open breaker whose timeout has elapsed
+// reports StateHalfOpen, since the next call is let through as a trial.
 func (cb *CircuitBreaker) GetState() State {
 	cb.mu.RLock()
 	defer cb.mu.RUnlock()
+
+	if cb.state == StateOpen && cb.readyToProbe() {
+		return StateHalfOpen
+	}
 	return cb.state
 }
 
@@ -274,6 +298,7 @@ func (cb *CircuitBreaker) Reset() {
 	cb.totalSuccesses = 0
 	cb.totalTimeouts = 0
 	cb.lastFailureTime = time.Time{}
+	cb.openedAt = time.Time{}
 
 	logger.Info("Circuit breaker reset")
 }
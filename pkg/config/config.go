@@ -1,8 +1,17 @@
 package config
 
 import (
+	"insider-message-system/pkg/constants/enums/authstrategies"
+	"insider-message-system/pkg/constants/enums/databasedrivers"
+	"insider-message-system/pkg/constants/enums/electionbackends"
 	"insider-message-system/pkg/constants/enums/formattypes"
+	"insider-message-system/pkg/constants/enums/joboverlaps"
 	"insider-message-system/pkg/constants/enums/loglevels"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/notifiertypes"
+	"insider-message-system/pkg/constants/enums/ratelimitbackends"
+	"insider-message-system/pkg/constants/enums/routingstrategies"
+	"insider-message-system/pkg/constants/enums/sinktypes"
 	"os"
 	"strings"
 	"time"
@@ -18,6 +27,9 @@ type Config struct {
 	Scheduler      SchedulerConfig      `mapstructure:"scheduler"`
 	Logger         LoggerConfig         `mapstructure:"logger"`
 	CircuitBreaker CircuitBreakerConfig `mapstructure:"circuit_breaker"`
+	Retention      RetentionConfig      `mapstructure:"retention"`
+	Outbox         OutboxConfig         `mapstructure:"outbox"`
+	Jobs           JobsConfig           `mapstructure:"jobs"`
 }
 
 type ServerConfig struct {
@@ -28,11 +40,14 @@ type ServerConfig struct {
 }
 
 type DatabaseConfig struct {
-	Driver             string        `mapstructure:"driver"`
-	DSN                string        `mapstructure:"dsn"`
-	MaxOpenConnections int           `mapstructure:"max_open_connections"`
-	MaxIdleConnections int           `mapstructure:"max_idle_connections"`
-	ConnMaxLifetime    time.Duration `mapstructure:"conn_max_lifetime"`
+	Driver                     databasedrivers.DatabaseDriver `mapstructure:"driver"`
+	DSN                        string                         `mapstructure:"dsn"`
+	MaxOpenConnections         int                            `mapstructure:"max_open_connections"`
+	MaxIdleConnections         int                            `mapstructure:"max_idle_connections"`
+	ConnMaxLifetime            time.Duration                  `mapstructure:"conn_max_lifetime"`
+	Replicas                   []string                       `mapstructure:"replicas"`
+	ReplicaHealthCheckInterval time.Duration                  `mapstructure:"replica_health_check_interval"`
+	SlowQueryThreshold         time.Duration                  `mapstructure:"slow_query_threshold"`
 }
 
 type RedisConfig struct {
@@ -43,17 +58,196 @@ type RedisConfig struct {
 }
 
 type WebhookConfig struct {
-	URL     string        `mapstructure:"url"`
-	AuthKey string        `mapstructure:"auth_key"`
-	Timeout time.Duration `mapstructure:"timeout"`
+	URL       string          `mapstructure:"url"`
+	AuthKey   string          `mapstructure:"auth_key"`
+	Auth      AuthConfig      `mapstructure:"auth"`
+	Timeout   time.Duration   `mapstructure:"timeout"`
+	TLS       TLSConfig       `mapstructure:"tls"`
+	ProxyURL  string          `mapstructure:"proxy_url"`
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	Signing   SigningConfig   `mapstructure:"signing"`
+	Retry     RetryConfig     `mapstructure:"retry"`
+	Mapping   MappingConfig   `mapstructure:"mapping"`
+	// Providers replaces URL and AuthKey with several named providers when set.
+	Providers []ProviderConfig                  `mapstructure:"providers"`
+	Routing   routingstrategies.RoutingStrategy `mapstructure:"routing"`
+}
+
+// ProviderConfig is a named SMS provider. A message goes to the providers whose
+// Prefixes match its recipient, then to those without prefixes, in the order the
+// routing strategy gives: by ascending Priority, or with the first provider drawn
+// in proportion to Weight. Timeout, ProxyURL, CircuitBreaker and RateLimit default
+// to the top-level settings, but every provider gets a breaker and a rate limit of
+// its own.
+type ProviderConfig struct {
+	Name           string                `mapstructure:"name"`
+	URL            string                `mapstructure:"url"`
+	AuthKey        string                `mapstructure:"auth_key"`
+	Auth           AuthConfig            `mapstructure:"auth"`
+	Timeout        time.Duration         `mapstructure:"timeout"`
+	TLS            TLSConfig             `mapstructure:"tls"`
+	ProxyURL       string                `mapstructure:"proxy_url"`
+	Priority       int                   `mapstructure:"priority"`
+	Weight         int                   `mapstructure:"weight"`
+	Prefixes       []string              `mapstructure:"prefixes"`
+	CircuitBreaker *CircuitBreakerConfig `mapstructure:"circuit_breaker"`
+	RateLimit      *RateLimitConfig      `mapstructure:"rate_limit"`
+	Mapping        MappingConfig         `mapstructure:"mapping"`
+}
+
+// AuthConfig decides how requests to a provider are authenticated. The header
+// type, the default, sends auth_key in Header (x-ins-auth-key unless set); basic
+// sends Username and Password; oauth2 sends a bearer token obtained from TokenURL
+// with the client credentials grant, cached and renewed RefreshBefore it expires
+// (one minute unless set).
+type AuthConfig struct {
+	Type          authstrategies.AuthStrategy `mapstructure:"type"`
+	Header        string                      `mapstructure:"header"`
+	Username      string                      `mapstructure:"username"`
+	Password      string                      `mapstructure:"password"`
+	TokenURL      string                      `mapstructure:"token_url"`
+	ClientID      string                      `mapstructure:"client_id"`
+	ClientSecret  string                      `mapstructure:"client_secret"`
+	Scopes        []string                    `mapstructure:"scopes"`
+	RefreshBefore time.Duration               `mapstructure:"refresh_before"`
+}
+
+// TLSConfig secures the connection to a provider. CertFile and KeyFile hold a
+// client certificate for mutual TLS, read again when they change on disk. CAFile
+// holds a PEM bundle of the CAs trusted instead of the system's. MinVersion is the
+// lowest TLS version accepted, "1.2" unless set.
+type TLSConfig struct {
+	CertFile   string `mapstructure:"cert_file"`
+	KeyFile    string `mapstructure:"key_file"`
+	CAFile     string `mapstructure:"ca_file"`
+	MinVersion string `mapstructure:"min_version"`
+}
+
+// MappingConfig describes a provider's request and response format. A request
+// without fields sends the built-in to and content fields, as JSON unless another
+// content type is set, and a response that reads nothing has its message ID read
+// from messageId.
+type MappingConfig struct {
+	Request  RequestMappingConfig  `mapstructure:"request"`
+	Response ResponseMappingConfig `mapstructure:"response"`
+}
+
+// RequestMappingConfig builds the request body from Fields in ContentType, either
+// application/json, where a dotted path nests objects, or
+// application/x-www-form-urlencoded.
+type RequestMappingConfig struct {
+	ContentType string               `mapstructure:"content_type"`
+	Fields      []FieldMappingConfig `mapstructure:"fields"`
+}
+
+// FieldMappingConfig sets the body field at Path to Value, a Go template over the
+// message's To and Content, such as "{{.To}}".
+type FieldMappingConfig struct {
+	Path  string `mapstructure:"path"`
+	Value string `mapstructure:"value"`
+}
+
+// ResponseMappingConfig reads the provider message ID from the JSON body path
+// MessageID, or from the header MessageIDHeader, and the delivery status from the
+// body path Status. With AcceptedStatuses, any other status fails the send.
+type ResponseMappingConfig struct {
+	MessageID        string   `mapstructure:"message_id"`
+	MessageIDHeader  string   `mapstructure:"message_id_header"`
+	Status           string   `mapstructure:"status"`
+	AcceptedStatuses []string `mapstructure:"accepted_statuses"`
+}
+
+// RetryConfig decides what happens to a message after a failed send. Transient
+// failures (5xx, timeouts, network errors) are retried after Backoff until the
+// message has failed MaxAttempts times. Throttled sends (429) are retried after
+// the response's Retry-After, or Backoff without one, capped at MaxRetryAfter,
+// and do not count as attempts. Permanent failures (other 4xx) are not retried.
+type RetryConfig struct {
+	MaxAttempts   int           `mapstructure:"max_attempts"`
+	Backoff       time.Duration `mapstructure:"backoff"`
+	MaxRetryAfter time.Duration `mapstructure:"max_retry_after"`
+}
+
+// SigningConfig signs every webhook request body and timestamp with HMAC-SHA256
+// under each of Secrets. Listing a new secret next to the old one lets receivers
+// switch over before the old one is removed.
+type SigningConfig struct {
+	Enabled bool     `mapstructure:"enabled"`
+	Secrets []string `mapstructure:"secrets"`
+}
+
+// RateLimitConfig limits webhook sends to Rate per second with bursts of up to
+// Burst. With the Redis backend the limit is shared by every instance using Key;
+// while Redis is unreachable each instance allows FallbackRate per second on its
+// own, or Rate when FallbackRate is zero. A send that cannot get a permit within
+// MaxWait is deferred to a later cycle.
+type RateLimitConfig struct {
+	Enabled      bool                               `mapstructure:"enabled"`
+	Backend      ratelimitbackends.RateLimitBackend `mapstructure:"backend"`
+	Rate         float64                            `mapstructure:"rate"`
+	Burst        int                                `mapstructure:"burst"`
+	MaxWait      time.Duration                      `mapstructure:"max_wait"`
+	Key          string                             `mapstructure:"key"`
+	FallbackRate float64                            `mapstructure:"fallback_rate"`
 }
 
 type SchedulerConfig struct {
-	Interval   time.Duration `mapstructure:"interval"`
-	BatchSize  int           `mapstructure:"batch_size"`
-	MaxRetries int           `mapstructure:"max_retries"`
-	RetryDelay time.Duration `mapstructure:"retry_delay"`
-	AutoStart  bool          `mapstructure:"auto_start"`
+	Interval          time.Duration        `mapstructure:"interval"`
+	BatchSize         int                  `mapstructure:"batch_size"`
+	Concurrency       int                  `mapstructure:"concurrency"`
+	ClaimLease        time.Duration        `mapstructure:"claim_lease"`
+	DrainTimeout      time.Duration        `mapstructure:"drain_timeout"`
+	MaxRetries        int                  `mapstructure:"max_retries"`
+	RetryDelay        time.Duration        `mapstructure:"retry_delay"`
+	AutoStart         bool                 `mapstructure:"auto_start"`
+	PersistSettings   bool                 `mapstructure:"persist_settings"`
+	PersistState      bool                 `mapstructure:"persist_state"`
+	ReconcileInterval time.Duration        `mapstructure:"reconcile_interval"`
+	HistorySize       int                  `mapstructure:"history_size"`
+	InstanceID        string               `mapstructure:"instance_id"`
+	Wakeup            WakeupConfig         `mapstructure:"wakeup"`
+	Cron              CronConfig           `mapstructure:"cron"`
+	LeaderElection    LeaderElectionConfig `mapstructure:"leader_election"`
+	AdaptiveBatch     AdaptiveBatchConfig  `mapstructure:"adaptive_batch"`
+}
+
+// AdaptiveBatchConfig makes the scheduler size each periodic cycle between
+// MinBatchSize and MaxBatchSize: larger while the pending backlog exceeds the
+// batch size and sends stay within TargetLatency and MaxErrorRate, smaller as
+// soon as they do not.
+type AdaptiveBatchConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	MinBatchSize  int           `mapstructure:"min_batch_size"`
+	MaxBatchSize  int           `mapstructure:"max_batch_size"`
+	TargetLatency time.Duration `mapstructure:"target_latency"`
+	MaxErrorRate  float64       `mapstructure:"max_error_rate"`
+}
+
+// LeaderElectionConfig makes replicas elect one instance to run the scheduler.
+// The leader renews its lock every RenewInterval; with the Redis backend the
+// lock expires TTL after the last renewal.
+type LeaderElectionConfig struct {
+	Enabled       bool                             `mapstructure:"enabled"`
+	Backend       electionbackends.ElectionBackend `mapstructure:"backend"`
+	Key           string                           `mapstructure:"key"`
+	TTL           time.Duration                    `mapstructure:"ttl"`
+	RenewInterval time.Duration                    `mapstructure:"renew_interval"`
+}
+
+// CronConfig replaces the fixed scheduler interval with a cron schedule when
+// Expression is set. Fire times are computed in Timezone, and days listed in
+// HolidaysFile are skipped.
+type CronConfig struct {
+	Expression   string `mapstructure:"expression"`
+	Timezone     string `mapstructure:"timezone"`
+	HolidaysFile string `mapstructure:"holidays_file"`
+}
+
+type WakeupConfig struct {
+	Enabled  bool                       `mapstructure:"enabled"`
+	Backend  notifiertypes.NotifierType `mapstructure:"backend"`
+	Channel  string                     `mapstructure:"channel"`
+	Debounce time.Duration              `mapstructure:"debounce"`
 }
 
 type LoggerConfig struct {
@@ -69,6 +263,46 @@ type CircuitBreakerConfig struct {
 	HalfOpenAfter time.Duration `mapstructure:"half_open_after"`
 }
 
+type RetentionConfig struct {
+	Enabled    bool                                          `mapstructure:"enabled"`
+	Interval   time.Duration                                 `mapstructure:"interval"`
+	BatchSize  int                                           `mapstructure:"batch_size"`
+	ArchiveDir string                                        `mapstructure:"archive_dir"`
+	Policies   map[messagestatus.MessageStatus]time.Duration `mapstructure:"policies"`
+}
+
+type OutboxConfig struct {
+	Enabled      bool               `mapstructure:"enabled"`
+	PollInterval time.Duration      `mapstructure:"poll_interval"`
+	BatchSize    int                `mapstructure:"batch_size"`
+	Cleanup      bool               `mapstructure:"cleanup"`
+	Sinks        []OutboxSinkConfig `mapstructure:"sinks"`
+}
+
+type OutboxSinkConfig struct {
+	Name    string             `mapstructure:"name"`
+	Type    sinktypes.SinkType `mapstructure:"type"`
+	URL     string             `mapstructure:"url"`
+	Headers map[string]string  `mapstructure:"headers"`
+	Timeout time.Duration      `mapstructure:"timeout"`
+	Path    string             `mapstructure:"path"`
+}
+
+// JobsConfig holds the run policy of each background job. The interval of a job
+// is set by the feature it runs, such as retention.interval.
+type JobsConfig struct {
+	Retention   JobConfig `mapstructure:"retention"`
+	OutboxRelay JobConfig `mapstructure:"outbox_relay"`
+}
+
+type JobConfig struct {
+	Jitter      time.Duration          `mapstructure:"jitter"`
+	Overlap     joboverlaps.JobOverlap `mapstructure:"overlap"`
+	Timeout     time.Duration          `mapstructure:"timeout"`
+	MaxAttempts int                    `mapstructure:"max_attempts"`
+	RetryDelay  time.Duration          `mapstructure:"retry_delay"`
+}
+
 func Load() (*Config, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
@@ -96,28 +330,128 @@ func Load() (*Config, error) {
 	return &config, nil
 }
 
+// DefaultProviderName names the provider built from webhook.url when no providers
+// are configured.
+const DefaultProviderName = "default"
+
+// WebhookProviders returns the configured providers with the top-level defaults
+// filled in, or a single provider named DefaultProviderName built from webhook.url
+// when none are configured. A provider's Redis rate limit key defaults to the
+// top-level key suffixed with its name, so that providers never share a bucket.
+func (c *Config) WebhookProviders() []ProviderConfig {
+	if len(c.Webhook.Providers) == 0 {
+		rateLimit := c.Webhook.RateLimit
+		circuitBreaker := c.CircuitBreaker
+		return []ProviderConfig{{
+			Name:           DefaultProviderName,
+			URL:            c.Webhook.URL,
+			AuthKey:        c.Webhook.AuthKey,
+			Auth:           c.Webhook.Auth,
+			Timeout:        c.Webhook.Timeout,
+			TLS:            c.Webhook.TLS,
+			ProxyURL:       c.Webhook.ProxyURL,
+			CircuitBreaker: &circuitBreaker,
+			RateLimit:      &rateLimit,
+			Mapping:        c.Webhook.Mapping,
+		}}
+	}
+
+	providers := make([]ProviderConfig, len(c.Webhook.Providers))
+	for i, provider := range c.Webhook.Providers {
+		if provider.Timeout == 0 {
+			provider.Timeout = c.Webhook.Timeout
+		}
+		if provider.ProxyURL == "" {
+			provider.ProxyURL = c.Webhook.ProxyURL
+		}
+
+		circuitBreaker := c.CircuitBreaker
+		if provider.CircuitBreaker != nil {
+			circuitBreaker = *provider.CircuitBreaker
+		}
+		provider.CircuitBreaker = &circuitBreaker
+
+		rateLimit := c.Webhook.RateLimit
+		rateLimit.Key = ""
+		if provider.RateLimit != nil {
+			rateLimit = *provider.RateLimit
+			if rateLimit.Backend == "" {
+				rateLimit.Backend = c.Webhook.RateLimit.Backend
+			}
+			if rateLimit.MaxWait == 0 {
+				rateLimit.MaxWait = c.Webhook.RateLimit.MaxWait
+			}
+		}
+		if rateLimit.Key == "" {
+			rateLimit.Key = c.Webhook.RateLimit.Key + ":" + provider.Name
+		}
+		provider.RateLimit = &rateLimit
+
+		providers[i] = provider
+	}
+	return providers
+}
+
 func setupDefaults() {
 	viper.SetDefault("server.port", "8080")
 	viper.SetDefault("server.host", "0.0.0.0")
 	viper.SetDefault("server.read_timeout", "30s")
 	viper.SetDefault("server.write_timeout", "30s")
 
-	viper.SetDefault("database.driver", "postgres")
+	viper.SetDefault("database.driver", string(databasedrivers.Postgres))
 	viper.SetDefault("database.max_open_connections", 25)
 	viper.SetDefault("database.max_idle_connections", 5)
 	viper.SetDefault("database.conn_max_lifetime", "15m")
+	viper.SetDefault("database.replica_health_check_interval", "10s")
+	viper.SetDefault("database.slow_query_threshold", "200ms")
 
 	viper.SetDefault("redis.host", "localhost")
 	viper.SetDefault("redis.port", "6379")
 	viper.SetDefault("redis.db", 0)
 
 	viper.SetDefault("webhook.timeout", "30s")
+	viper.SetDefault("webhook.rate_limit.enabled", false)
+	viper.SetDefault("webhook.rate_limit.backend", string(ratelimitbackends.Redis))
+	viper.SetDefault("webhook.rate_limit.rate", 10)
+	viper.SetDefault("webhook.rate_limit.burst", 10)
+	viper.SetDefault("webhook.rate_limit.max_wait", "1s")
+	viper.SetDefault("webhook.rate_limit.key", "insider:webhook:rate_limit")
+	viper.SetDefault("webhook.rate_limit.fallback_rate", 0)
+	viper.SetDefault("webhook.signing.enabled", false)
+	viper.SetDefault("webhook.retry.max_attempts", 3)
+	viper.SetDefault("webhook.routing", string(routingstrategies.Priority))
+	viper.SetDefault("webhook.retry.backoff", "1m")
+	viper.SetDefault("webhook.retry.max_retry_after", "1h")
 
 	viper.SetDefault("scheduler.interval", "2m")
 	viper.SetDefault("scheduler.batch_size", 2)
+	viper.SetDefault("scheduler.concurrency", 4)
+	viper.SetDefault("scheduler.claim_lease", "5m")
+	viper.SetDefault("scheduler.drain_timeout", "30s")
 	viper.SetDefault("scheduler.max_retries", 3)
 	viper.SetDefault("scheduler.retry_delay", "5s")
 	viper.SetDefault("scheduler.auto_start", false)
+	viper.SetDefault("scheduler.persist_settings", false)
+	viper.SetDefault("scheduler.persist_state", true)
+	viper.SetDefault("scheduler.reconcile_interval", "10s")
+	viper.SetDefault("scheduler.history_size", 20)
+	viper.SetDefault("scheduler.wakeup.enabled", true)
+	viper.SetDefault("scheduler.wakeup.backend", string(notifiertypes.Local))
+	viper.SetDefault("scheduler.wakeup.channel", "insider:scheduler:wakeup")
+	viper.SetDefault("scheduler.wakeup.debounce", "500ms")
+	viper.SetDefault("scheduler.cron.expression", "")
+	viper.SetDefault("scheduler.cron.timezone", "UTC")
+	viper.SetDefault("scheduler.cron.holidays_file", "")
+	viper.SetDefault("scheduler.leader_election.enabled", false)
+	viper.SetDefault("scheduler.leader_election.backend", string(electionbackends.Redis))
+	viper.SetDefault("scheduler.leader_election.key", "insider:scheduler:leader")
+	viper.SetDefault("scheduler.leader_election.ttl", "15s")
+	viper.SetDefault("scheduler.leader_election.renew_interval", "5s")
+	viper.SetDefault("scheduler.adaptive_batch.enabled", false)
+	viper.SetDefault("scheduler.adaptive_batch.min_batch_size", 1)
+	viper.SetDefault("scheduler.adaptive_batch.max_batch_size", 100)
+	viper.SetDefault("scheduler.adaptive_batch.target_latency", "1s")
+	viper.SetDefault("scheduler.adaptive_batch.max_error_rate", 0.1)
 
 	viper.SetDefault("logger.level", loglevels.Info)
 	viper.SetDefault("logger.format", formattypes.FormatJSON)
@@ -127,6 +461,32 @@ func setupDefaults() {
 	viper.SetDefault("circuit_breaker.failure_rate", 0.5)
 	viper.SetDefault("circuit_breaker.min_requests", 10)
 	viper.SetDefault("circuit_breaker.half_open_after", "10s")
+
+	viper.SetDefault("retention.enabled", false)
+	viper.SetDefault("retention.interval", "1h")
+	viper.SetDefault("retention.batch_size", 500)
+	viper.SetDefault("retention.archive_dir", "./archive")
+	viper.SetDefault("retention.policies", map[string]string{
+		string(messagestatus.Sent):   "2160h",
+		string(messagestatus.Failed): "4320h",
+	})
+
+	viper.SetDefault("outbox.enabled", false)
+	viper.SetDefault("outbox.poll_interval", "1s")
+	viper.SetDefault("outbox.batch_size", 100)
+	viper.SetDefault("outbox.cleanup", true)
+
+	viper.SetDefault("jobs.retention.jitter", "5m")
+	viper.SetDefault("jobs.retention.overlap", joboverlaps.Skip)
+	viper.SetDefault("jobs.retention.timeout", "30m")
+	viper.SetDefault("jobs.retention.max_attempts", 2)
+	viper.SetDefault("jobs.retention.retry_delay", "1m")
+
+	viper.SetDefault("jobs.outbox_relay.jitter", "0s")
+	viper.SetDefault("jobs.outbox_relay.overlap", joboverlaps.Skip)
+	viper.SetDefault("jobs.outbox_relay.timeout", "30s")
+	viper.SetDefault("jobs.outbox_relay.max_attempts", 1)
+	viper.SetDefault("jobs.outbox_relay.retry_delay", "1s")
 }
 
 func setupEnvironmentVariables() {
@@ -134,15 +494,59 @@ func setupEnvironmentVariables() {
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.SetEnvPrefix("")
 
+	viper.BindEnv("database.driver", "DATABASE_DRIVER")
 	viper.BindEnv("database.dsn", "DATABASE_DSN")
+	viper.BindEnv("database.replicas", "DATABASE_REPLICAS")
+	viper.BindEnv("database.slow_query_threshold", "DATABASE_SLOW_QUERY_THRESHOLD")
 	viper.BindEnv("redis.host", "REDIS_HOST")
 	viper.BindEnv("redis.port", "REDIS_PORT")
 	viper.BindEnv("redis.password", "REDIS_PASSWORD")
 	viper.BindEnv("webhook.url", "WEBHOOK_URL")
 	viper.BindEnv("webhook.auth_key", "WEBHOOK_AUTH_KEY")
+	viper.BindEnv("webhook.auth.type", "WEBHOOK_AUTH_TYPE")
+	viper.BindEnv("webhook.auth.username", "WEBHOOK_AUTH_USERNAME")
+	viper.BindEnv("webhook.auth.password", "WEBHOOK_AUTH_PASSWORD")
+	viper.BindEnv("webhook.auth.token_url", "WEBHOOK_AUTH_TOKEN_URL")
+	viper.BindEnv("webhook.auth.client_id", "WEBHOOK_AUTH_CLIENT_ID")
+	viper.BindEnv("webhook.auth.client_secret", "WEBHOOK_AUTH_CLIENT_SECRET")
+	viper.BindEnv("webhook.tls.cert_file", "WEBHOOK_TLS_CERT_FILE")
+	viper.BindEnv("webhook.tls.key_file", "WEBHOOK_TLS_KEY_FILE")
+	viper.BindEnv("webhook.tls.ca_file", "WEBHOOK_TLS_CA_FILE")
+	viper.BindEnv("webhook.tls.min_version", "WEBHOOK_TLS_MIN_VERSION")
+	viper.BindEnv("webhook.proxy_url", "WEBHOOK_PROXY_URL")
+	viper.BindEnv("webhook.rate_limit.enabled", "WEBHOOK_RATE_LIMIT_ENABLED")
+	viper.BindEnv("webhook.rate_limit.backend", "WEBHOOK_RATE_LIMIT_BACKEND")
+	viper.BindEnv("webhook.rate_limit.rate", "WEBHOOK_RATE_LIMIT_RATE")
+	viper.BindEnv("webhook.rate_limit.burst", "WEBHOOK_RATE_LIMIT_BURST")
+	viper.BindEnv("webhook.rate_limit.fallback_rate", "WEBHOOK_RATE_LIMIT_FALLBACK_RATE")
+	viper.BindEnv("webhook.signing.enabled", "WEBHOOK_SIGNING_ENABLED")
+	viper.BindEnv("webhook.signing.secrets", "WEBHOOK_SIGNING_SECRETS")
+	viper.BindEnv("webhook.routing", "WEBHOOK_ROUTING")
+	viper.BindEnv("webhook.retry.max_attempts", "WEBHOOK_RETRY_MAX_ATTEMPTS")
+	viper.BindEnv("webhook.retry.backoff", "WEBHOOK_RETRY_BACKOFF")
+	viper.BindEnv("webhook.retry.max_retry_after", "WEBHOOK_RETRY_MAX_RETRY_AFTER")
 	viper.BindEnv("scheduler.auto_start", "SCHEDULER_AUTO_START")
 	viper.BindEnv("scheduler.interval", "SCHEDULER_INTERVAL")
 	viper.BindEnv("scheduler.batch_size", "SCHEDULER_BATCH_SIZE")
+	viper.BindEnv("scheduler.concurrency", "SCHEDULER_CONCURRENCY")
+	viper.BindEnv("scheduler.claim_lease", "SCHEDULER_CLAIM_LEASE")
+	viper.BindEnv("scheduler.drain_timeout", "SCHEDULER_DRAIN_TIMEOUT")
+	viper.BindEnv("scheduler.persist_settings", "SCHEDULER_PERSIST_SETTINGS")
+	viper.BindEnv("scheduler.persist_state", "SCHEDULER_PERSIST_STATE")
+	viper.BindEnv("scheduler.reconcile_interval", "SCHEDULER_RECONCILE_INTERVAL")
+	viper.BindEnv("scheduler.instance_id", "SCHEDULER_INSTANCE_ID")
+	viper.BindEnv("scheduler.wakeup.enabled", "SCHEDULER_WAKEUP_ENABLED")
+	viper.BindEnv("scheduler.wakeup.backend", "SCHEDULER_WAKEUP_BACKEND")
+	viper.BindEnv("scheduler.cron.expression", "SCHEDULER_CRON_EXPRESSION")
+	viper.BindEnv("scheduler.cron.timezone", "SCHEDULER_CRON_TIMEZONE")
+	viper.BindEnv("scheduler.cron.holidays_file", "SCHEDULER_CRON_HOLIDAYS_FILE")
+	viper.BindEnv("scheduler.leader_election.enabled", "SCHEDULER_LEADER_ELECTION_ENABLED")
+	viper.BindEnv("scheduler.leader_election.backend", "SCHEDULER_LEADER_ELECTION_BACKEND")
+	viper.BindEnv("scheduler.adaptive_batch.enabled", "SCHEDULER_ADAPTIVE_BATCH_ENABLED")
+	viper.BindEnv("scheduler.adaptive_batch.min_batch_size", "SCHEDULER_ADAPTIVE_BATCH_MIN_BATCH_SIZE")
+	viper.BindEnv("scheduler.adaptive_batch.max_batch_size", "SCHEDULER_ADAPTIVE_BATCH_MAX_BATCH_SIZE")
+	viper.BindEnv("scheduler.adaptive_batch.target_latency", "SCHEDULER_ADAPTIVE_BATCH_TARGET_LATENCY")
+	viper.BindEnv("scheduler.adaptive_batch.max_error_rate", "SCHEDULER_ADAPTIVE_BATCH_MAX_ERROR_RATE")
 	viper.BindEnv("server.port", "SERVER_PORT")
 	viper.BindEnv("logger.level", "LOG_LEVEL")
 	viper.BindEnv("logger.format", "LOG_FORMAT")
@@ -151,6 +555,12 @@ func setupEnvironmentVariables() {
 	viper.BindEnv("circuit_breaker.failure_rate", "CIRCUIT_BREAKER_FAILURE_RATE")
 	viper.BindEnv("circuit_breaker.min_requests", "CIRCUIT_BREAKER_MIN_REQUESTS")
 	viper.BindEnv("circuit_breaker.half_open_after", "CIRCUIT_BREAKER_HALF_OPEN_AFTER")
+	viper.BindEnv("retention.enabled", "RETENTION_ENABLED")
+	viper.BindEnv("retention.interval", "RETENTION_INTERVAL")
+	viper.BindEnv("retention.archive_dir", "RETENTION_ARCHIVE_DIR")
+	viper.BindEnv("outbox.enabled", "OUTBOX_ENABLED")
+	viper.BindEnv("jobs.retention.timeout", "JOBS_RETENTION_TIMEOUT")
+	viper.BindEnv("jobs.outbox_relay.timeout", "JOBS_OUTBOX_RELAY_TIMEOUT")
 }
 
 func setupContainerDefaults() {
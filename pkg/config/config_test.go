@@ -3,6 +3,17 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
+
+	"insider-message-system/pkg/constants/enums/authstrategies"
+	"insider-message-system/pkg/constants/enums/databasedrivers"
+	"insider-message-system/pkg/constants/enums/electionbackends"
+	"insider-message-system/pkg/constants/enums/formattypes"
+	"insider-message-system/pkg/constants/enums/joboverlaps"
+	"insider-message-system/pkg/constants/enums/loglevels"
+	"insider-message-system/pkg/constants/enums/messagestatus"
+	"insider-message-system/pkg/constants/enums/ratelimitbackends"
+	"insider-message-system/pkg/constants/enums/routingstrategies"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -29,7 +40,296 @@ func TestLoggerConfigStruct(t *testing.T) {
 		Format:     "json",
 		OutputPath: "stdout",
 	}
-	assert.Equal(t, "info", cfg.Level)
-	assert.Equal(t, "json", cfg.Format)
+	assert.Equal(t, loglevels.Info, cfg.Level)
+	assert.Equal(t, formattypes.FormatJSON, cfg.Format)
 	assert.Equal(t, "stdout", cfg.OutputPath)
 }
+
+func TestRetentionDefaults(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+	assert.False(t, cfg.Retention.Enabled)
+	assert.Equal(t, time.Hour, cfg.Retention.Interval)
+	assert.Equal(t, 500, cfg.Retention.BatchSize)
+	assert.Equal(t, 90*24*time.Hour, cfg.Retention.Policies[messagestatus.Sent])
+	assert.Equal(t, 180*24*time.Hour, cfg.Retention.Policies[messagestatus.Failed])
+}
+
+func TestJobsDefaults(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+	assert.Equal(t, JobConfig{
+		Jitter:      5 * time.Minute,
+		Overlap:     joboverlaps.Skip,
+		Timeout:     30 * time.Minute,
+		MaxAttempts: 2,
+		RetryDelay:  time.Minute,
+	}, cfg.Jobs.Retention)
+	assert.Equal(t, JobConfig{
+		Overlap:     joboverlaps.Skip,
+		Timeout:     30 * time.Second,
+		MaxAttempts: 1,
+		RetryDelay:  time.Second,
+	}, cfg.Jobs.OutboxRelay)
+}
+
+func TestSchedulerCronDefaults(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+	assert.Empty(t, cfg.Scheduler.Cron.Expression)
+	assert.Equal(t, "UTC", cfg.Scheduler.Cron.Timezone)
+	assert.Empty(t, cfg.Scheduler.Cron.HolidaysFile)
+}
+
+func TestSchedulerLeaderElectionDefaults(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+	assert.False(t, cfg.Scheduler.LeaderElection.Enabled)
+	assert.Equal(t, electionbackends.Redis, cfg.Scheduler.LeaderElection.Backend)
+	assert.Equal(t, 15*time.Second, cfg.Scheduler.LeaderElection.TTL)
+	assert.Equal(t, 5*time.Second, cfg.Scheduler.LeaderElection.RenewInterval)
+}
+
+func TestWebhookRateLimitDefaults(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+	assert.False(t, cfg.Webhook.RateLimit.Enabled)
+	assert.Equal(t, ratelimitbackends.Redis, cfg.Webhook.RateLimit.Backend)
+	assert.Equal(t, 10.0, cfg.Webhook.RateLimit.Rate)
+	assert.Equal(t, 10, cfg.Webhook.RateLimit.Burst)
+	assert.Equal(t, time.Second, cfg.Webhook.RateLimit.MaxWait)
+	assert.Zero(t, cfg.Webhook.RateLimit.FallbackRate)
+}
+
+func TestWebhookSigningFromEnv(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+	t.Setenv("WEBHOOK_SIGNING_ENABLED", "true")
+	t.Setenv("WEBHOOK_SIGNING_SECRETS", "new-secret,old-secret")
+	setupEnvironmentVariables()
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+	assert.True(t, cfg.Webhook.Signing.Enabled)
+	assert.Equal(t, []string{"new-secret", "old-secret"}, cfg.Webhook.Signing.Secrets)
+}
+
+func TestWebhookRetryDefaults(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+	t.Setenv("WEBHOOK_RETRY_MAX_ATTEMPTS", "5")
+	setupEnvironmentVariables()
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+	assert.Equal(t, 5, cfg.Webhook.Retry.MaxAttempts)
+	assert.Equal(t, time.Minute, cfg.Webhook.Retry.Backoff)
+	assert.Equal(t, time.Hour, cfg.Webhook.Retry.MaxRetryAfter)
+}
+
+func TestWebhookProviders_DefaultsToWebhookURL(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+	viper.Set("webhook.url", "https://sms.example.com/send")
+	viper.Set("webhook.auth_key", "key")
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+	assert.Equal(t, routingstrategies.Priority, cfg.Webhook.Routing)
+
+	providers := cfg.WebhookProviders()
+	assert.Len(t, providers, 1)
+	assert.Equal(t, DefaultProviderName, providers[0].Name)
+	assert.Equal(t, "https://sms.example.com/send", providers[0].URL)
+	assert.Equal(t, "key", providers[0].AuthKey)
+	assert.Equal(t, 30*time.Second, providers[0].Timeout)
+	assert.Equal(t, cfg.CircuitBreaker, *providers[0].CircuitBreaker)
+	assert.Equal(t, cfg.Webhook.RateLimit, *providers[0].RateLimit)
+}
+
+func TestWebhookProviders_InheritTopLevelSettings(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+	viper.Set("webhook.providers", []map[string]any{
+		{"name": "primary", "url": "https://primary.example.com", "prefixes": []string{"+90"}},
+		{
+			"name":            "backup",
+			"url":             "https://backup.example.com",
+			"timeout":         "5s",
+			"priority":        1,
+			"circuit_breaker": map[string]any{"enabled": false},
+			"rate_limit":      map[string]any{"enabled": true, "rate": 2, "burst": 4},
+		},
+	})
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+
+	providers := cfg.WebhookProviders()
+	assert.Len(t, providers, 2)
+
+	primary := providers[0]
+	assert.Equal(t, []string{"+90"}, primary.Prefixes)
+	assert.Equal(t, 30*time.Second, primary.Timeout)
+	assert.Equal(t, cfg.CircuitBreaker, *primary.CircuitBreaker)
+	assert.False(t, primary.RateLimit.Enabled)
+	assert.Equal(t, "insider:webhook:rate_limit:primary", primary.RateLimit.Key)
+
+	backup := providers[1]
+	assert.Equal(t, 5*time.Second, backup.Timeout)
+	assert.Equal(t, 1, backup.Priority)
+	assert.False(t, backup.CircuitBreaker.Enabled)
+	assert.True(t, backup.RateLimit.Enabled)
+	assert.Equal(t, 2.0, backup.RateLimit.Rate)
+	assert.Equal(t, ratelimitbackends.Redis, backup.RateLimit.Backend)
+	assert.Equal(t, time.Second, backup.RateLimit.MaxWait)
+	assert.Equal(t, "insider:webhook:rate_limit:backup", backup.RateLimit.Key)
+}
+
+func TestWebhookProviders_Mapping(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+	viper.Set("webhook.mapping.response.message_id_header", "X-Message-Id")
+	viper.Set("webhook.providers", []map[string]any{{
+		"name": "form",
+		"url":  "https://form.example.com",
+		"mapping": map[string]any{
+			"request": map[string]any{
+				"content_type": "application/x-www-form-urlencoded",
+				"fields":       []map[string]any{{"path": "To", "value": "{{.To}}"}},
+			},
+			"response": map[string]any{"status": "data.status", "accepted_statuses": []string{"queued"}},
+		},
+	}})
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+
+	mapping := cfg.WebhookProviders()[0].Mapping
+	assert.Equal(t, "application/x-www-form-urlencoded", mapping.Request.ContentType)
+	assert.Equal(t, []FieldMappingConfig{{Path: "To", Value: "{{.To}}"}}, mapping.Request.Fields)
+	assert.Equal(t, "data.status", mapping.Response.Status)
+	assert.Equal(t, []string{"queued"}, mapping.Response.AcceptedStatuses)
+	// The top-level mapping only applies to webhook.url.
+	assert.Empty(t, mapping.Response.MessageIDHeader)
+
+	cfg.Webhook.Providers = nil
+	assert.Equal(t, "X-Message-Id", cfg.WebhookProviders()[0].Mapping.Response.MessageIDHeader)
+}
+
+func TestWebhookAuthFromEnv(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+	t.Setenv("WEBHOOK_AUTH_TYPE", "oauth2")
+	t.Setenv("WEBHOOK_AUTH_TOKEN_URL", "https://auth.example.com/token")
+	t.Setenv("WEBHOOK_AUTH_CLIENT_ID", "client")
+	t.Setenv("WEBHOOK_AUTH_CLIENT_SECRET", "secret")
+	setupEnvironmentVariables()
+	viper.Set("webhook.auth.scopes", []string{"sms.send"})
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+
+	auth := cfg.WebhookProviders()[0].Auth
+	assert.Equal(t, authstrategies.OAuth2, auth.Type)
+	assert.Equal(t, "https://auth.example.com/token", auth.TokenURL)
+	assert.Equal(t, "client", auth.ClientID)
+	assert.Equal(t, "secret", auth.ClientSecret)
+	assert.Equal(t, []string{"sms.send"}, auth.Scopes)
+}
+
+func TestWebhookProviders_TLSAndProxy(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+	t.Setenv("WEBHOOK_TLS_CERT_FILE", "/etc/insider/client.crt")
+	t.Setenv("WEBHOOK_TLS_KEY_FILE", "/etc/insider/client.key")
+	t.Setenv("WEBHOOK_TLS_MIN_VERSION", "1.3")
+	t.Setenv("WEBHOOK_PROXY_URL", "http://egress.internal:3128")
+	setupEnvironmentVariables()
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+
+	provider := cfg.WebhookProviders()[0]
+	assert.Equal(t, TLSConfig{CertFile: "/etc/insider/client.crt", KeyFile: "/etc/insider/client.key", MinVersion: "1.3"}, provider.TLS)
+	assert.Equal(t, "http://egress.internal:3128", provider.ProxyURL)
+
+	cfg.Webhook.Providers = []ProviderConfig{
+		{Name: "carrier", TLS: TLSConfig{CAFile: "/etc/insider/carrier-ca.pem"}},
+		{Name: "direct", ProxyURL: "http://other-proxy.internal:3128"},
+	}
+	providers := cfg.WebhookProviders()
+	// The proxy is shared unless a provider sets its own; TLS is per provider.
+	assert.Equal(t, TLSConfig{CAFile: "/etc/insider/carrier-ca.pem"}, providers[0].TLS)
+	assert.Equal(t, "http://egress.internal:3128", providers[0].ProxyURL)
+	assert.Empty(t, providers[1].TLS)
+	assert.Equal(t, "http://other-proxy.internal:3128", providers[1].ProxyURL)
+}
+
+func TestDatabaseReplicasFromEnv(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+	t.Setenv("DATABASE_REPLICAS", "postgres://replica-1/db,postgres://replica-2/db")
+	viper.BindEnv("database.replicas", "DATABASE_REPLICAS")
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+	assert.Equal(t, []string{"postgres://replica-1/db", "postgres://replica-2/db"}, cfg.Database.Replicas)
+	assert.Equal(t, 10*time.Second, cfg.Database.ReplicaHealthCheckInterval)
+}
+
+func TestDatabaseDriverDefault(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+	assert.Equal(t, databasedrivers.Postgres, cfg.Database.Driver)
+}
+
+func TestSchedulerConcurrencyDefault(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+	assert.Equal(t, 4, cfg.Scheduler.Concurrency)
+}
+
+func TestSchedulerStateDefaults(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+	assert.True(t, cfg.Scheduler.PersistState)
+	assert.Equal(t, 10*time.Second, cfg.Scheduler.ReconcileInterval)
+}
+
+func TestSchedulerAdaptiveBatchDefaults(t *testing.T) {
+	viper.Reset()
+	setupDefaults()
+
+	var cfg Config
+	assert.NoError(t, viper.Unmarshal(&cfg))
+	assert.False(t, cfg.Scheduler.AdaptiveBatch.Enabled)
+	assert.Equal(t, 1, cfg.Scheduler.AdaptiveBatch.MinBatchSize)
+	assert.Equal(t, 100, cfg.Scheduler.AdaptiveBatch.MaxBatchSize)
+	assert.Equal(t, time.Second, cfg.Scheduler.AdaptiveBatch.TargetLatency)
+	assert.Equal(t, 0.1, cfg.Scheduler.AdaptiveBatch.MaxErrorRate)
+}
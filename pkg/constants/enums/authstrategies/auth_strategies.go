@@ -0,0 +1,26 @@
+package authstrategies
+
+// AuthStrategy decides how requests to a webhook provider are authenticated.
+type AuthStrategy string
+
+const (
+	// Header sends a static key in a request header.
+	Header AuthStrategy = "header"
+	// Basic sends a username and password with HTTP basic authentication.
+	Basic AuthStrategy = "basic"
+	// OAuth2 sends a bearer token obtained with the OAuth2 client credentials grant.
+	OAuth2 AuthStrategy = "oauth2"
+)
+
+func (s AuthStrategy) String() string {
+	return string(s)
+}
+
+func (s AuthStrategy) IsValid() bool {
+	switch s {
+	case Header, Basic, OAuth2:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,20 @@
+package authstrategies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthStrategy_String(t *testing.T) {
+	assert.Equal(t, "header", Header.String())
+	assert.Equal(t, "basic", Basic.String())
+	assert.Equal(t, "oauth2", OAuth2.String())
+}
+
+func TestAuthStrategy_IsValid(t *testing.T) {
+	assert.True(t, Header.IsValid())
+	assert.True(t, Basic.IsValid())
+	assert.True(t, OAuth2.IsValid())
+	assert.False(t, AuthStrategy("digest").IsValid())
+}
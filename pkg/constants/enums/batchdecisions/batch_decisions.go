@@ -0,0 +1,22 @@
+package batchdecisions
+
+type BatchDecision string
+
+const (
+	Increase BatchDecision = "increase"
+	Decrease BatchDecision = "decrease"
+	Hold     BatchDecision = "hold"
+)
+
+func (d BatchDecision) String() string {
+	return string(d)
+}
+
+func (d BatchDecision) IsValid() bool {
+	switch d {
+	case Increase, Decrease, Hold:
+		return true
+	default:
+		return false
+	}
+}
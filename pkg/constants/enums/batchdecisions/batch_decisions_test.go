@@ -0,0 +1,20 @@
+package batchdecisions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchDecision_String(t *testing.T) {
+	assert.Equal(t, "increase", Increase.String())
+	assert.Equal(t, "decrease", Decrease.String())
+	assert.Equal(t, "hold", Hold.String())
+}
+
+func TestBatchDecision_IsValid(t *testing.T) {
+	assert.True(t, Increase.IsValid())
+	assert.True(t, Decrease.IsValid())
+	assert.True(t, Hold.IsValid())
+	assert.False(t, BatchDecision("reset").IsValid())
+}
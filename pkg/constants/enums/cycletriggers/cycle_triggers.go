@@ -0,0 +1,22 @@
+package cycletriggers
+
+type CycleTrigger string
+
+const (
+	Tick   CycleTrigger = "tick"
+	Wakeup CycleTrigger = "wakeup"
+	Manual CycleTrigger = "manual"
+)
+
+func (c CycleTrigger) String() string {
+	return string(c)
+}
+
+func (c CycleTrigger) IsValid() bool {
+	switch c {
+	case Tick, Wakeup, Manual:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,20 @@
+package cycletriggers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCycleTrigger_String(t *testing.T) {
+	assert.Equal(t, "tick", Tick.String())
+	assert.Equal(t, "wakeup", Wakeup.String())
+	assert.Equal(t, "manual", Manual.String())
+}
+
+func TestCycleTrigger_IsValid(t *testing.T) {
+	assert.True(t, Tick.IsValid())
+	assert.True(t, Wakeup.IsValid())
+	assert.True(t, Manual.IsValid())
+	assert.False(t, CycleTrigger("cron").IsValid())
+}
@@ -0,0 +1,21 @@
+package databasedrivers
+
+type DatabaseDriver string
+
+const (
+	Postgres DatabaseDriver = "postgres"
+	Memory   DatabaseDriver = "memory"
+)
+
+func (d DatabaseDriver) String() string {
+	return string(d)
+}
+
+func (d DatabaseDriver) IsValid() bool {
+	switch d {
+	case Postgres, Memory:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,18 @@
+package databasedrivers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabaseDriver_String(t *testing.T) {
+	assert.Equal(t, "postgres", Postgres.String())
+	assert.Equal(t, "memory", Memory.String())
+}
+
+func TestDatabaseDriver_IsValid(t *testing.T) {
+	assert.True(t, Postgres.IsValid())
+	assert.True(t, Memory.IsValid())
+	assert.False(t, DatabaseDriver("mysql").IsValid())
+}
@@ -0,0 +1,21 @@
+package electionbackends
+
+type ElectionBackend string
+
+const (
+	Redis    ElectionBackend = "redis"
+	Postgres ElectionBackend = "postgres"
+)
+
+func (b ElectionBackend) String() string {
+	return string(b)
+}
+
+func (b ElectionBackend) IsValid() bool {
+	switch b {
+	case Redis, Postgres:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,18 @@
+package electionbackends
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElectionBackend_String(t *testing.T) {
+	assert.Equal(t, "redis", Redis.String())
+	assert.Equal(t, "postgres", Postgres.String())
+}
+
+func TestElectionBackend_IsValid(t *testing.T) {
+	assert.True(t, Redis.IsValid())
+	assert.True(t, Postgres.IsValid())
+	assert.False(t, ElectionBackend("etcd").IsValid())
+}
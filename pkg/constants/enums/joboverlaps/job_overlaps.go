@@ -0,0 +1,28 @@
+package joboverlaps
+
+// JobOverlap decides what happens when a job's run is due while its previous
+// run is still in progress.
+type JobOverlap string
+
+const (
+	// Skip drops the due run.
+	Skip JobOverlap = "skip"
+	// Queue runs once more as soon as the run in progress finishes. Several due
+	// runs are coalesced into one.
+	Queue JobOverlap = "queue"
+	// Allow starts the due run alongside the one in progress.
+	Allow JobOverlap = "allow"
+)
+
+func (o JobOverlap) String() string {
+	return string(o)
+}
+
+func (o JobOverlap) IsValid() bool {
+	switch o {
+	case Skip, Queue, Allow:
+		return true
+	default:
+		return false
+	}
+}
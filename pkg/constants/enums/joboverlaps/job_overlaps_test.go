@@ -0,0 +1,20 @@
+package joboverlaps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobOverlap_String(t *testing.T) {
+	assert.Equal(t, "skip", Skip.String())
+	assert.Equal(t, "queue", Queue.String())
+	assert.Equal(t, "allow", Allow.String())
+}
+
+func TestJobOverlap_IsValid(t *testing.T) {
+	assert.True(t, Skip.IsValid())
+	assert.True(t, Queue.IsValid())
+	assert.True(t, Allow.IsValid())
+	assert.False(t, JobOverlap("replace").IsValid())
+}
@@ -0,0 +1,21 @@
+package notifiertypes
+
+type NotifierType string
+
+const (
+	Local NotifierType = "local"
+	Redis NotifierType = "redis"
+)
+
+func (n NotifierType) String() string {
+	return string(n)
+}
+
+func (n NotifierType) IsValid() bool {
+	switch n {
+	case Local, Redis:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,18 @@
+package notifiertypes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifierType_String(t *testing.T) {
+	assert.Equal(t, "local", Local.String())
+	assert.Equal(t, "redis", Redis.String())
+}
+
+func TestNotifierType_IsValid(t *testing.T) {
+	assert.True(t, Local.IsValid())
+	assert.True(t, Redis.IsValid())
+	assert.False(t, NotifierType("postgres").IsValid())
+}
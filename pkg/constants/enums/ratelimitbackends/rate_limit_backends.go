@@ -0,0 +1,21 @@
+package ratelimitbackends
+
+type RateLimitBackend string
+
+const (
+	Local RateLimitBackend = "local"
+	Redis RateLimitBackend = "redis"
+)
+
+func (b RateLimitBackend) String() string {
+	return string(b)
+}
+
+func (b RateLimitBackend) IsValid() bool {
+	switch b {
+	case Local, Redis:
+		return true
+	default:
+		return false
+	}
+}
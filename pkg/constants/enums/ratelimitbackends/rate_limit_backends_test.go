@@ -0,0 +1,18 @@
+package ratelimitbackends
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitBackend_String(t *testing.T) {
+	assert.Equal(t, "local", Local.String())
+	assert.Equal(t, "redis", Redis.String())
+}
+
+func TestRateLimitBackend_IsValid(t *testing.T) {
+	assert.True(t, Local.IsValid())
+	assert.True(t, Redis.IsValid())
+	assert.False(t, RateLimitBackend("memcached").IsValid())
+}
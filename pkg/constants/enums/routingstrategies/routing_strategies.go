@@ -0,0 +1,26 @@
+package routingstrategies
+
+// RoutingStrategy decides the order in which the providers that may deliver a
+// message are tried.
+type RoutingStrategy string
+
+const (
+	// Priority tries providers from the lowest priority value up.
+	Priority RoutingStrategy = "priority"
+	// Weighted picks the first provider at random in proportion to its weight, so
+	// traffic is split between providers, and fails over in priority order.
+	Weighted RoutingStrategy = "weighted"
+)
+
+func (s RoutingStrategy) String() string {
+	return string(s)
+}
+
+func (s RoutingStrategy) IsValid() bool {
+	switch s {
+	case Priority, Weighted:
+		return true
+	default:
+		return false
+	}
+}
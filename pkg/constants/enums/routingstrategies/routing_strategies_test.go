@@ -0,0 +1,18 @@
+package routingstrategies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutingStrategy_String(t *testing.T) {
+	assert.Equal(t, "priority", Priority.String())
+	assert.Equal(t, "weighted", Weighted.String())
+}
+
+func TestRoutingStrategy_IsValid(t *testing.T) {
+	assert.True(t, Priority.IsValid())
+	assert.True(t, Weighted.IsValid())
+	assert.False(t, RoutingStrategy("round_robin").IsValid())
+}
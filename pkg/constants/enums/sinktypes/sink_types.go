@@ -0,0 +1,21 @@
+package sinktypes
+
+type SinkType string
+
+const (
+	HTTP SinkType = "http"
+	File SinkType = "file"
+)
+
+func (s SinkType) String() string {
+	return string(s)
+}
+
+func (s SinkType) IsValid() bool {
+	switch s {
+	case HTTP, File:
+		return true
+	default:
+		return false
+	}
+}
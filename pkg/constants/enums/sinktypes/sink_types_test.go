@@ -0,0 +1,18 @@
+package sinktypes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSinkType_String(t *testing.T) {
+	assert.Equal(t, "http", HTTP.String())
+	assert.Equal(t, "file", File.String())
+}
+
+func TestSinkType_IsValid(t *testing.T) {
+	assert.True(t, HTTP.IsValid())
+	assert.True(t, File.IsValid())
+	assert.False(t, SinkType("kafka").IsValid())
+}
@@ -0,0 +1,40 @@
+package statsintervals
+
+import "time"
+
+type StatsInterval string
+
+const (
+	Hour StatsInterval = "hour"
+	Day  StatsInterval = "day"
+)
+
+func (s StatsInterval) String() string {
+	return string(s)
+}
+
+func (s StatsInterval) IsValid() bool {
+	switch s {
+	case Hour, Day:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s StatsInterval) Duration() time.Duration {
+	switch s {
+	case Day:
+		return 24 * time.Hour
+	default:
+		return time.Hour
+	}
+}
+
+func FromString(interval string) StatsInterval {
+	si := StatsInterval(interval)
+	if si.IsValid() {
+		return si
+	}
+	return Hour
+}
@@ -0,0 +1,29 @@
+package statsintervals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsInterval_String(t *testing.T) {
+	assert.Equal(t, "hour", Hour.String())
+	assert.Equal(t, "day", Day.String())
+}
+
+func TestStatsInterval_IsValid(t *testing.T) {
+	assert.True(t, Hour.IsValid())
+	assert.True(t, Day.IsValid())
+	assert.False(t, StatsInterval("week").IsValid())
+}
+
+func TestStatsInterval_Duration(t *testing.T) {
+	assert.Equal(t, time.Hour, Hour.Duration())
+	assert.Equal(t, 24*time.Hour, Day.Duration())
+}
+
+func TestFromString(t *testing.T) {
+	assert.Equal(t, Day, FromString("day"))
+	assert.Equal(t, Hour, FromString("unknown")) // Default case
+}
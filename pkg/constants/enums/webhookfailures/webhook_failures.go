@@ -0,0 +1,29 @@
+package webhookfailures
+
+// WebhookFailure classifies a failed webhook send by whether and when it is worth
+// retrying.
+type WebhookFailure string
+
+const (
+	// Permanent failures, such as a 4xx validation error, fail the same way on
+	// every retry.
+	Permanent WebhookFailure = "permanent"
+	// Throttled sends were refused with 429 and can be retried after the
+	// provider's Retry-After.
+	Throttled WebhookFailure = "throttled"
+	// Transient failures, such as a 5xx or a timeout, may succeed on a retry.
+	Transient WebhookFailure = "transient"
+)
+
+func (f WebhookFailure) String() string {
+	return string(f)
+}
+
+func (f WebhookFailure) IsValid() bool {
+	switch f {
+	case Permanent, Throttled, Transient:
+		return true
+	default:
+		return false
+	}
+}
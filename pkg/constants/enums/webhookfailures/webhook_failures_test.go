@@ -0,0 +1,20 @@
+package webhookfailures
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookFailure_String(t *testing.T) {
+	assert.Equal(t, "permanent", Permanent.String())
+	assert.Equal(t, "throttled", Throttled.String())
+	assert.Equal(t, "transient", Transient.String())
+}
+
+func TestWebhookFailure_IsValid(t *testing.T) {
+	assert.True(t, Permanent.IsValid())
+	assert.True(t, Throttled.IsValid())
+	assert.True(t, Transient.IsValid())
+	assert.False(t, WebhookFailure("unknown").IsValid())
+}
@@ -0,0 +1,80 @@
+package cron
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Calendar is a set of holidays on which a Schedule does not fire.
+type Calendar struct {
+	holidays map[string]struct{}
+}
+
+// LoadCalendar reads a holiday calendar file. See ParseCalendar for the format.
+func LoadCalendar(path string) (*Calendar, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	calendar, err := ParseCalendar(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return calendar, nil
+}
+
+// ParseCalendar reads one holiday per line as a YYYY-MM-DD date. Anything after
+// the date is treated as a description, and blank lines and lines starting with
+// "#" are ignored:
+//
+//	# Public holidays 2025
+//	2025-01-01 New Year's Day
+//	2025-04-23 National Sovereignty and Children's Day
+func ParseCalendar(r io.Reader) (*Calendar, error) {
+	calendar := &Calendar{holidays: make(map[string]struct{})}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		date := strings.Fields(text)[0]
+		if _, err := time.Parse(dateLayout, date); err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q, expected YYYY-MM-DD", line, date)
+		}
+		calendar.holidays[date] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
+
+// IsHoliday reports whether t falls on a holiday, using the date of t in its own
+// location. A nil calendar has no holidays.
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	if c == nil {
+		return false
+	}
+	_, ok := c.holidays[t.Format(dateLayout)]
+	return ok
+}
+
+// Len returns the number of holidays in the calendar.
+func (c *Calendar) Len() int {
+	if c == nil {
+		return 0
+	}
+	return len(c.holidays)
+}
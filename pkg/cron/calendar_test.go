@@ -0,0 +1,50 @@
+package cron
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCalendar(t *testing.T) {
+	input := `# Public holidays
+2025-01-01 New Year's Day
+
+  2025-04-23
+`
+	calendar, err := ParseCalendar(strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, calendar.Len())
+	assert.True(t, calendar.IsHoliday(time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)))
+	assert.True(t, calendar.IsHoliday(time.Date(2025, 4, 23, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, calendar.IsHoliday(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestParseCalendar_InvalidDate(t *testing.T) {
+	_, err := ParseCalendar(strings.NewReader("2025-01-01\n01/02/2025\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestLoadCalendar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.txt")
+	require.NoError(t, os.WriteFile(path, []byte("2025-12-25 Christmas\n"), 0o600))
+
+	calendar, err := LoadCalendar(path)
+	require.NoError(t, err)
+	assert.True(t, calendar.IsHoliday(time.Date(2025, 12, 25, 9, 0, 0, 0, time.UTC)))
+
+	_, err = LoadCalendar(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestCalendar_Nil(t *testing.T) {
+	var calendar *Calendar
+	assert.False(t, calendar.IsHoliday(time.Now()))
+	assert.Equal(t, 0, calendar.Len())
+}
@@ -0,0 +1,269 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchHorizon bounds how far ahead Next looks for a matching time. Every valid
+// expression matches at least once every four years, holidays aside.
+const searchHorizon = 5 * 366 * 24 * time.Hour
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}}
+	// Day of week accepts 7 as an alias for Sunday; it is folded into 0 after parsing.
+	dowField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Schedule is a parsed five-field cron expression (minute, hour, day of month,
+// month, day of week) evaluated in a fixed location. As in standard cron, when
+// both day fields are restricted a time matches if either of them does.
+type Schedule struct {
+	expr     string
+	location *time.Location
+	calendar *Calendar
+
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+// Parse parses a standard five-field cron expression or one of the @yearly,
+// @monthly, @weekly, @daily, @midnight and @hourly descriptors. Fields accept
+// "*", single values, ranges ("1-5"), steps ("*/15", "8-18/2") and comma
+// separated lists; months and weekdays also accept three-letter names. A nil
+// location means UTC.
+func Parse(expr string, location *time.Location) (*Schedule, error) {
+	if location == nil {
+		location = time.UTC
+	}
+
+	spec := strings.TrimSpace(expr)
+	if descriptor, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = descriptor
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, got %d", expr, len(fields))
+	}
+
+	s := &Schedule{expr: strings.TrimSpace(expr), location: location}
+
+	var err error
+	if s.minute, err = parseField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], domField); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dowField); err != nil {
+		return nil, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+
+	s.domStar = fields[2] == "*" || fields[2] == "?"
+	s.dowStar = fields[4] == "*" || fields[4] == "?"
+
+	return s, nil
+}
+
+// WithCalendar returns a copy of the schedule that skips every fire time falling
+// on one of the calendar's holidays, as seen in the schedule's location.
+func (s *Schedule) WithCalendar(calendar *Calendar) *Schedule {
+	c := *s
+	c.calendar = calendar
+	return &c
+}
+
+// Location returns the location the schedule is evaluated in.
+func (s *Schedule) Location() *time.Location {
+	return s.location
+}
+
+// String returns the expression the schedule was parsed from.
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// Next returns the first fire time strictly after t, in the schedule's location,
+// or the zero time if there is none within the next five years, as for
+// "0 0 30 2 *" or a calendar that marks every matching day as a holiday.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(searchHorizon)
+
+	for t.Before(limit) {
+		next := s.next(t, limit)
+		if next.IsZero() || !s.calendar.IsHoliday(next) {
+			return next
+		}
+		// Skip the rest of the holiday rather than trying it minute by minute.
+		y, m, d := next.Date()
+		t = forward(next, time.Date(y, m, d+1, 0, 0, 0, 0, s.location))
+	}
+
+	return time.Time{}
+}
+
+// next finds the first matching time at or after t, ignoring holidays.
+func (s *Schedule) next(t, limit time.Time) time.Time {
+	loc := s.location
+
+	for t.Before(limit) {
+		if !has(s.month, int(t.Month())) {
+			t = forward(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = forward(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if !has(s.hour, t.Hour()) {
+			t = forward(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc))
+			continue
+		}
+		if !has(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+// forward returns candidate, unless time.Date normalised a local time that does
+// not exist (a daylight saving gap) to an instant at or before t; it then moves
+// to the start of the next absolute hour so the search always makes progress.
+func forward(t, candidate time.Time) time.Time {
+	if candidate.After(t) {
+		return candidate
+	}
+	return t.Add(time.Hour - time.Duration(t.Minute())*time.Minute)
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := has(s.dom, t.Day())
+	dowMatch := has(s.dow, int(t.Weekday()))
+
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func has(set uint64, value int) bool {
+	return set&(1<<uint(value)) != 0
+}
+
+func parseField(spec string, f field) (uint64, error) {
+	var set uint64
+
+	for _, part := range strings.Split(spec, ",") {
+		bitsForPart, err := parsePart(part, f)
+		if err != nil {
+			return 0, err
+		}
+		set |= bitsForPart
+	}
+
+	return set, nil
+}
+
+func parsePart(part string, f field) (uint64, error) {
+	rangeSpec, stepSpec, hasStep := strings.Cut(part, "/")
+
+	step := 1
+	if hasStep {
+		var err error
+		step, err = strconv.Atoi(stepSpec)
+		if err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step %q in %s field", stepSpec, f.name)
+		}
+	}
+
+	var low, high int
+	switch {
+	case rangeSpec == "*" || rangeSpec == "?":
+		low, high = f.min, f.max
+	case strings.Contains(rangeSpec, "-"):
+		lowSpec, highSpec, _ := strings.Cut(rangeSpec, "-")
+		var err error
+		if low, err = parseValue(lowSpec, f); err != nil {
+			return 0, err
+		}
+		if high, err = parseValue(highSpec, f); err != nil {
+			return 0, err
+		}
+		if low > high {
+			return 0, fmt.Errorf("invalid range %q in %s field", rangeSpec, f.name)
+		}
+	default:
+		var err error
+		if low, err = parseValue(rangeSpec, f); err != nil {
+			return 0, err
+		}
+		high = low
+		// "5/15" means every 15 starting at 5, as in most cron implementations.
+		if hasStep {
+			high = f.max
+		}
+	}
+
+	var set uint64
+	for v := low; v <= high; v += step {
+		set |= 1 << uint(v)
+	}
+
+	return set, nil
+}
+
+func parseValue(spec string, f field) (int, error) {
+	if v, ok := f.names[strings.ToUpper(spec)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(spec)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q in %s field", spec, f.name)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d-%d] in %s field", v, f.min, f.max, f.name)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,119 @@
+package cron
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"* * * FOO *",
+		"@often",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			_, err := Parse(expr, time.UTC)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	istanbul, err := time.LoadLocation("Europe/Istanbul")
+	require.NoError(t, err)
+
+	// 2025-01-15 is a Wednesday.
+	from := time.Date(2025, 1, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		expr     string
+		location *time.Location
+		from     time.Time
+		expected time.Time
+	}{
+		{"every minute", "* * * * *", time.UTC, from, time.Date(2025, 1, 15, 10, 8, 0, 0, time.UTC)},
+		{"strictly after", "8 * * * *", time.UTC, time.Date(2025, 1, 15, 10, 8, 0, 0, time.UTC), time.Date(2025, 1, 15, 11, 8, 0, 0, time.UTC)},
+		{"step", "*/15 * * * *", time.UTC, from, time.Date(2025, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{"step from value", "5/20 * * * *", time.UTC, from, time.Date(2025, 1, 15, 10, 25, 0, 0, time.UTC)},
+		{"list", "0 9,17 * * *", time.UTC, from, time.Date(2025, 1, 15, 17, 0, 0, 0, time.UTC)},
+		{"business hours roll to next day", "0 9-17 * * MON-FRI", time.UTC, time.Date(2025, 1, 15, 17, 30, 0, 0, time.UTC), time.Date(2025, 1, 16, 9, 0, 0, 0, time.UTC)},
+		{"business hours skip weekend", "0 9-17 * * 1-5", time.UTC, time.Date(2025, 1, 17, 18, 0, 0, 0, time.UTC), time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC)},
+		{"sunday as seven", "0 0 * * 7", time.UTC, from, time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"month names", "0 0 1 mar *", time.UTC, from, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"day of month or day of week", "0 0 1 * FRI", time.UTC, from, time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.UTC, from, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"descriptor", "@daily", time.UTC, from, time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"timezone", "0 9 * * *", istanbul, from, time.Date(2025, 1, 16, 9, 0, 0, 0, istanbul)},
+		{"never", "0 0 30 2 *", time.UTC, from, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.expr, tt.location)
+			require.NoError(t, err)
+
+			next := schedule.Next(tt.from)
+			assert.True(t, tt.expected.Equal(next), "expected %s, got %s", tt.expected, next)
+			if !next.IsZero() {
+				assert.Equal(t, tt.location, next.Location())
+			}
+		})
+	}
+}
+
+func TestSchedule_Next_DaylightSavingTime(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	schedule, err := Parse("30 2 * * *", newYork)
+	require.NoError(t, err)
+
+	// 02:30 does not exist on 2025-03-09; the run moves to the next existing time.
+	next := schedule.Next(time.Date(2025, 3, 9, 0, 0, 0, 0, newYork))
+	assert.Equal(t, time.Date(2025, 3, 10, 2, 30, 0, 0, newYork), next)
+}
+
+func TestSchedule_Next_SkipsHolidays(t *testing.T) {
+	calendar, err := ParseCalendar(strings.NewReader("2025-01-16\n2025-01-17 Long weekend\n"))
+	require.NoError(t, err)
+
+	schedule, err := Parse("0 9 * * MON-FRI", time.UTC)
+	require.NoError(t, err)
+	schedule = schedule.WithCalendar(calendar)
+
+	next := schedule.Next(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2025, 1, 20, 9, 0, 0, 0, time.UTC), next)
+}
+
+func TestSchedule_Next_HolidaysUseScheduleLocation(t *testing.T) {
+	istanbul, err := time.LoadLocation("Europe/Istanbul")
+	require.NoError(t, err)
+
+	calendar, err := ParseCalendar(strings.NewReader("2025-01-16\n"))
+	require.NoError(t, err)
+
+	schedule, err := Parse("30 0 * * *", istanbul)
+	require.NoError(t, err)
+	schedule = schedule.WithCalendar(calendar)
+
+	// 2025-01-15 21:30 UTC is already 2025-01-16 00:30 in Istanbul, a holiday.
+	next := schedule.Next(time.Date(2025, 1, 15, 20, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2025, 1, 17, 0, 30, 0, 0, istanbul), next)
+}
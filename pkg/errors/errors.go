@@ -42,11 +42,22 @@ var (
 
 	ErrMessageNotFound         = NewError("MESSAGE_NOT_FOUND", "Message not found", http.StatusNotFound)
 	ErrMessageSendFailed       = NewError("MESSAGE_SEND_FAILED", "Failed to send message", http.StatusInternalServerError)
+	ErrMessageNotSendable      = NewError("MESSAGE_NOT_SENDABLE", "Message is not valid for sending", http.StatusUnprocessableEntity)
 	ErrSchedulerNotRunning     = NewError("SCHEDULER_NOT_RUNNING", "Scheduler is not running", http.StatusBadRequest)
 	ErrSchedulerAlreadyRunning = NewError("SCHEDULER_ALREADY_RUNNING", "Scheduler is already running", http.StatusBadRequest)
+	ErrSchedulerCycleRunning   = NewError("SCHEDULER_CYCLE_IN_PROGRESS", "A processing cycle is already in progress", http.StatusConflict)
+	ErrProcessorNotSet         = NewError("PROCESSOR_NOT_SET", "Message processor not set", http.StatusInternalServerError)
 	ErrInvalidMessageContent   = NewError("INVALID_MESSAGE_CONTENT", "Message content exceeds character limit", http.StatusBadRequest)
 	ErrDatabaseConnection      = NewError("DATABASE_CONNECTION_ERROR", "Database connection failed", http.StatusInternalServerError)
 	ErrCacheConnection         = NewError("CACHE_CONNECTION_ERROR", "Cache connection failed", http.StatusInternalServerError)
+	ErrWebhookThrottled        = NewError("WEBHOOK_THROTTLED", "Webhook send rate limit reached", http.StatusTooManyRequests)
+	ErrWebhookCircuitOpen      = NewErrorWithDetails("WEBHOOK_CIRCUIT_OPEN", "Webhook service is temporarily unavailable", "Circuit breaker is open due to repeated failures", http.StatusServiceUnavailable)
+	ErrWebhookCircuitHalfOpen  = NewErrorWithDetails("WEBHOOK_CIRCUIT_HALF_OPEN", "Webhook service is testing recovery", "Circuit breaker is in half-open state", http.StatusServiceUnavailable)
+	ErrInvalidStatsRange       = NewError("INVALID_STATS_RANGE", "Stats range start must be before its end", http.StatusBadRequest)
+	ErrStatsRangeTooLarge      = NewError("STATS_RANGE_TOO_LARGE", "Stats range contains too many buckets for the interval", http.StatusBadRequest)
+	ErrJobNotFound             = NewError("JOB_NOT_FOUND", "Job not found", http.StatusNotFound)
+	ErrJobNotRunning           = NewError("JOB_NOT_RUNNING", "Job is not running", http.StatusBadRequest)
+	ErrJobAlreadyRunning       = NewError("JOB_ALREADY_RUNNING", "Job is already running", http.StatusBadRequest)
 )
 
 func WrapError(err error, code, message string, status int) *Error {
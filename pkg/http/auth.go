@@ -0,0 +1,174 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// AuthStrategy authenticates outgoing requests by setting their authorization
+// headers.
+type AuthStrategy interface {
+	Authorize(ctx context.Context, headers map[string]string) error
+}
+
+// RefreshableAuth is an AuthStrategy whose credentials can be renewed after the
+// server rejects them.
+type RefreshableAuth interface {
+	AuthStrategy
+	// Refresh discards the credentials in headers, which the server rejected, and
+	// sets new ones. Credentials that were already renewed by a concurrent
+	// request are reused rather than renewed again.
+	Refresh(ctx context.Context, headers map[string]string) error
+}
+
+type headerAuth struct {
+	name  string
+	value string
+}
+
+// NewHeaderAuth returns an AuthStrategy that sends value in the header name.
+func NewHeaderAuth(name, value string) AuthStrategy {
+	return &headerAuth{name: name, value: value}
+}
+
+func (a *headerAuth) Authorize(ctx context.Context, headers map[string]string) error {
+	headers[a.name] = a.value
+	return nil
+}
+
+type basicAuth struct {
+	credentials string
+}
+
+// NewBasicAuth returns an AuthStrategy that sends username and password with HTTP
+// basic authentication.
+func NewBasicAuth(username, password string) AuthStrategy {
+	return &basicAuth{credentials: base64.StdEncoding.EncodeToString([]byte(username + ":" + password))}
+}
+
+func (a *basicAuth) Authorize(ctx context.Context, headers map[string]string) error {
+	headers["Authorization"] = "Basic " + a.credentials
+	return nil
+}
+
+// OAuth2Config configures the OAuth2 client credentials grant. A token is renewed
+// RefreshBefore it expires, so that no request is sent with a token about to
+// expire.
+type OAuth2Config struct {
+	TokenURL      string
+	ClientID      string
+	ClientSecret  string
+	Scopes        []string
+	RefreshBefore time.Duration
+}
+
+// ErrTokenRequest is returned when the token endpoint does not issue a token.
+var ErrTokenRequest = errors.New("oauth2 token request failed")
+
+type oauth2Auth struct {
+	config OAuth2Config
+	client *resty.Client
+	now    func() time.Time
+
+	mu        sync.Mutex
+	token     string
+	refreshAt time.Time
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// NewOAuth2ClientCredentials returns a RefreshableAuth that sends a bearer token
+// obtained from cfg.TokenURL with the client credentials grant. The token is
+// cached and shared by concurrent requests until it is due for renewal; a token
+// issued without expires_in is kept until the server rejects it. The token
+// endpoint is called with a client built from config.
+func NewOAuth2ClientCredentials(cfg OAuth2Config, config *ClientConfig) RefreshableAuth {
+	return &oauth2Auth{
+		config: cfg,
+		client: NewClient(config),
+		now:    time.Now,
+	}
+}
+
+func (a *oauth2Auth) Authorize(ctx context.Context, headers map[string]string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.authorize(ctx, headers)
+}
+
+func (a *oauth2Auth) Refresh(ctx context.Context, headers map[string]string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if headers["Authorization"] == "Bearer "+a.token {
+		a.token = ""
+	}
+	return a.authorize(ctx, headers)
+}
+
+// authorize sets the cached token, requesting a new one when there is none or it
+// is due for renewal. The caller holds a.mu, so concurrent requests wait for a
+// single token request.
+func (a *oauth2Auth) authorize(ctx context.Context, headers map[string]string) error {
+	if a.token == "" || (!a.refreshAt.IsZero() && !a.now().Before(a.refreshAt)) {
+		if err := a.requestToken(ctx); err != nil {
+			return err
+		}
+	}
+	headers["Authorization"] = "Bearer " + a.token
+	return nil
+}
+
+func (a *oauth2Auth) requestToken(ctx context.Context) error {
+	form := map[string]string{"grant_type": "client_credentials"}
+	if len(a.config.Scopes) > 0 {
+		form["scope"] = strings.Join(a.config.Scopes, " ")
+	}
+
+	requestedAt := a.now()
+	resp, err := a.client.R().
+		SetContext(ctx).
+		SetBasicAuth(a.config.ClientID, a.config.ClientSecret).
+		SetFormData(form).
+		Post(a.config.TokenURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrTokenRequest, err)
+	}
+	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		return fmt.Errorf("%w: status %d: %s", ErrTokenRequest, resp.StatusCode(), resp.String())
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(resp.Body(), &token); err != nil {
+		return fmt.Errorf("%w: %v", ErrTokenRequest, err)
+	}
+	if token.AccessToken == "" {
+		return fmt.Errorf("%w: response has no access_token", ErrTokenRequest)
+	}
+
+	a.token = token.AccessToken
+	a.refreshAt = time.Time{}
+	if token.ExpiresIn > 0 {
+		// A token that lives shorter than RefreshBefore is renewed halfway through
+		// its lifetime instead of on every request.
+		lifetime := time.Duration(token.ExpiresIn) * time.Second
+		renewAfter := lifetime - a.config.RefreshBefore
+		if renewAfter <= 0 {
+			renewAfter = lifetime / 2
+		}
+		a.refreshAt = requestedAt.Add(renewAfter)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderAuth(t *testing.T) {
+	headers := map[string]string{}
+	require.NoError(t, NewHeaderAuth("x-ins-auth-key", "secret").Authorize(context.Background(), headers))
+	assert.Equal(t, map[string]string{"x-ins-auth-key": "secret"}, headers)
+}
+
+func TestBasicAuth(t *testing.T) {
+	headers := map[string]string{}
+	require.NoError(t, NewBasicAuth("user", "pass").Authorize(context.Background(), headers))
+	assert.Equal(t, "Basic dXNlcjpwYXNz", headers["Authorization"])
+}
+
+// newTokenServer issues the tokens token-1, token-2, ... valid for expiresIn
+// seconds, and counts the requests it receives.
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "client", clientID)
+		assert.Equal(t, "secret", clientSecret)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "sms.send sms.read", r.PostForm.Get("scope"))
+
+		n := requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, n, expiresIn)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newTestOAuth2(server *httptest.Server, now *time.Time) *oauth2Auth {
+	auth := NewOAuth2ClientCredentials(OAuth2Config{
+		TokenURL:      server.URL,
+		ClientID:      "client",
+		ClientSecret:  "secret",
+		Scopes:        []string{"sms.send", "sms.read"},
+		RefreshBefore: time.Minute,
+	}, nil).(*oauth2Auth)
+	auth.now = func() time.Time { return *now }
+	return auth
+}
+
+func TestOAuth2_CachesTokenUntilRenewal(t *testing.T) {
+	server, requests := newTokenServer(t, 3600)
+	now := time.Unix(1705312800, 0)
+	auth := newTestOAuth2(server, &now)
+
+	headers := map[string]string{}
+	require.NoError(t, auth.Authorize(context.Background(), headers))
+	assert.Equal(t, "Bearer token-1", headers["Authorization"])
+
+	now = now.Add(58 * time.Minute)
+	require.NoError(t, auth.Authorize(context.Background(), headers))
+	assert.Equal(t, "Bearer token-1", headers["Authorization"])
+	assert.Equal(t, int32(1), requests.Load())
+
+	// A minute before expiry the token is renewed.
+	now = now.Add(time.Minute)
+	require.NoError(t, auth.Authorize(context.Background(), headers))
+	assert.Equal(t, "Bearer token-2", headers["Authorization"])
+}
+
+func TestOAuth2_ShortLivedTokenRenewedHalfway(t *testing.T) {
+	server, _ := newTokenServer(t, 30)
+	now := time.Unix(1705312800, 0)
+	auth := newTestOAuth2(server, &now)
+
+	headers := map[string]string{}
+	require.NoError(t, auth.Authorize(context.Background(), headers))
+	now = now.Add(14 * time.Second)
+	require.NoError(t, auth.Authorize(context.Background(), headers))
+	assert.Equal(t, "Bearer token-1", headers["Authorization"])
+
+	now = now.Add(time.Second)
+	require.NoError(t, auth.Authorize(context.Background(), headers))
+	assert.Equal(t, "Bearer token-2", headers["Authorization"])
+}
+
+func TestOAuth2_RefreshRenewsRejectedTokenOnce(t *testing.T) {
+	server, requests := newTokenServer(t, 3600)
+	now := time.Unix(1705312800, 0)
+	auth := newTestOAuth2(server, &now)
+
+	rejected := map[string]string{}
+	require.NoError(t, auth.Authorize(context.Background(), rejected))
+
+	// Every request that sent the rejected token refreshes, but only the first
+	// requests a new token.
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			headers := map[string]string{"Authorization": rejected["Authorization"]}
+			assert.NoError(t, auth.Refresh(context.Background(), headers))
+			assert.Equal(t, "Bearer token-2", headers["Authorization"])
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestOAuth2_TokenRequestFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	now := time.Unix(1705312800, 0)
+	err := newTestOAuth2(server, &now).Authorize(context.Background(), map[string]string{})
+	assert.ErrorIs(t, err, ErrTokenRequest)
+	assert.Contains(t, err.Error(), "invalid_client")
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"crypto/tls"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -13,6 +14,11 @@ type ClientConfig struct {
 	RetryMaxWaitTime time.Duration
 	DefaultHeaders   map[string]string
 	RetryCondition   func(*resty.Response, error) bool
+	// TLS replaces the default TLS configuration; see NewTLSConfig.
+	TLS *tls.Config
+	// ProxyURL sends requests through an HTTP or HTTPS proxy instead of the one
+	// named by the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+	ProxyURL string
 }
 
 func DefaultConfig() *ClientConfig {
@@ -49,6 +55,14 @@ func NewClient(config *ClientConfig) *resty.Client {
 		client.AddRetryCondition(config.RetryCondition)
 	}
 
+	if config.TLS != nil {
+		client.SetTLSClientConfig(config.TLS)
+	}
+
+	if config.ProxyURL != "" {
+		client.SetProxy(config.ProxyURL)
+	}
+
 	return client
 }
 
@@ -0,0 +1,201 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// TLSOptions names the files and minimum version of a client TLS configuration.
+// CertFile and KeyFile hold a client certificate for mutual TLS, and CAFile a PEM
+// bundle of the CAs trusted instead of the system's. MinVersion is "1.2" unless
+// set.
+type TLSOptions struct {
+	CertFile   string
+	KeyFile    string
+	CAFile     string
+	MinVersion string
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// NewTLSConfig loads options into a client TLS configuration. The client
+// certificate and the CA bundle are read again when their files change on disk,
+// so rotated files are used from the next handshake on. With a CA bundle the
+// server's certificate is verified against the bundle read last, which needs the
+// server to be addressed by host name rather than IP address.
+func NewTLSConfig(options TLSOptions) (*tls.Config, error) {
+	if options.MinVersion == "" {
+		options.MinVersion = "1.2"
+	}
+	minVersion, ok := tlsVersions[options.MinVersion]
+	if !ok {
+		return nil, fmt.Errorf("unknown TLS version %q, expected 1.0, 1.1, 1.2 or 1.3", options.MinVersion)
+	}
+	if (options.CertFile == "") != (options.KeyFile == "") {
+		return nil, errors.New("a client certificate needs both a certificate and a key file")
+	}
+
+	config := &tls.Config{MinVersion: minVersion}
+
+	if options.CAFile != "" {
+		reloader := &caBundleReloader{file: options.CAFile}
+		if err := reloader.reload(); err != nil {
+			return nil, err
+		}
+		// Verification by crypto/tls is bound to the roots the configuration was
+		// built with, so it is replaced by one against the current bundle.
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = reloader.verifyConnection
+	}
+
+	if options.CertFile != "" {
+		reloader := &certificateReloader{certFile: options.CertFile, keyFile: options.KeyFile}
+		if err := reloader.reload(); err != nil {
+			return nil, err
+		}
+		config.GetClientCertificate = reloader.clientCertificate
+	}
+
+	return config, nil
+}
+
+// certificateReloader serves a client certificate and reads it again once its
+// certificate or key file changes.
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+
+	mu          sync.Mutex
+	certificate *tls.Certificate
+	loaded      [2]fileVersion
+}
+
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+func (r *certificateReloader) clientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// A rotation writes the certificate and the key one after the other, so a
+	// pair that does not load yet is retried on the next handshake while the
+	// previous certificate stays in use.
+	_ = r.reloadIfChanged()
+	return r.certificate, nil
+}
+
+func (r *certificateReloader) reloadIfChanged() error {
+	versions, err := r.versions()
+	if err != nil || versions == r.loaded {
+		return err
+	}
+	return r.reload()
+}
+
+func (r *certificateReloader) reload() error {
+	versions, err := r.versions()
+	if err != nil {
+		return err
+	}
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("load client certificate: %w", err)
+	}
+	r.certificate = &certificate
+	r.loaded = versions
+	return nil
+}
+
+func (r *certificateReloader) versions() ([2]fileVersion, error) {
+	var versions [2]fileVersion
+	for i, file := range []string{r.certFile, r.keyFile} {
+		version, err := statFile(file)
+		if err != nil {
+			return versions, fmt.Errorf("load client certificate: %w", err)
+		}
+		versions[i] = version
+	}
+	return versions, nil
+}
+
+// caBundleReloader verifies server certificates against a CA bundle and reads
+// the bundle again once its file changes.
+type caBundleReloader struct {
+	file string
+
+	mu     sync.Mutex
+	roots  *x509.CertPool
+	loaded fileVersion
+}
+
+func (r *caBundleReloader) verifyConnection(state tls.ConnectionState) error {
+	// crypto/tls leaves ServerName empty for an IP address, so the certificate
+	// could not be checked against the host it was meant for.
+	if state.ServerName == "" {
+		return errors.New("cannot verify a server addressed by IP address against the CA bundle, use its host name")
+	}
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("server sent no certificate")
+	}
+
+	options := x509.VerifyOptions{
+		DNSName:       state.ServerName,
+		Roots:         r.currentRoots(),
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, certificate := range state.PeerCertificates[1:] {
+		options.Intermediates.AddCert(certificate)
+	}
+	_, err := state.PeerCertificates[0].Verify(options)
+	return err
+}
+
+func (r *caBundleReloader) currentRoots() *x509.CertPool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// A bundle that is still being written is retried on the next handshake
+	// while the previous one stays in use.
+	if version, err := statFile(r.file); err == nil && version != r.loaded {
+		_ = r.reload()
+	}
+	return r.roots
+}
+
+func (r *caBundleReloader) reload() error {
+	version, err := statFile(r.file)
+	if err != nil {
+		return fmt.Errorf("read CA bundle: %w", err)
+	}
+	bundle, err := os.ReadFile(r.file)
+	if err != nil {
+		return fmt.Errorf("read CA bundle: %w", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(bundle) {
+		return fmt.Errorf("CA bundle %s contains no PEM certificates", r.file)
+	}
+	r.roots = roots
+	r.loaded = version
+	return nil
+}
+
+func statFile(path string) (fileVersion, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileVersion{}, err
+	}
+	return fileVersion{modTime: info.ModTime(), size: info.Size()}, nil
+}
@@ -0,0 +1,244 @@
+package http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM certificate and key for commonName, valid for localhost and
+// 127.0.0.1.
+func (ca *testCA) issue(t *testing.T, commonName string) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+// newTLSServer starts a server presenting a certificate issued by ca, and returns
+// its URL by host name.
+func newTLSServer(t *testing.T, ca *testCA) string {
+	certPEM, keyPEM := ca.issue(t, "carrier")
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{certificate}}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+}
+
+func clientCommonName(t *testing.T, config *tls.Config) string {
+	certificate, err := config.GetClientCertificate(&tls.CertificateRequestInfo{})
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	require.NoError(t, err)
+	return leaf.Subject.CommonName
+}
+
+func TestNewTLSConfig_ReloadsRotatedCertificate(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	issuedAt := time.Now().Add(-time.Hour)
+
+	certPEM, keyPEM := ca.issue(t, "first")
+	writeFile(t, certFile, certPEM, issuedAt)
+	writeFile(t, keyFile, keyPEM, issuedAt)
+
+	config, err := NewTLSConfig(TLSOptions{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.3"})
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), config.MinVersion)
+	assert.Equal(t, "first", clientCommonName(t, config))
+
+	// Halfway through a rotation the new certificate does not match the old key,
+	// so the old certificate stays in use.
+	certPEM, keyPEM = ca.issue(t, "second")
+	rotatedAt := issuedAt.Add(time.Minute)
+	writeFile(t, certFile, certPEM, rotatedAt)
+	assert.Equal(t, "first", clientCommonName(t, config))
+
+	writeFile(t, keyFile, keyPEM, rotatedAt)
+	assert.Equal(t, "second", clientCommonName(t, config))
+}
+
+func TestNewTLSConfig_RejectsInvalidOptions(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	writeFile(t, notPEM, []byte("not a certificate"), time.Now())
+
+	for name, options := range map[string]TLSOptions{
+		"unknown version":                {MinVersion: "1.4"},
+		"certificate only":               {CertFile: filepath.Join(dir, "client.crt")},
+		"missing files":                  {CertFile: filepath.Join(dir, "client.crt"), KeyFile: filepath.Join(dir, "client.key")},
+		"missing CA bundle":              {CAFile: filepath.Join(dir, "missing.pem")},
+		"CA bundle with no certificates": {CAFile: notPEM},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewTLSConfig(options)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewClient_MutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	writeFile(t, caFile, ca.pem, time.Now())
+	certPEM, keyPEM := ca.issue(t, "insider")
+	writeFile(t, certFile, certPEM, time.Now())
+	writeFile(t, keyFile, keyPEM, time.Now())
+
+	serverCertPEM, serverKeyPEM := ca.issue(t, "carrier")
+	serverCert, err := tls.X509KeyPair(serverCertPEM, serverKeyPEM)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	tlsConfig, err := NewTLSConfig(TLSOptions{CertFile: certFile, KeyFile: keyFile, CAFile: caFile})
+	require.NoError(t, err)
+	config := DefaultConfig()
+	config.RetryCount = 0
+	config.TLS = tlsConfig
+
+	url := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	resp, err := NewClient(config).R().Get(url)
+	require.NoError(t, err)
+	assert.Equal(t, "insider", resp.String())
+
+	// The CA bundle cannot vouch for a server addressed by IP address.
+	_, err = NewClient(config).R().Get(server.URL)
+	assert.Error(t, err)
+
+	// Without the CA bundle the carrier's certificate is not trusted.
+	config.TLS = nil
+	_, err = NewClient(config).R().Get(url)
+	assert.Error(t, err)
+}
+
+func TestNewClient_ReloadsRotatedCABundle(t *testing.T) {
+	oldCA, newCA := newTestCA(t), newTestCA(t)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	issuedAt := time.Now().Add(-time.Hour)
+	writeFile(t, caFile, oldCA.pem, issuedAt)
+
+	tlsConfig, err := NewTLSConfig(TLSOptions{CAFile: caFile})
+	require.NoError(t, err)
+	config := DefaultConfig()
+	config.RetryCount = 0
+	config.TLS = tlsConfig
+	client := NewClient(config)
+
+	oldServer, newServer := newTLSServer(t, oldCA), newTLSServer(t, newCA)
+	_, err = client.R().Get(oldServer)
+	require.NoError(t, err)
+	_, err = client.R().Get(newServer)
+	assert.Error(t, err)
+
+	// After the carrier moves to a new CA, the rotated bundle is trusted from the
+	// next handshake on, while an unreadable bundle keeps the previous one.
+	rotatedAt := issuedAt.Add(time.Minute)
+	writeFile(t, caFile, []byte("partial"), rotatedAt)
+	_, err = client.R().Get(oldServer)
+	require.NoError(t, err)
+
+	writeFile(t, caFile, newCA.pem, rotatedAt.Add(time.Minute))
+	_, err = client.R().Get(newServer)
+	require.NoError(t, err)
+
+	// Open connections were verified when they were made, new ones are verified
+	// against the new bundle only.
+	_, err = NewClient(config).R().Get(oldServer)
+	assert.Error(t, err)
+}
+
+func TestNewClient_Proxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("via proxy to " + r.URL.Host))
+	}))
+	defer proxy.Close()
+
+	config := DefaultConfig()
+	config.RetryCount = 0
+	config.ProxyURL = proxy.URL
+
+	resp, err := NewClient(config).R().Get("http://carrier.example.com/send")
+	require.NoError(t, err)
+	assert.Equal(t, "via proxy to carrier.example.com", resp.String())
+}
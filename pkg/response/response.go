@@ -238,6 +238,23 @@ func SchedulerStopped() *Response {
 	}
 }
 
+func SchedulerRunStarted(data any) *Response {
+	msg := "Processing cycle started"
+	return &Response{
+		Code: http.StatusAccepted,
+		Body: &Body{
+			Status: true,
+			Msg:    msg,
+			Data:   data,
+		},
+		Log: &Log{
+			Level: zapcore.InfoLevel,
+			Msg:   msg,
+			Type:  API,
+		},
+	}
+}
+
 func MessageCreated(data any) *Response {
 	msg := "Message created successfully"
 	return &Response{
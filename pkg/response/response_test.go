@@ -94,6 +94,15 @@ func TestSchedulerStopped(t *testing.T) {
 	assert.NotNil(t, resp.Body.Data)
 }
 
+func TestSchedulerRunStarted(t *testing.T) {
+	data := map[string]string{"mode": "async"}
+	resp := SchedulerRunStarted(data)
+	assert.Equal(t, http.StatusAccepted, resp.Code)
+	assert.True(t, resp.Body.Status)
+	assert.Equal(t, "Processing cycle started", resp.Body.Msg)
+	assert.Equal(t, data, resp.Body.Data)
+}
+
 func TestMessageCreated(t *testing.T) {
 	data := map[string]string{"foo": "bar"}
 	resp := MessageCreated(data)
@@ -0,0 +1,174 @@
+// Package signature signs outbound webhook requests with HMAC-SHA256 and verifies
+// them on the receiving side.
+//
+// A request is signed over its timestamp and body as "<timestamp>.<body>", where
+// the timestamp is in Unix seconds. The timestamp is sent in TimestampHeader and
+// the signature in SignatureHeader as a comma-separated list of "v1=<hex>"
+// entries, one per active secret. A receiver accepts the request when any entry
+// matches any of its own secrets, so a secret can be rotated by adding the new one
+// on both sides before removing the old one.
+package signature
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Ins-Signature"
+	TimestampHeader = "X-Ins-Timestamp"
+
+	// DefaultTolerance is how far a request's timestamp may be from the receiver's
+	// clock when no tolerance is given.
+	DefaultTolerance = 5 * time.Minute
+
+	scheme = "v1"
+)
+
+var (
+	ErrNoSecrets         = errors.New("at least one signing secret is required")
+	ErrMissingSignature  = errors.New("request is not signed")
+	ErrInvalidTimestamp  = errors.New("signature timestamp is invalid")
+	ErrTimestampExpired  = errors.New("signature timestamp is outside the tolerance")
+	ErrSignatureMismatch = errors.New("signature does not match")
+)
+
+// Compute returns the hex-encoded HMAC-SHA256 of "<timestamp>.<body>" under secret.
+func Compute(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Signer signs request bodies with every active secret.
+type Signer struct {
+	secrets []string
+	now     func() time.Time
+}
+
+// NewSigner creates a Signer for the given secrets. Empty secrets are ignored.
+func NewSigner(secrets []string) (*Signer, error) {
+	active := activeSecrets(secrets)
+	if len(active) == 0 {
+		return nil, ErrNoSecrets
+	}
+	return &Signer{secrets: active, now: time.Now}, nil
+}
+
+// Sign returns the timestamp and signature header values for body.
+func (s *Signer) Sign(body []byte) (timestamp, signature string) {
+	timestamp = strconv.FormatInt(s.now().Unix(), 10)
+
+	entries := make([]string, len(s.secrets))
+	for i, secret := range s.secrets {
+		entries[i] = scheme + "=" + Compute(secret, timestamp, body)
+	}
+	return timestamp, strings.Join(entries, ",")
+}
+
+// Headers returns the signature headers to send with body.
+func (s *Signer) Headers(body []byte) map[string]string {
+	timestamp, signature := s.Sign(body)
+	return map[string]string{
+		TimestampHeader: timestamp,
+		SignatureHeader: signature,
+	}
+}
+
+// Verifier checks signed requests against the receiver's active secrets.
+type Verifier struct {
+	secrets   []string
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// NewVerifier creates a Verifier for the given secrets that rejects timestamps
+// more than tolerance away from now, or DefaultTolerance when tolerance is not
+// positive. Empty secrets are ignored.
+func NewVerifier(secrets []string, tolerance time.Duration) (*Verifier, error) {
+	active := activeSecrets(secrets)
+	if len(active) == 0 {
+		return nil, ErrNoSecrets
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	return &Verifier{secrets: active, tolerance: tolerance, now: time.Now}, nil
+}
+
+// Verify checks the timestamp and signature header values against body. The
+// timestamp is checked first, so that a replayed request is rejected even with
+// a valid signature.
+func (v *Verifier) Verify(timestamp, signature string, body []byte) error {
+	if timestamp == "" || signature == "" {
+		return ErrMissingSignature
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	age := v.now().Sub(time.Unix(seconds, 0))
+	if age > v.tolerance || age < -v.tolerance {
+		return ErrTimestampExpired
+	}
+
+	for _, entry := range strings.Split(signature, ",") {
+		version, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || version != scheme {
+			continue
+		}
+		received, err := hex.DecodeString(value)
+		if err != nil {
+			continue
+		}
+		for _, secret := range v.secrets {
+			expected, _ := hex.DecodeString(Compute(secret, timestamp, body))
+			if hmac.Equal(received, expected) {
+				return nil
+			}
+		}
+	}
+	return ErrSignatureMismatch
+}
+
+// VerifyRequest reads the body of r and verifies it against r's signature
+// headers. The body is restored, so that r can still be decoded afterwards, and
+// is also returned.
+func (v *Verifier) VerifyRequest(r *http.Request) ([]byte, error) {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if err := v.Verify(r.Header.Get(TimestampHeader), r.Header.Get(SignatureHeader), body); err != nil {
+		return body, err
+	}
+	return body, nil
+}
+
+func activeSecrets(secrets []string) []string {
+	active := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		if secret != "" {
+			active = append(active, secret)
+		}
+	}
+	return active
+}
@@ -0,0 +1,114 @@
+package signature
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var signedAt = time.Unix(1705312800, 0)
+
+func newTestSigner(t *testing.T, secrets ...string) *Signer {
+	signer, err := NewSigner(secrets)
+	require.NoError(t, err)
+	signer.now = func() time.Time { return signedAt }
+	return signer
+}
+
+func newTestVerifier(t *testing.T, now time.Time, secrets ...string) *Verifier {
+	verifier, err := NewVerifier(secrets, time.Minute)
+	require.NoError(t, err)
+	verifier.now = func() time.Time { return now }
+	return verifier
+}
+
+func TestCompute(t *testing.T) {
+	// HMAC-SHA256 of '1705312800.{"to":"+905551111111"}' under "secret".
+	assert.Equal(t,
+		"b466fea0428800bb42b107449d10d4bafc99f6fbab92e743a63f346006de5902",
+		Compute("secret", "1705312800", []byte(`{"to":"+905551111111"}`)))
+	assert.NotEqual(t,
+		Compute("secret", "1705312800", []byte("body")),
+		Compute("secret", "1705312801", []byte("body")))
+}
+
+func TestNewSigner_RequiresSecret(t *testing.T) {
+	_, err := NewSigner(nil)
+	assert.Equal(t, ErrNoSecrets, err)
+	_, err = NewSigner([]string{""})
+	assert.Equal(t, ErrNoSecrets, err)
+	_, err = NewVerifier(nil, time.Minute)
+	assert.Equal(t, ErrNoSecrets, err)
+}
+
+func TestSigner_SignsWithEverySecret(t *testing.T) {
+	body := []byte(`{"to":"+905551111111","content":"hi"}`)
+
+	timestamp, signature := newTestSigner(t, "old", "", "new").Sign(body)
+	assert.Equal(t, "1705312800", timestamp)
+	assert.Equal(t, "v1="+Compute("old", timestamp, body)+",v1="+Compute("new", timestamp, body), signature)
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	body := []byte(`{"to":"+905551111111","content":"hi"}`)
+	timestamp, signature := newTestSigner(t, "old", "new").Sign(body)
+
+	tests := []struct {
+		name      string
+		secrets   []string
+		now       time.Time
+		timestamp string
+		signature string
+		body      []byte
+		want      error
+	}{
+		{name: "valid", secrets: []string{"new"}, now: signedAt, timestamp: timestamp, signature: signature, body: body},
+		{name: "rotated secret", secrets: []string{"newer", "old"}, now: signedAt, timestamp: timestamp, signature: signature, body: body},
+		{name: "within tolerance", secrets: []string{"new"}, now: signedAt.Add(time.Minute), timestamp: timestamp, signature: signature, body: body},
+		{name: "unknown secret", secrets: []string{"other"}, now: signedAt, timestamp: timestamp, signature: signature, body: body, want: ErrSignatureMismatch},
+		{name: "tampered body", secrets: []string{"new"}, now: signedAt, timestamp: timestamp, signature: signature, body: []byte(`{"to":"+905552222222","content":"hi"}`), want: ErrSignatureMismatch},
+		{name: "replayed later", secrets: []string{"new"}, now: signedAt.Add(2 * time.Minute), timestamp: timestamp, signature: signature, body: body, want: ErrTimestampExpired},
+		{name: "from the future", secrets: []string{"new"}, now: signedAt.Add(-2 * time.Minute), timestamp: timestamp, signature: signature, body: body, want: ErrTimestampExpired},
+		{name: "changed timestamp", secrets: []string{"new"}, now: signedAt, timestamp: "1705312801", signature: signature, body: body, want: ErrSignatureMismatch},
+		{name: "invalid timestamp", secrets: []string{"new"}, now: signedAt, timestamp: "yesterday", signature: signature, body: body, want: ErrInvalidTimestamp},
+		{name: "unknown scheme", secrets: []string{"new"}, now: signedAt, timestamp: timestamp, signature: strings.ReplaceAll(signature, "v1=", "v0="), body: body, want: ErrSignatureMismatch},
+		{name: "missing signature", secrets: []string{"new"}, now: signedAt, timestamp: timestamp, body: body, want: ErrMissingSignature},
+		{name: "missing timestamp", secrets: []string{"new"}, now: signedAt, signature: signature, body: body, want: ErrMissingSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTestVerifier(t, tt.now, tt.secrets...).Verify(tt.timestamp, tt.signature, tt.body)
+			assert.Equal(t, tt.want, err)
+		})
+	}
+}
+
+func TestVerifier_VerifyRequest(t *testing.T) {
+	body := `{"to":"+905551111111","content":"hi"}`
+	signer := newTestSigner(t, "secret")
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	for key, value := range signer.Headers([]byte(body)) {
+		req.Header.Set(key, value)
+	}
+
+	read, err := newTestVerifier(t, signedAt, "secret").VerifyRequest(req)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(read))
+
+	// The body can still be read by the handler.
+	restored, err := io.ReadAll(req.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(restored))
+
+	unsigned := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	_, err = newTestVerifier(t, signedAt, "secret").VerifyRequest(unsigned)
+	assert.Equal(t, ErrMissingSignature, err)
+}